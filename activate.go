@@ -0,0 +1,42 @@
+package httpmock
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+var activated atomic.Bool
+
+// Activate replaces http.DefaultTransport and http.DefaultClient.Transport
+// with NewTransport for code calling http.Get and http.DefaultClient
+// directly, they are restored at Cleanup.
+//
+// The defaults are process wide, so Activate fails the test when another
+// test activated them, and panics when t is a parallel *testing.T, like
+// testing.T.Setenv does.
+func Activate(t TestReporter, calls Calls, opts ...Option) {
+	if setenv, ok := t.(interface{ Setenv(key, value string) }); ok {
+		setenv.Setenv("HTTPMOCK_ACTIVATED", "1")
+	}
+
+	if !activated.CompareAndSwap(false, true) {
+		t.Fatalf("activate, http.DefaultTransport is already replaced by another test")
+
+		return
+	}
+
+	defaultTransport := http.DefaultTransport
+	defaultClientTransport := http.DefaultClient.Transport
+
+	transport := NewTransport(t, calls, nil, opts...)
+
+	http.DefaultTransport = transport
+	http.DefaultClient.Transport = transport
+
+	t.Cleanup(func() {
+		http.DefaultTransport = defaultTransport
+		http.DefaultClient.Transport = defaultClientTransport
+
+		activated.Store(false)
+	})
+}
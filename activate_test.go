@@ -0,0 +1,41 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_Activate(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+
+	t.Run("activated", func(t *testing.T) {
+		Activate(t, SequenceCalls(
+			Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("/legacy")}, Response: Response{StatusCode: http.StatusAccepted}},
+		))
+
+		resp, err := http.Get("http://legacy.local/legacy")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusAccepted {
+			t.Errorf("wrong status code, expected %d, actual %d", http.StatusAccepted, resp.StatusCode)
+		}
+
+		Activate(
+			ExpectFailureTestReporter(
+				nil,
+				[]testReporterCall{
+					{format: "activate, http.DefaultTransport is already replaced by another test"},
+				},
+			)(t),
+			SequenceCalls(),
+		)
+	})
+
+	if http.DefaultTransport != defaultTransport || http.DefaultClient.Transport != nil {
+		t.Errorf("default transports not restored")
+	}
+}
@@ -0,0 +1,276 @@
+package httpmock
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+const batchBoundary = "batch_httpmock"
+
+// Batch returns a call handling a multipart/mixed batch request like Google
+// batch APIs use. Every part holds an application/http request which is
+// compared with the call of the same index, the response is assembled from
+// the responses of the calls in the same format. Input.Body is replaced, the
+// other input fields are compared with the outer request as usual.
+func Batch(input Input, calls ...Call) Call {
+	b := batch{calls: calls}
+
+	input.Body = batchInput(b)
+
+	return Call{
+		Input: input,
+		Response: Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"multipart/mixed; boundary=" + batchBoundary}},
+			Body:       batchResponse(b),
+		},
+	}
+}
+
+type batch struct {
+	calls []Call
+}
+
+type batchPart struct {
+	contentID string
+	request   *http.Request
+}
+
+type batchInput batch
+
+func (b batchInput) Bytes() ([]byte, error) {
+	return nil, fmt.Errorf("batch input body has no bytes, use it with CompareBody")
+}
+
+func (b batchInput) CompareBody(t TestReporter, body []byte) {
+	parts, err := parseBatch(body, sniffBoundary(body))
+	if err != nil {
+		t.Errorf("parse batch request, %s", err)
+
+		return
+	}
+
+	if len(parts) != len(b.calls) {
+		t.Errorf("wrong batch parts count, expected %d, actual %d", len(b.calls), len(parts))
+	}
+
+	for i, part := range parts {
+		if i >= len(b.calls) {
+			break
+		}
+
+		CompareInput(
+			errorfPrefixTestReporter{TestReporter: t, prefix: fmt.Sprintf("batch part %d, ", i+1)},
+			part.request,
+			b.calls[i].Input,
+		)
+	}
+}
+
+type batchResponse batch
+
+func (b batchResponse) Bytes() ([]byte, error) {
+	return b.RequestBytes(nil)
+}
+
+func (b batchResponse) RequestBytes(r *http.Request) ([]byte, error) {
+	if r == nil {
+		return nil, errTemplateNoRequest
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read batch request body, %w", err)
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || params["boundary"] == "" {
+		params = map[string]string{"boundary": sniffBoundary(body)}
+	}
+
+	parts, err := parseBatch(body, params["boundary"])
+	if err != nil {
+		return nil, fmt.Errorf("parse batch request, %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	writer := multipart.NewWriter(&buf)
+
+	err = writer.SetBoundary(batchBoundary)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, part := range parts {
+		if i >= len(b.calls) {
+			break
+		}
+
+		response, err := RenderResponse(part.request, b.calls[i].Response)
+		if err != nil {
+			return nil, fmt.Errorf("batch part %d, %w", i+1, err)
+		}
+
+		header := textproto.MIMEHeader{"Content-Type": {"application/http"}}
+		if part.contentID != "" {
+			header.Set("Content-ID", "<response-"+strings.Trim(part.contentID, "<>")+">")
+		}
+
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+
+		err = writeBatchResponse(partWriter, response)
+		if err != nil {
+			return nil, fmt.Errorf("batch part %d, %w", i+1, err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func parseBatch(body []byte, boundary string) ([]batchPart, error) {
+	if boundary == "" {
+		return nil, fmt.Errorf("multipart boundary not found")
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	var parts []batchPart
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("read part %d, %w", len(parts)+1, err)
+		}
+
+		request, err := readBatchRequest(data)
+		if err != nil {
+			return nil, fmt.Errorf("read part %d request, %w", len(parts)+1, err)
+		}
+
+		parts = append(parts, batchPart{
+			contentID: part.Header.Get("Content-ID"),
+			request:   request,
+		})
+	}
+}
+
+// readBatchRequest reads the nested request, the protocol version of the
+// request line is optional in batch requests.
+func readBatchRequest(data []byte) (*http.Request, error) {
+	data = bytes.TrimLeft(data, "\r\n")
+
+	line, rest, _ := strings.Cut(string(data), "\n")
+	line = strings.TrimRight(line, "\r")
+
+	if len(strings.Fields(line)) == 2 {
+		line += " HTTP/1.1"
+	}
+
+	if !strings.Contains(rest, "\n\n") && !strings.Contains(rest, "\r\n\r\n") {
+		rest += "\r\n"
+	}
+
+	reader := bufio.NewReader(strings.NewReader(line + "\r\n" + rest))
+
+	request, err := http.ReadRequest(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// nested requests often omit Content-Length, the body lasts to the
+	// end of the part then
+	if request.ContentLength <= 0 && len(request.TransferEncoding) == 0 {
+		body, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	return request, nil
+}
+
+func writeBatchResponse(w io.Writer, response Response) error {
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	body := []byte(nil)
+
+	if response.Body != nil {
+		var err error
+
+		body, err = response.Body.Bytes()
+		if err != nil {
+			return fmt.Errorf("get response body bytes, unexpected error: %w", err)
+		}
+	}
+
+	header := response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	header.Set("Content-Length", fmt.Sprint(len(body)))
+
+	_, err := fmt.Fprintf(w, "HTTP/1.1 %d %s\r\n", statusCode, http.StatusText(statusCode))
+	if err != nil {
+		return err
+	}
+
+	err = header.Write(w)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "\r\n%s", body)
+
+	return err
+}
+
+// sniffBoundary takes the boundary from the first delimiter line of the body.
+func sniffBoundary(body []byte) string {
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+
+		if boundary, ok := strings.CutPrefix(line, "--"); ok && boundary != "" {
+			return boundary
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,135 @@
+package httpmock
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const batchRequestBody = "--batch_foobarbaz\r\n" +
+	"Content-Type: application/http\r\n" +
+	"Content-ID: <item1:12930812@barnyard.example.com>\r\n" +
+	"\r\n" +
+	"GET /farm/v1/animals/pony\r\n" +
+	"\r\n" +
+	"--batch_foobarbaz\r\n" +
+	"Content-Type: application/http\r\n" +
+	"Content-ID: <item2:12930812@barnyard.example.com>\r\n" +
+	"\r\n" +
+	"PUT /farm/v1/animals/sheep HTTP/1.1\r\n" +
+	"Content-Type: application/json\r\n" +
+	"\r\n" +
+	`{"animalName":"sheep"}` + "\r\n" +
+	"--batch_foobarbaz--\r\n"
+
+func batchCall() Call {
+	return Batch(
+		Input{
+			Method: http.MethodPost,
+			URL:    mustParseURL("/batch/farm/v1"),
+		},
+		Call{
+			Input: Input{
+				Method: http.MethodGet,
+				URL:    mustParseURL("/farm/v1/animals/pony"),
+			},
+			Response: Response{
+				Header: http.Header{"Content-Type": {"application/json"}},
+				Body:   RawBody(`{"kind":"farm#animal"}`),
+			},
+		},
+		Call{
+			Input: Input{
+				Method: http.MethodPut,
+				URL:    mustParseURL("/farm/v1/animals/sheep"),
+				Body:   RawBody(`{"animalName":"sheep"}`),
+			},
+			Response: Response{
+				StatusCode: http.StatusCreated,
+				Body:       TemplateBody(`{{request.pathSegments.[3]}} created`),
+			},
+		},
+	)
+}
+
+func doBatch(t *testing.T, client *http.Client, body string) *multipart.Reader {
+	t.Helper()
+
+	resp, err := client.Post("https://www.googleapis.com/batch/farm/v1", "multipart/mixed; boundary=batch_foobarbaz", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { resp.Body.Close() })
+
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("parse response Content-Type, %s", err)
+	}
+
+	return multipart.NewReader(resp.Body, params["boundary"])
+}
+
+func Test_Batch(t *testing.T) {
+	client := &http.Client{Transport: NewTransport(t, SequenceCalls(batchCall()), nil)}
+
+	reader := doBatch(t, client, batchRequestBody)
+
+	expected := []struct {
+		contentID string
+		response  string
+	}{
+		{
+			contentID: "<response-item1:12930812@barnyard.example.com>",
+			response:  "HTTP/1.1 200 OK\r\nContent-Length: 22\r\nContent-Type: application/json\r\n\r\n" + `{"kind":"farm#animal"}`,
+		},
+		{
+			contentID: "<response-item2:12930812@barnyard.example.com>",
+			response:  "HTTP/1.1 201 Created\r\nContent-Length: 13\r\n\r\nsheep created",
+		},
+	}
+
+	for i, exp := range expected {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("read part %d, %s", i+1, err)
+		}
+
+		data, _ := io.ReadAll(part)
+
+		if contentID := part.Header.Get("Content-ID"); contentID != exp.contentID {
+			t.Errorf("part %d, wrong Content-ID, expected %s, actual %s", i+1, exp.contentID, contentID)
+		}
+
+		if string(data) != exp.response {
+			t.Errorf("part %d, wrong response, expected %q, actual %q", i+1, exp.response, data)
+		}
+	}
+
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("expect two parts, next part error %v", err)
+	}
+}
+
+func Test_Batch_Mismatch(t *testing.T) {
+	tr := ExpectFailureTestReporter(
+		[]testReporterCall{
+			{
+				format: "1 call, wrong batch parts count, expected %d, actual %d",
+				args:   []any{2, 1},
+			},
+			{
+				format: "1 call, batch part 1, wrong url.Path, expected %s, actual %s",
+				args:   []any{"/farm/v1/animals/pony", "/farm/v1/animals/cow"},
+			},
+		},
+		nil,
+	)(t)
+
+	client := &http.Client{Transport: NewTransport(tr, SequenceCalls(batchCall()), nil)}
+
+	doBatch(t, client, "--batch_foobarbaz\r\nContent-Type: application/http\r\n\r\nGET /farm/v1/animals/cow\r\n--batch_foobarbaz--\r\n")
+}
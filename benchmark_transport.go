@@ -0,0 +1,108 @@
+package httpmock
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+type encodedCall struct {
+	doError    error
+	delay      time.Duration
+	statusCode int
+	status     string
+	header     http.Header
+	body       []byte
+}
+
+type benchmarkTransport struct {
+	calledTimes atomic.Uint64
+	calls       []encodedCall
+}
+
+// NewBenchmarkTransport serves calls repeatedly like StaticCalls without
+// comparing inputs and reporting, so it can back client benchmarks without
+// dominating the profile. Responses are encoded once, request rendered
+// bodies and templates are rendered without a request, webhooks are not
+// sent. Call.DoError and Call.Delay are kept.
+func NewBenchmarkTransport(t TestReporter, calls ...Call) http.RoundTripper {
+	encoded := make([]encodedCall, 0, len(calls))
+
+	for i, call := range calls {
+		response := call.Response
+
+		body := response.Body
+		if body == nil {
+			body = RawBody{}
+		}
+
+		bytes, err := body.Bytes()
+		if err != nil {
+			t.Fatalf("encode call %d response body, %s", i+1, err)
+
+			return nil
+		}
+
+		statusCode := response.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+
+		header := response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+
+		header.Set("Content-Length", strconv.Itoa(len(bytes)))
+
+		encoded = append(encoded, encodedCall{
+			doError:    call.DoError,
+			delay:      call.Delay,
+			statusCode: statusCode,
+			status:     strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+			header:     header,
+			body:       bytes,
+		})
+	}
+
+	if len(encoded) == 0 {
+		t.Fatalf("no calls for benchmark transport")
+
+		return nil
+	}
+
+	return &benchmarkTransport{calls: encoded}
+}
+
+func (b *benchmarkTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body != nil {
+		r.Body.Close()
+	}
+
+	calledTimes := b.calledTimes.Add(1)
+
+	call := &b.calls[(calledTimes-1)%uint64(len(b.calls))]
+
+	if call.doError != nil {
+		return nil, call.doError
+	}
+
+	if call.delay > 0 && !sleepContext(r.Context(), call.delay) {
+		return nil, r.Context().Err()
+	}
+
+	return &http.Response{
+		Status:        call.status,
+		StatusCode:    call.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        call.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(call.body)),
+		ContentLength: int64(len(call.body)),
+		Request:       r,
+	}, nil
+}
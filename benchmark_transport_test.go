@@ -0,0 +1,74 @@
+package httpmock
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func Test_BenchmarkTransport(t *testing.T) {
+	doErr := errors.New("connection refused")
+
+	transport := NewBenchmarkTransport(t,
+		Call{Response: Resp(http.StatusCreated).JSON(map[string]int{"id": 1}).Response()},
+		Call{DoError: doErr},
+	)
+
+	client := &http.Client{Transport: transport}
+
+	for range 2 {
+		resp, err := client.Post("http://localhost/items", "application/json", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated || string(body) != `{"id":1}` {
+			t.Errorf("wrong response, status %d, body %s", resp.StatusCode, body)
+		}
+
+		if resp.ContentLength != 8 || resp.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("wrong response header %v, content length %d", resp.Header, resp.ContentLength)
+		}
+
+		_, err = client.Get("http://localhost/items")
+		if !errors.Is(err, doErr) {
+			t.Errorf("wrong error, expected %s, actual %v", doErr, err)
+		}
+	}
+}
+
+func benchmarkClient(b *testing.B, transport http.RoundTripper) {
+	client := &http.Client{Transport: transport}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		resp, err := client.Get("http://localhost/items")
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+func Benchmark_Transport(b *testing.B) {
+	call := Call{
+		Input:    Input{Method: http.MethodGet},
+		Response: Resp(http.StatusOK).JSON(map[string]string{"name": "item"}).Response(),
+	}
+
+	b.Run("NewTransport", func(b *testing.B) {
+		benchmarkClient(b, NewTransport(b, StaticCalls(call), nil))
+	})
+
+	b.Run("NewBenchmarkTransport", func(b *testing.B) {
+		benchmarkClient(b, NewBenchmarkTransport(b, call))
+	})
+}
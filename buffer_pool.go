@@ -0,0 +1,56 @@
+package httpmock
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxPooledBufferSize keeps rare huge bodies from pinning memory in the pool.
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// readPooled reads r into a pooled buffer, the buffer must be returned with
+// putBuffer once its bytes are not used anymore.
+func readPooled(r io.Reader) (*bytes.Buffer, error) {
+	buf := getBuffer()
+
+	_, err := buf.ReadFrom(r)
+
+	return buf, err
+}
+
+// WithMaxBodySize limits request bodies to size bytes, reading more fails
+// with *http.MaxBytesError and the comparison reports it.
+func WithMaxBodySize(size int64) Option {
+	return func(o *options) {
+		o.maxBodySize = size
+	}
+}
+
+func limitRequestBody(w http.ResponseWriter, r *http.Request, size int64) {
+	if size <= 0 || r.Body == nil {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, size)
+}
@@ -0,0 +1,66 @@
+package httpmock
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func Test_WithMaxBodySize(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "1 call, read body from request, %s", args: []any{&http.MaxBytesError{Limit: 4}}},
+			},
+			nil,
+		)(t),
+		SequenceCalls(
+			Call{Input: Input{Method: http.MethodPost, Body: RawBody("abcdef")}},
+			Call{Input: Input{Method: http.MethodPost, Body: RawBody("abcd")}},
+		),
+		nil,
+		WithMaxBodySize(4),
+	)
+
+	client := &http.Client{Transport: transport}
+
+	for _, body := range []string{"abcdef", "abcd"} {
+		resp, err := client.Post("http://localhost/items", "text/plain", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+}
+
+func Test_CompareBody_PooledBuffers(t *testing.T) {
+	var wg sync.WaitGroup
+
+	for i := range 50 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			body := strings.Repeat("x", i*100)
+
+			CompareBody(ExpectSuccessTestReporter(t), strings.NewReader(body), RawBody(body))
+		}()
+	}
+
+	wg.Wait()
+}
+
+func Test_PutBuffer_DropsHugeBuffers(t *testing.T) {
+	buf := getBuffer()
+	buf.Grow(maxPooledBufferSize + 1)
+	buf.WriteString("data")
+
+	putBuffer(buf)
+
+	if buf.Len() != 4 {
+		t.Errorf("huge buffer must not be reset and pooled")
+	}
+}
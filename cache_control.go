@@ -0,0 +1,122 @@
+package httpmock
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type cachedResource struct {
+	servedAt   time.Time
+	modifiedAt time.Time
+	etag       string
+}
+
+type cacheControl struct {
+	maxAge time.Duration
+
+	mu        sync.Mutex
+	resources map[string]cachedResource
+}
+
+// CacheControl serves GET responses with Cache-Control: max-age and an
+// ETag and checks the client cache: a resource requested again within
+// max-age is reported, after max-age the request must revalidate with
+// If-None-Match or If-Modified-Since and is answered with 304 Not Modified
+// once its input is compared. Time is taken from the WithClock clock when
+// set. handleCall serves the calls, nil means HandleCallCompareInput.
+func CacheControl(maxAge time.Duration, handleCall HandleCall) HandleCall {
+	if handleCall == nil {
+		handleCall = HandleCallCompareInput
+	}
+
+	c := &cacheControl{
+		maxAge:    maxAge,
+		resources: make(map[string]cachedResource),
+	}
+
+	return func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		if r.Method != http.MethodGet {
+			handleCall(t, w, r, call)
+
+			return
+		}
+
+		now := time.Now
+		if clock := clockFrom(r.Context()); clock != nil {
+			now = clock
+		}
+
+		resource, cached := c.serve(r.URL.String(), now())
+
+		if cached && c.revalidated(t, r, resource, now()) {
+			CompareInput(t, r, call.Input)
+
+			c.writeHeader(w, resource)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		c.writeHeader(w, resource)
+		w.Header().Set("Last-Modified", resource.modifiedAt.UTC().Format(http.TimeFormat))
+
+		handleCall(t, w, r, call)
+	}
+}
+
+// serve returns the resource as it was served before and whether it was,
+// the resource is stored as served now, so its freshness starts again.
+func (c *cacheControl) serve(key string, now time.Time) (cachedResource, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	resource, ok := c.resources[key]
+	if !ok {
+		resource = cachedResource{
+			modifiedAt: now,
+			etag:       `"` + strconv.Itoa(len(c.resources)+1) + `"`,
+		}
+	}
+
+	previous := resource
+
+	resource.servedAt = now
+	c.resources[key] = resource
+
+	return previous, ok
+}
+
+func (c *cacheControl) revalidated(t TestReporter, r *http.Request, resource cachedResource, now time.Time) bool {
+	age := now.Sub(resource.servedAt)
+
+	if age < c.maxAge {
+		t.Errorf(
+			"cached resource %s requested again within its freshness lifetime, age %s, max-age %s",
+			r.URL.String(),
+			age,
+			c.maxAge,
+		)
+
+		return false
+	}
+
+	if r.Header.Get("If-None-Match") == resource.etag {
+		return true
+	}
+
+	modifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err == nil && !resource.modifiedAt.Truncate(time.Second).After(modifiedSince) {
+		return true
+	}
+
+	t.Errorf("stale resource %s requested without revalidation, expected If-None-Match %s or If-Modified-Since", r.URL.String(), resource.etag)
+
+	return false
+}
+
+func (c *cacheControl) writeHeader(w http.ResponseWriter, resource cachedResource) {
+	w.Header().Set("Cache-Control", "max-age="+strconv.FormatInt(int64(c.maxAge/time.Second), 10))
+	w.Header().Set("ETag", resource.etag)
+}
@@ -0,0 +1,77 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_CacheControl(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{
+					format: "2 call, cached resource %s requested again within its freshness lifetime, age %s, max-age %s",
+					args:   []any{"http://localhost/items", 30 * time.Second, time.Minute},
+				},
+				{
+					format: "4 call, stale resource %s requested without revalidation, expected If-None-Match %s or If-Modified-Since",
+					args:   []any{"http://localhost/items", `"1"`},
+				},
+			},
+			nil,
+		)(t),
+		StaticCalls(Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{StatusCode: http.StatusOK, Body: RawBody("items")},
+		}),
+		CacheControl(time.Minute, nil),
+		WithClock(func() time.Time { return now }),
+	)
+
+	get := func(header http.Header, expectedStatusCode int) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/items", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header = header
+
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		CompareStatusCode(t, resp.StatusCode, expectedStatusCode)
+
+		return resp
+	}
+
+	resp := get(http.Header{}, http.StatusOK)
+
+	CompareHeader(t, resp.Header, http.Header{
+		"Cache-Control": {"max-age=60"},
+		"Etag":          {`"1"`},
+		"Last-Modified": {"Fri, 01 Mar 2024 12:00:00 GMT"},
+	})
+
+	now = now.Add(30 * time.Second)
+
+	get(http.Header{}, http.StatusOK)
+
+	now = now.Add(time.Minute)
+
+	get(http.Header{"If-None-Match": {`"1"`}}, http.StatusNotModified)
+
+	now = now.Add(time.Minute)
+
+	get(http.Header{}, http.StatusOK)
+
+	now = now.Add(time.Minute)
+
+	get(http.Header{"If-Modified-Since": {"Fri, 01 Mar 2024 12:00:00 GMT"}}, http.StatusNotModified)
+}
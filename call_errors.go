@@ -0,0 +1,143 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Errors RoundTrip returns with WithCallErrors wrap ErrUnexpectedCall and
+// one of the errors describing the failure, so callers branch on them with
+// errors.Is. Failures reported by custom HandleCall functions with the
+// reporter they got wrap ErrUnexpectedCall only.
+var (
+	ErrUnexpectedCall = errors.New("unexpected call")
+	// ErrNoCallsLeft is returned for a request beyond the expected calls.
+	ErrNoCallsLeft = errors.New("no expected calls left")
+	// ErrInputMismatch is returned when the request did not match the call.
+	ErrInputMismatch = errors.New("input mismatch")
+	// ErrBodyRead is returned when the request body could not be read, e.g.
+	// it exceeds WithMaxBodySize.
+	ErrBodyRead = errors.New("read request body")
+	// ErrResponseWrite is returned when the call response could not be
+	// rendered or written, e.g. a DynamicBody failed.
+	ErrResponseWrite = errors.New("write response")
+)
+
+// WithCallErrors makes RoundTrip return an error wrapping ErrUnexpectedCall
+// when no expected calls are left or the call reported a failure, in
+// addition to reporting it. Failures are reported with Errorf instead of
+// Fatalf: testing.T.Fatalf exits the goroutine it is called from, a client
+// goroutine would never get a response. The option affects NewTransport and
+// NewClient only, servers answer with a status code.
+func WithCallErrors() Option {
+	return func(o *options) {
+		o.callErrors = true
+	}
+}
+
+// callErrorReporter keeps the first failure reported for the call, Fatalf
+// is reported with Errorf, so RoundTrip returns the error.
+type callErrorReporter struct {
+	TestReporter
+
+	mu      sync.Mutex
+	failure string
+	kind    error
+}
+
+func (c *callErrorReporter) Errorf(format string, args ...any) {
+	if h, ok := c.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
+	c.fail(nil, format, args)
+	c.TestReporter.Errorf(format, args...)
+}
+
+func (c *callErrorReporter) errorfKind(kind error, format string, args ...any) {
+	if h, ok := c.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
+	c.fail(kind, format, args)
+	c.TestReporter.Errorf(format, args...)
+}
+
+func (c *callErrorReporter) Fatalf(format string, args ...any) {
+	if h, ok := c.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
+	c.fail(nil, format, args)
+	c.TestReporter.Errorf(format, args...)
+}
+
+func (c *callErrorReporter) fail(kind error, format string, args []any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failure == "" {
+		c.failure = fmt.Sprintf(format, args...)
+		c.kind = kind
+	}
+}
+
+func (c *callErrorReporter) err(calledTimes int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failure == "" {
+		return nil
+	}
+
+	if c.kind == nil {
+		return fmt.Errorf("%w, call %d, %s", ErrUnexpectedCall, calledTimes, c.failure)
+	}
+
+	return fmt.Errorf("%w, %w, call %d, %s", ErrUnexpectedCall, c.kind, calledTimes, c.failure)
+}
+
+// kindReporter reports failures of a kind, the kind is wrapped into the
+// error RoundTrip returns with WithCallErrors.
+type kindReporter interface {
+	errorfKind(kind error, format string, args ...any)
+}
+
+// kindTestReporter reports every failure with the kind.
+type kindTestReporter struct {
+	TestReporter
+	report kindReporter
+	kind   error
+}
+
+// withFailureKind makes failures reported to t be of the kind when t keeps
+// kinds, t is returned as is otherwise.
+func withFailureKind(t TestReporter, kind error) TestReporter {
+	report, ok := t.(kindReporter)
+	if !ok {
+		return t
+	}
+
+	return kindTestReporter{TestReporter: t, report: report, kind: kind}
+}
+
+func (k kindTestReporter) Errorf(format string, args ...any) {
+	if h, ok := k.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
+	k.report.errorfKind(k.kind, format, args...)
+}
+
+func (k kindTestReporter) Fatalf(format string, args ...any) {
+	if h, ok := k.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
+	k.report.errorfKind(k.kind, format, args...)
+}
+
+func (k kindTestReporter) errorfKind(kind error, format string, args ...any) {
+	k.report.errorfKind(kind, format, args...)
+}
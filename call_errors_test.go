@@ -0,0 +1,187 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_WithCallErrors_NoCallsLeft(t *testing.T) {
+	client := &http.Client{
+		Transport: NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "2 call, no expected calls left"},
+					{format: "assert handler calls, not all calls were handled"},
+				},
+				nil,
+			)(t),
+			SequenceCalls(Call{Input: Input{Method: http.MethodGet}}),
+			nil,
+			WithCallErrors(),
+		),
+	}
+
+	resp, err := client.Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	_, err = client.Get("http://localhost/items")
+	if !errors.Is(err, ErrUnexpectedCall) || !errors.Is(err, ErrNoCallsLeft) {
+		t.Fatalf("expected ErrUnexpectedCall and ErrNoCallsLeft, actual %v", err)
+	}
+
+	const expected = "Get \"http://localhost/items\": unexpected call, no expected calls left, call 2, GET http://localhost/items"
+	if err.Error() != expected {
+		t.Errorf("wrong error, expected %s, actual %s", expected, err)
+	}
+}
+
+func Test_WithCallErrors_InputMismatch(t *testing.T) {
+	client := &http.Client{
+		Transport: NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{{format: "1 call, wrong r.Method, expected %s, actual %s", args: []any{"POST", "GET"}}},
+				nil,
+			)(t),
+			SequenceCalls(Call{Input: Input{Method: http.MethodPost}}),
+			nil,
+			WithCallErrors(),
+		),
+	}
+
+	_, err := client.Get("http://localhost/items")
+	if !errors.Is(err, ErrUnexpectedCall) || !errors.Is(err, ErrInputMismatch) {
+		t.Fatalf("expected ErrUnexpectedCall and ErrInputMismatch, actual %v", err)
+	}
+
+	const expected = "Get \"http://localhost/items\": unexpected call, input mismatch, call 1, wrong r.Method, expected POST, actual GET"
+	if err.Error() != expected {
+		t.Errorf("wrong error, expected %s, actual %s", expected, err)
+	}
+}
+
+func Test_WithCallErrors_BodyRead(t *testing.T) {
+	reporter := &testReporterMock{t: t}
+
+	client := &http.Client{
+		Transport: NewTransport(
+			reporter,
+			SequenceCalls(Call{Input: Input{Method: http.MethodPost, Body: RawBody("0123456789")}}),
+			nil,
+			WithCallErrors(),
+			WithMaxBodySize(4),
+		),
+	}
+
+	_, err := client.Post("http://localhost/items", "text/plain", strings.NewReader("0123456789"))
+	if !errors.Is(err, ErrBodyRead) || errors.Is(err, ErrInputMismatch) {
+		t.Fatalf("expected ErrBodyRead, actual %v", err)
+	}
+
+	if len(reporter.errorfCalls) != 1 || reporter.errorfCalls[0].format != "1 call, read body from request, %s" {
+		t.Errorf("wrong reported errors, %v", reporter.errorfCalls)
+	}
+}
+
+func Test_WithCallErrors_ResponseWrite(t *testing.T) {
+	reporter := &testReporterMock{t: t}
+
+	client := &http.Client{
+		Transport: NewTransport(
+			reporter,
+			SequenceCalls(Call{
+				Input: Input{Method: http.MethodGet},
+				Response: Response{
+					Body: DynamicBody(func() ([]byte, error) { return nil, errors.New("boom") }),
+				},
+			}),
+			nil,
+			WithCallErrors(),
+		),
+	}
+
+	_, err := client.Get("http://localhost/items")
+	if !errors.Is(err, ErrResponseWrite) || errors.Is(err, ErrInputMismatch) {
+		t.Fatalf("expected ErrResponseWrite, actual %v", err)
+	}
+
+	if len(reporter.errorfCalls) != 1 {
+		t.Errorf("wrong reported errors, %v", reporter.errorfCalls)
+	}
+}
+
+func Test_WithCallErrors_Matched(t *testing.T) {
+	client := &http.Client{
+		Transport: NewTransport(t, SequenceCalls(Call{Input: Input{Method: http.MethodGet}}), nil, WithCallErrors()),
+	}
+
+	resp, err := client.Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+// errorsRecorder records Errorf and leaves Fatalf to the real testing.T,
+// which exits the calling goroutine.
+type errorsRecorder struct {
+	*testing.T
+
+	mu     sync.Mutex
+	errors []string
+}
+
+func (e *errorsRecorder) Errorf(format string, args ...any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.errors = append(e.errors, fmt.Sprintf(format, args...))
+}
+
+func Test_WithCallErrors_ClientGoroutine(t *testing.T) {
+	reporter := &errorsRecorder{T: t}
+
+	client := &http.Client{
+		Transport: NewTransport(
+			reporter,
+			SequenceCalls(Call{Input: Input{Method: http.MethodGet}}),
+			func(t TestReporter, _ http.ResponseWriter, _ *http.Request, _ Call) {
+				t.Fatalf("handler failed")
+			},
+			WithCallErrors(),
+		),
+	}
+
+	for _, expected := range []error{ErrUnexpectedCall, ErrNoCallsLeft} {
+		done := make(chan error)
+
+		go func() {
+			_, err := client.Get("http://localhost/items")
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			if !errors.Is(err, expected) {
+				t.Errorf("expected %s, actual %v", expected, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("client goroutine exited without an error, expected %s", expected)
+		}
+	}
+
+	expected := []string{"1 call, handler failed", "2 call, no expected calls left"}
+	if !slices.Equal(reporter.errors, expected) {
+		t.Errorf("wrong reported errors, expected %v, actual %v", expected, reporter.errors)
+	}
+}
@@ -0,0 +1,36 @@
+package httpmock
+
+import (
+	"math/rand/v2"
+)
+
+// Selection returns the index of the call served for the call number,
+// starting from 1, among count calls. Transports and servers count calls
+// the same way, so a selection serves them the same calls.
+type Selection func(calledTimes, count int) int
+
+// RoundRobin serves the calls in order and starts over after the last one:
+// with calls a, b, c the requests get a, b, c, a, b, ...
+func RoundRobin() Selection {
+	return func(calledTimes, count int) int {
+		return (calledTimes - 1) % count
+	}
+}
+
+// Sticky serves the calls in order and keeps serving the last one: with
+// calls a, b, c the requests get a, b, c, c, c, ..., e.g. for a backend
+// which settles after a few transient answers.
+func Sticky() Selection {
+	return func(calledTimes, count int) int {
+		return min(calledTimes, count) - 1
+	}
+}
+
+// Random serves a call picked at random, the pick depends only on the seed
+// and the call number, so sequential requests get the same calls with the
+// same seed.
+func Random(seed uint64) Selection {
+	return func(calledTimes, count int) int {
+		return rand.New(rand.NewPCG(seed, uint64(calledTimes))).IntN(count)
+	}
+}
@@ -0,0 +1,101 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func selectionCalls(count int) []Call {
+	calls := make([]Call, count)
+
+	for i := range calls {
+		calls[i] = Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{Body: RawBody(strconv.Itoa(i))},
+		}
+	}
+
+	return calls
+}
+
+func servedIndexes(t *testing.T, client *http.Client, url string, requests int) []int {
+	t.Helper()
+
+	indexes := make([]int, 0, requests)
+
+	for range requests {
+		resp, err := client.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		index, _ := strconv.Atoi(string(body))
+		indexes = append(indexes, index)
+	}
+
+	return indexes
+}
+
+func Test_SelectCalls(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection Selection
+		expected  []int
+	}{
+		{name: "round robin", selection: RoundRobin(), expected: []int{0, 1, 2, 0, 1, 2, 0}},
+		{name: "sticky", selection: Sticky(), expected: []int{0, 1, 2, 2, 2, 2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &http.Client{Transport: NewTransport(t, SelectCalls(tt.selection, selectionCalls(3)...), nil)}
+			server := NewServer(t, SelectCalls(tt.selection, selectionCalls(3)...), nil)
+
+			if actual := servedIndexes(t, transport, "http://localhost", 7); !slices.Equal(actual, tt.expected) {
+				t.Errorf("wrong transport calls, expected %v, actual %v", tt.expected, actual)
+			}
+
+			if actual := servedIndexes(t, server.Client(), server.URL, 7); !slices.Equal(actual, tt.expected) {
+				t.Errorf("wrong server calls, expected %v, actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_StaticCalls_WrapsToFirst(t *testing.T) {
+	client := &http.Client{Transport: NewTransport(t, StaticCalls(selectionCalls(2)...), nil)}
+
+	expected := []int{0, 1, 0, 1, 0}
+
+	if actual := servedIndexes(t, client, "http://localhost", 5); !slices.Equal(actual, expected) {
+		t.Errorf("wrong calls, expected %v, actual %v", expected, actual)
+	}
+}
+
+func Test_Random(t *testing.T) {
+	first := &http.Client{Transport: NewTransport(t, SelectCalls(Random(7), selectionCalls(4)...), nil)}
+	second := &http.Client{Transport: NewTransport(t, SelectCalls(Random(7), selectionCalls(4)...), nil)}
+
+	firstIndexes := servedIndexes(t, first, "http://localhost", 20)
+	secondIndexes := servedIndexes(t, second, "http://localhost", 20)
+
+	if !slices.Equal(firstIndexes, secondIndexes) {
+		t.Errorf("same seed served different calls, %v and %v", firstIndexes, secondIndexes)
+	}
+
+	served := make(map[int]bool)
+
+	for _, index := range firstIndexes {
+		served[index] = true
+	}
+
+	if len(served) < 2 {
+		t.Errorf("random selection served one call only, %v", firstIndexes)
+	}
+}
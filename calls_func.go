@@ -0,0 +1,47 @@
+package httpmock
+
+import (
+	"net/http"
+)
+
+// requestCalls is implemented by Calls which pick the call by the request,
+// transports and servers pass it instead of calling Call.
+type requestCalls interface {
+	callRequest(calledTimes int, r *http.Request) (Call, bool)
+}
+
+// callFor returns the call for the call number and the request.
+func callFor(calls Calls, calledTimes int, r *http.Request) (Call, bool) {
+	if calls, ok := calls.(requestCalls); ok {
+		return calls.callRequest(calledTimes, r)
+	}
+
+	return calls.Call(calledTimes)
+}
+
+type funcCalls struct {
+	expected int
+	fn       func(calledTimes int, r *http.Request) (Call, bool)
+}
+
+// CallsFunc computes the call for every request with fn, e.g. from a model
+// of the backend, the input is compared and failures are reported as for
+// other calls. Requests fn returns false for are reported as no expected
+// calls left. Done reports whether at least expected calls were made, so
+// Cleanup reports missing ones, 0 accepts any count. r is nil when Call is
+// called directly instead of by a transport or a server.
+func CallsFunc(expected int, fn func(calledTimes int, r *http.Request) (Call, bool)) Calls {
+	return funcCalls{expected: expected, fn: fn}
+}
+
+func (f funcCalls) Call(calledTimes int) (Call, bool) {
+	return f.fn(calledTimes, nil)
+}
+
+func (f funcCalls) callRequest(calledTimes int, r *http.Request) (Call, bool) {
+	return f.fn(calledTimes, r)
+}
+
+func (f funcCalls) Done(calledTimes int) bool {
+	return calledTimes >= f.expected
+}
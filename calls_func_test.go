@@ -0,0 +1,96 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func Test_CallsFunc(t *testing.T) {
+	calls := CallsFunc(2, func(calledTimes int, r *http.Request) (Call, bool) {
+		if calledTimes > 2 {
+			return Call{}, false
+		}
+
+		return Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{Body: RawBody(r.URL.Path)},
+		}, true
+	})
+
+	server := NewServer(
+		ExpectFailureTestReporter([]testReporterCall{{format: "3 call, no expected calls left"}}, nil)(t),
+		FailOnCalls(calls, []int{2}, FaultStatus(http.StatusServiceUnavailable, nil)),
+		nil,
+	)
+
+	for _, tt := range []struct {
+		path       string
+		statusCode int
+		body       string
+	}{
+		{path: "/users", statusCode: http.StatusOK, body: "/users"},
+		{path: "/users", statusCode: http.StatusServiceUnavailable},
+		{path: "/items", statusCode: http.StatusNotImplemented},
+	} {
+		resp, err := http.Get(server.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.statusCode {
+			t.Errorf("%s, wrong status code, expected %d, actual %d", tt.path, tt.statusCode, resp.StatusCode)
+		}
+
+		if tt.body != "" && string(body) != tt.body {
+			t.Errorf("%s, wrong body, expected %s, actual %s", tt.path, tt.body, body)
+		}
+	}
+}
+
+func Test_CallsFunc_Transport(t *testing.T) {
+	client := &http.Client{
+		Transport: NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{{format: "1 call, wrong r.Method, expected %s, actual %s", args: []any{"POST", "GET"}}},
+				nil,
+			)(t),
+			CallsFunc(1, func(_ int, r *http.Request) (Call, bool) {
+				return Call{Input: Input{Method: http.MethodPost, URL: r.URL}}, true
+			}),
+			nil,
+		),
+	}
+
+	resp, err := client.Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_CallsFunc_MissingCalls(t *testing.T) {
+	client := &http.Client{
+		Transport: NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{{format: "assert handler calls, not all calls were handled"}},
+				nil,
+			)(t),
+			CallsFunc(2, func(int, *http.Request) (Call, bool) {
+				return Call{Input: Input{Method: http.MethodGet}}, true
+			}),
+			nil,
+		),
+	}
+
+	resp, err := client.Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
@@ -0,0 +1,233 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"unicode/utf8"
+)
+
+// Exchange is a recorded request and response pair.
+type Exchange struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string       `json:"method"`
+	URL    string       `json:"url"`
+	Header http.Header  `json:"header,omitempty"`
+	Body   RecordedBody `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int          `json:"status"`
+	Header     http.Header  `json:"header,omitempty"`
+	Body       RecordedBody `json:"body,omitempty"`
+}
+
+// RecordedBody is stored as a JSON string when it is valid UTF-8 and as
+// {"base64": "..."} otherwise.
+type RecordedBody []byte
+
+func (b RecordedBody) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+
+	return json.Marshal(struct {
+		Base64 string `json:"base64"`
+	}{
+		Base64: base64.StdEncoding.EncodeToString(b),
+	})
+}
+
+func (b *RecordedBody) UnmarshalJSON(data []byte) error {
+	var text string
+
+	err := json.Unmarshal(data, &text)
+	if err == nil {
+		*b = RecordedBody(text)
+
+		return nil
+	}
+
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+
+	err = json.Unmarshal(data, &encoded)
+	if err != nil {
+		return fmt.Errorf("unmarshal recorded body, %w", err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return fmt.Errorf("decode recorded body, %w", err)
+	}
+
+	*b = decoded
+
+	return nil
+}
+
+// recordedHopHeaders describe the recorded connection and body framing, they
+// are dropped from replayed responses, the mock frames the body it serves.
+var recordedHopHeaders = []string{
+	"Content-Length",
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// Call converts the exchange to a call, request headers are not compared
+// because recorded clients send a lot of volatile ones. Content-Length and
+// hop-by-hop response headers are dropped, so replay rules may change the
+// body length.
+func (e Exchange) Call() (Call, error) {
+	u, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return Call{}, fmt.Errorf("parse recorded url, %w", err)
+	}
+
+	return Call{
+		Input: Input{
+			Method: e.Request.Method,
+			URL:    u,
+			Body:   RawBody(e.Request.Body),
+		},
+		Response: Response{
+			StatusCode: e.Response.StatusCode,
+			Header:     replayedHeader(e.Response.Header),
+			Body:       RawBody(e.Response.Body),
+		},
+	}, nil
+}
+
+func replayedHeader(recorded http.Header) http.Header {
+	header := recorded.Clone()
+
+	for _, key := range recordedHopHeaders {
+		header.Del(key)
+	}
+
+	return header
+}
+
+// Cassette is a concurrency safe list of exchanges stored as JSON.
+type Cassette struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+type cassetteFile struct {
+	Exchanges []Exchange `json:"exchanges"`
+}
+
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette, %w", err)
+	}
+
+	var file cassetteFile
+
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal cassette %s, %w", path, err)
+	}
+
+	return &Cassette{exchanges: file.Exchanges}, nil
+}
+
+func (c *Cassette) Record(exchange Exchange) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.exchanges = append(c.exchanges, exchange)
+
+	return nil
+}
+
+func (c *Cassette) Exchanges() []Exchange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Exchange(nil), c.exchanges...)
+}
+
+// Calls converts recorded exchanges to calls in the recorded order and
+// applies rules to every call.
+func (c *Cassette) Calls(rules ...ReplayRule) ([]Call, error) {
+	exchanges := c.Exchanges()
+	calls := make([]Call, 0, len(exchanges))
+
+	for i, exchange := range exchanges {
+		call, err := exchange.Call()
+		if err != nil {
+			return nil, fmt.Errorf("exchange %d, %w", i, err)
+		}
+
+		for _, rule := range rules {
+			rule(&call)
+		}
+
+		calls = append(calls, call)
+	}
+
+	return calls, nil
+}
+
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(cassetteFile{Exchanges: c.Exchanges()}, "", "\t")
+	if err != nil {
+		return fmt.Errorf("marshal cassette, %w", err)
+	}
+
+	err = os.WriteFile(path, append(data, '\n'), 0o644)
+	if err != nil {
+		return fmt.Errorf("write cassette, %w", err)
+	}
+
+	return nil
+}
+
+// readAndRestore reads the whole body and replaces it with an in-memory copy.
+func readAndRestore(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+
+	closeErr := (*body).Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	*body = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+func cloneNonEmptyHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	return header.Clone()
+}
@@ -0,0 +1,187 @@
+package httpmock
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// Mode selects how a cassette transport treats the real API.
+type Mode string
+
+const (
+	// ModeReplay serves responses from the cassette file.
+	ModeReplay Mode = "replay"
+	// ModeRecord sends requests to the real API and rewrites the cassette file.
+	ModeRecord Mode = "record"
+	// ModeDiff serves responses from the cassette file and reports where the
+	// real API response differs from the recorded one.
+	ModeDiff Mode = "diff"
+)
+
+// ModeEnv is read when CassetteConfig.Mode is empty.
+const ModeEnv = "HTTPMOCK_MODE"
+
+type CassetteConfig struct {
+	// Path of the cassette file.
+	Path string
+	// Mode defaults to the ModeEnv value and then to ModeReplay.
+	Mode Mode
+	// Upstream reaches the real API, defaults to http.DefaultTransport.
+	Upstream http.RoundTripper
+	// Rules are applied to replayed calls.
+	Rules []ReplayRule
+	// DiffIgnoreJSONFields are JSON pointers excluded from the body diff.
+	DiffIgnoreJSONFields []string
+}
+
+func (c CassetteConfig) mode() (Mode, error) {
+	mode := c.Mode
+	if mode == "" {
+		mode = Mode(os.Getenv(ModeEnv))
+	}
+
+	switch mode {
+	case "":
+		return ModeReplay, nil
+	case ModeReplay, ModeRecord, ModeDiff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown httpmock mode %q, expected replay, record or diff", mode)
+	}
+}
+
+// NewCassetteTransport runs the same test against the real API or the
+// cassette file depending on the configured mode.
+func NewCassetteTransport(t TestReporter, cfg CassetteConfig) http.RoundTripper {
+	mode, err := cfg.mode()
+	if err != nil {
+		t.Fatalf(err.Error())
+
+		return nil
+	}
+
+	upstream := cfg.Upstream
+	if upstream == nil {
+		upstream = http.DefaultTransport
+	}
+
+	if mode == ModeRecord {
+		cassette := &Cassette{}
+
+		t.Cleanup(func() {
+			err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755)
+			if err != nil {
+				t.Errorf("create cassette directory, %s", err)
+
+				return
+			}
+
+			err = cassette.Save(cfg.Path)
+			if err != nil {
+				t.Errorf("save cassette, %s", err)
+			}
+		})
+
+		return NewRecordingTransport(upstream, cassette)
+	}
+
+	cassette, err := LoadCassette(cfg.Path)
+	if err != nil {
+		t.Fatalf("load cassette, %s", err)
+
+		return nil
+	}
+
+	calls, err := cassette.Calls(cfg.Rules...)
+	if err != nil {
+		t.Fatalf("convert cassette %s, %s", cfg.Path, err)
+
+		return nil
+	}
+
+	replay := NewTransport(t, SequenceCalls(calls...), HandleCallCompareInput)
+
+	if mode == ModeReplay {
+		return replay
+	}
+
+	return &diffTransport{
+		t:                t,
+		replay:           replay,
+		upstream:         upstream,
+		exchanges:        cassette.Exchanges(),
+		ignoreJSONFields: cfg.DiffIgnoreJSONFields,
+	}
+}
+
+type diffTransport struct {
+	t                TestReporter
+	replay           http.RoundTripper
+	upstream         http.RoundTripper
+	exchanges        []Exchange
+	ignoreJSONFields []string
+	calledTimes      atomic.Int64
+}
+
+func (d *diffTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	calledTimes := d.calledTimes.Add(1)
+
+	t := errorfTestReporterWithCallNumber(d.t, calledTimes)
+
+	r = r.Clone(r.Context())
+
+	requestBody, err := readAndRestore(&r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body, %w", err)
+	}
+
+	upstreamRequest := r.Clone(r.Context())
+	upstreamRequest.Body = io.NopCloser(bytes.NewReader(requestBody))
+
+	if int(calledTimes) <= len(d.exchanges) {
+		d.diff(t, upstreamRequest, d.exchanges[calledTimes-1].Response)
+	}
+
+	replayRequest := r.Clone(r.Context())
+	replayRequest.Body = io.NopCloser(bytes.NewReader(requestBody))
+
+	return d.replay.RoundTrip(replayRequest)
+}
+
+func (d *diffTransport) diff(t TestReporter, r *http.Request, recorded RecordedResponse) {
+	resp, err := d.upstream.RoundTrip(r)
+	if err != nil {
+		t.Errorf("diff with real api, do request, %s", err)
+
+		return
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Errorf("diff with real api, read body, %s", err)
+
+		return
+	}
+
+	if resp.StatusCode != recorded.StatusCode {
+		t.Errorf("diff with real api, wrong status code, recorded %d, actual %d", recorded.StatusCode, resp.StatusCode)
+	}
+
+	recordedBody := []byte(recorded.Body)
+
+	if len(d.ignoreJSONFields) > 0 {
+		recordedBody = removeJSONFields(recordedBody, d.ignoreJSONFields)
+		body = removeJSONFields(body, d.ignoreJSONFields)
+	}
+
+	if !bytes.Equal(recordedBody, body) {
+		t.Errorf("diff with real api, body differs, recorded %s actual %s", string(recordedBody), string(body))
+	}
+}
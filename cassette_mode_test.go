@@ -0,0 +1,126 @@
+package httpmock
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func counterHandler(format string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		fmt.Fprintf(w, format, r.URL.Path, body)
+	})
+}
+
+func Test_NewCassetteTransport_Modes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures", "users.json")
+
+	requests := func(expectedBody string) func(client *http.Client) error {
+		return do(
+			request{
+				method: http.MethodPost,
+				target: "http://api.example.com/users",
+				body:   strings.NewReader("dima"),
+			},
+			Response{
+				StatusCode: http.StatusOK,
+				Body:       RawBody(expectedBody),
+			},
+		)
+	}
+
+	t.Run("record", func(t *testing.T) {
+		client := &http.Client{
+			Transport: NewCassetteTransport(t, CassetteConfig{
+				Path:     path,
+				Mode:     ModeRecord,
+				Upstream: NewHandlerTransport(counterHandler(`{"path":"%s","name":"%s","ts":1}`)),
+			}),
+		}
+
+		err := requests(`{"path":"/users","name":"dima","ts":1}`)(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("replay from env", func(t *testing.T) {
+		t.Setenv(ModeEnv, string(ModeReplay))
+
+		client := &http.Client{
+			Transport: NewCassetteTransport(t, CassetteConfig{
+				Path:     path,
+				Upstream: NewHandlerTransport(http.NotFoundHandler()),
+			}),
+		}
+
+		err := requests(`{"path":"/users","name":"dima","ts":1}`)(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("diff ignores configured fields", func(t *testing.T) {
+		client := &http.Client{
+			Transport: NewCassetteTransport(t, CassetteConfig{
+				Path:                 path,
+				Mode:                 ModeDiff,
+				Upstream:             NewHandlerTransport(counterHandler(`{"path":"%s","name":"%s","ts":2}`)),
+				DiffIgnoreJSONFields: []string{"/ts"},
+			}),
+		}
+
+		err := requests(`{"path":"/users","name":"dima","ts":1}`)(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("diff reports real api changes", func(t *testing.T) {
+		tr := ExpectFailureTestReporter(
+			[]testReporterCall{
+				{
+					format: "1 call, diff with real api, body differs, recorded %s actual %s",
+					args: []any{
+						`{"path":"/users","name":"dima","ts":1}`,
+						`{"path":"/users","fullName":"dima","ts":1}`,
+					},
+				},
+			},
+			nil,
+		)(t)
+
+		client := &http.Client{
+			Transport: NewCassetteTransport(tr, CassetteConfig{
+				Path:     path,
+				Mode:     ModeDiff,
+				Upstream: NewHandlerTransport(counterHandler(`{"path":"%s","fullName":"%s","ts":1}`)),
+			}),
+		}
+
+		err := requests(`{"path":"/users","name":"dima","ts":1}`)(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func Test_NewCassetteTransport_UnknownMode(t *testing.T) {
+	t.Setenv(ModeEnv, "live")
+
+	tr := ExpectFailureTestReporter(
+		nil,
+		[]testReporterCall{
+			{
+				format: `unknown httpmock mode "live", expected replay, record or diff`,
+			},
+		},
+	)(t)
+
+	NewCassetteTransport(tr, CassetteConfig{Path: "unused.json"})
+}
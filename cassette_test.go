@@ -0,0 +1,159 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_RecordingTransport(t *testing.T) {
+	upstream := http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusAccepted)
+			w.Write(append([]byte("echo "), body...))
+		},
+	)
+
+	cassette := &Cassette{}
+	client := &http.Client{
+		Transport: NewRecordingTransport(NewHandlerTransport(upstream), cassette),
+	}
+
+	responseHeader := make(http.Header)
+	responseHeader.Set("Content-Type", "text/plain")
+
+	err := doMany(
+		do(
+			request{
+				method: http.MethodPost,
+				target: "http://localhost/users?name=dima",
+				body:   strings.NewReader("Hello World!"),
+			},
+			Response{
+				StatusCode: http.StatusAccepted,
+				Body:       RawBody("echo Hello World!"),
+				Header:     responseHeader,
+			},
+		),
+		do(
+			request{
+				method: http.MethodGet,
+				target: "http://localhost/binary",
+				body:   strings.NewReader("\xff\xfe"),
+			},
+			Response{
+				StatusCode: http.StatusAccepted,
+				Body:       RawBody("echo \xff\xfe"),
+				Header:     responseHeader,
+			},
+		),
+	)(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	err = cassette.Save(path)
+	if err != nil {
+		t.Fatalf("save cassette, unexpected error: %s", err)
+	}
+
+	loaded, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("load cassette, unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(loaded.Exchanges(), cassette.Exchanges()) {
+		t.Fatalf("loaded exchanges not equal,\nexpected %v,\nactual %v", cassette.Exchanges(), loaded.Exchanges())
+	}
+
+	calls, err := loaded.Calls()
+	if err != nil {
+		t.Fatalf("convert cassette to calls, unexpected error: %s", err)
+	}
+
+	runTransportTests(t,
+		&transportTest{
+			Name:         "replay recorded calls",
+			TestReporter: ExpectSuccessTestReporter,
+			Calls:        SequenceCalls(calls...),
+			Execute: doMany(
+				do(
+					request{
+						method: http.MethodPost,
+						target: "/users?name=dima",
+						body:   strings.NewReader("Hello World!"),
+					},
+					Response{
+						StatusCode: http.StatusAccepted,
+						Body:       RawBody("echo Hello World!"),
+						Header:     responseHeader,
+					},
+				),
+				do(
+					request{
+						method: http.MethodGet,
+						target: "/binary",
+						body:   strings.NewReader("\xff\xfe"),
+					},
+					Response{
+						StatusCode: http.StatusAccepted,
+						Body:       RawBody("echo \xff\xfe"),
+						Header:     responseHeader,
+					},
+				),
+			),
+		},
+	)
+}
+
+func Test_RecordedBody_JSON(t *testing.T) {
+	tests := []struct {
+		Name string
+		Body RecordedBody
+		JSON string
+	}{
+		{
+			Name: "text",
+			Body: RecordedBody("Hello World!"),
+			JSON: `"Hello World!"`,
+		},
+		{
+			Name: "binary",
+			Body: RecordedBody{0xff, 0x00},
+			JSON: `{"base64":"/wA="}`,
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.Name, func(t *testing.T) {
+			data, err := tst.Body.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(data) != tst.JSON {
+				t.Fatalf("wrong json, expected %s, actual %s", tst.JSON, data)
+			}
+
+			var body RecordedBody
+
+			err = body.UnmarshalJSON(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(body, tst.Body) {
+				t.Errorf("wrong body, expected %v, actual %v", tst.Body, body)
+			}
+		})
+	}
+}
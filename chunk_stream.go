@@ -0,0 +1,82 @@
+package httpmock
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type chunkStep struct {
+	chunk []byte
+	flush bool
+	wait  time.Duration
+}
+
+// ChunkStream is a response body written in steps with explicit flush
+// points, so tests can check the client surfaces partial data before EOF,
+// e.g. Stream().Write(`{"a":1}`).Flush().Wait(time.Second).Write(`{"b":2}`).
+// Written chunks are buffered by the server until the next Flush or the end
+// of the response. NewTransport buffers the whole response, so partial data
+// is visible with NewServer only.
+type ChunkStream struct {
+	steps []chunkStep
+}
+
+func Stream() *ChunkStream {
+	return &ChunkStream{}
+}
+
+// Write appends the chunk to the response.
+func (s *ChunkStream) Write(chunk string) *ChunkStream {
+	s.steps = append(s.steps, chunkStep{chunk: []byte(chunk)})
+
+	return s
+}
+
+// Flush sends written chunks to the client.
+func (s *ChunkStream) Flush() *ChunkStream {
+	s.steps = append(s.steps, chunkStep{flush: true})
+
+	return s
+}
+
+// Wait pauses the response, it ends early when the client disconnects.
+func (s *ChunkStream) Wait(d time.Duration) *ChunkStream {
+	s.steps = append(s.steps, chunkStep{wait: d})
+
+	return s
+}
+
+// Bytes returns the chunks joined, the body the client reads in the end.
+func (s *ChunkStream) Bytes() ([]byte, error) {
+	var body bytes.Buffer
+
+	for _, step := range s.steps {
+		body.Write(step.chunk)
+	}
+
+	return body.Bytes(), nil
+}
+
+func (s *ChunkStream) WriteStream(w http.ResponseWriter, r *http.Request) error {
+	flusher, _ := w.(http.Flusher)
+
+	for _, step := range s.steps {
+		switch {
+		case step.chunk != nil:
+			_, err := w.Write(step.chunk)
+			if err != nil {
+				return fmt.Errorf("write stream chunk, %w", err)
+			}
+		case step.flush && flusher != nil:
+			flusher.Flush()
+		case step.wait > 0:
+			if !sleepContext(r.Context(), step.wait) {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package httpmock
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_Stream(t *testing.T) {
+	server := NewStaticServer(t, Call{
+		Input: Input{Method: http.MethodGet},
+		Response: Response{
+			Body: Stream().Write("first\n").Flush().Wait(200 * time.Millisecond).Write("second\n"),
+		},
+	})
+
+	start := time.Now()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+
+	line, err := reader.ReadString('\n')
+	if err != nil || line != "first\n" {
+		t.Fatalf("wrong first chunk, expected first, actual %q, %v", line, err)
+	}
+
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Errorf("first chunk not flushed, elapsed %s", elapsed)
+	}
+
+	rest, err := io.ReadAll(reader)
+	if err != nil || string(rest) != "second\n" {
+		t.Errorf("wrong second chunk, expected second, actual %q, %v", rest, err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("stream did not wait, elapsed %s", elapsed)
+	}
+}
+
+func Test_Stream_Bytes(t *testing.T) {
+	body, _ := Stream().Write("a").Flush().Wait(time.Second).Write("b").Bytes()
+
+	if string(body) != "ab" {
+		t.Errorf("wrong stream bytes, expected ab, actual %s", body)
+	}
+}
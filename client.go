@@ -0,0 +1,58 @@
+package httpmock
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"time"
+)
+
+// DefaultClientTimeout is the http.Client.Timeout of NewClient, it keeps a
+// broken test from hanging until the go test deadline.
+const DefaultClientTimeout = 10 * time.Second
+
+// NewClient returns a client sending requests to NewTransport with calls
+// compared by HandleCallCompareInput, see WithCookieJar and WithClientTimeout.
+func NewClient(t TestReporter, calls Calls, opts ...Option) *http.Client {
+	transport := NewTransport(t, calls, nil, opts...).(*transport)
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   DefaultClientTimeout,
+	}
+
+	switch timeout := transport.options.clientTimeout; {
+	case timeout < 0:
+		client.Timeout = 0
+	case timeout > 0:
+		client.Timeout = timeout
+	}
+
+	if transport.options.cookieJar {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			t.Fatalf("create cookie jar, %s", err)
+
+			return nil
+		}
+
+		client.Jar = jar
+	}
+
+	return client
+}
+
+// WithCookieJar makes NewClient store cookies set by responses and send
+// them with next requests.
+func WithCookieJar() Option {
+	return func(o *options) {
+		o.cookieJar = true
+	}
+}
+
+// WithClientTimeout overrides DefaultClientTimeout of NewClient, negative
+// timeout disables it.
+func WithClientTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.clientTimeout = timeout
+	}
+}
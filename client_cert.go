@@ -0,0 +1,68 @@
+package httpmock
+
+import (
+	"net/http"
+	"slices"
+	"time"
+)
+
+// ClientCert matches the leaf certificate the client presented over mTLS,
+// see WithTLS with tls.Config.ClientAuth, zero fields are not compared.
+type ClientCert struct {
+	CommonName string
+	// DNSNames must all be in the certificate SANs, other SANs are allowed.
+	DNSNames []string
+	// IssuerCommonName is the common name of the certificate issuer.
+	IssuerCommonName string
+	// MinValidity and MaxValidity bound the time left until the
+	// certificate expires, now is taken from WithClock when set.
+	MinValidity time.Duration
+	MaxValidity time.Duration
+}
+
+func CompareClientCert(t TestReporter, r *http.Request, inputCert *ClientCert) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	if inputCert == nil {
+		return
+	}
+
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		t.Errorf("client certificate not presented")
+
+		return
+	}
+
+	cert := r.TLS.PeerCertificates[0]
+
+	if inputCert.CommonName != "" && cert.Subject.CommonName != inputCert.CommonName {
+		t.Errorf("wrong client certificate common name, expected %s, actual %s", inputCert.CommonName, cert.Subject.CommonName)
+	}
+
+	for _, name := range inputCert.DNSNames {
+		if !slices.Contains(cert.DNSNames, name) {
+			t.Errorf("client certificate san %s not presented, actual %v", name, cert.DNSNames)
+		}
+	}
+
+	if inputCert.IssuerCommonName != "" && cert.Issuer.CommonName != inputCert.IssuerCommonName {
+		t.Errorf("wrong client certificate issuer, expected %s, actual %s", inputCert.IssuerCommonName, cert.Issuer.CommonName)
+	}
+
+	now := time.Now
+	if clock := clockFrom(r.Context()); clock != nil {
+		now = clock
+	}
+
+	left := cert.NotAfter.Sub(now())
+
+	if inputCert.MinValidity > 0 && left < inputCert.MinValidity {
+		t.Errorf("client certificate expires too soon, expected at least %s, actual %s", inputCert.MinValidity, left.Round(time.Second))
+	}
+
+	if inputCert.MaxValidity > 0 && left > inputCert.MaxValidity {
+		t.Errorf("client certificate expires too late, expected at most %s, actual %s", inputCert.MaxValidity, left.Round(time.Second))
+	}
+}
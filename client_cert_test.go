@@ -0,0 +1,98 @@
+package httpmock
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newClientCert(t *testing.T, commonName string, dnsNames []string, notAfter time.Time) tls.Certificate {
+	ca, caCert, err := newProxyCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, ca.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func Test_CompareClientCert(t *testing.T) {
+	notAfter := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+
+	cert := newClientCert(t, "billing", []string{"billing.internal"}, notAfter)
+
+	var reporter TestReporter = t
+
+	server := NewServer(t,
+		StaticCalls(Call{
+			Input: Input{
+				Method: http.MethodGet,
+				ClientCert: &ClientCert{
+					CommonName:       "billing",
+					DNSNames:         []string{"billing.internal", "billing.local"},
+					IssuerCommonName: "httpmock proxy CA",
+					MinValidity:      2 * time.Hour,
+				},
+			},
+		}),
+		func(_ TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+			CompareInput(reporter, r, call.Input)
+		},
+		WithTLS(&tls.Config{ClientAuth: tls.RequireAnyClientCert}),
+		WithClock(func() time.Time { return notAfter.Add(-time.Hour) }),
+	)
+
+	reporter = ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "client certificate san %s not presented, actual %v", args: []any{"billing.local", []string{"billing.internal"}}},
+			{format: "client certificate expires too soon, expected at least %s, actual %s", args: []any{2 * time.Hour, time.Hour}},
+		},
+		nil,
+	)(t)
+
+	client := tlsClient(server, func(config *tls.Config) {
+		config.Certificates = []tls.Certificate{cert}
+	})
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_CompareClientCert_NotPresented(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/", http.NoBody)
+
+	CompareClientCert(ExpectFailureTestReporter(
+		[]testReporterCall{{format: "client certificate not presented"}},
+		nil,
+	)(t), r, &ClientCert{CommonName: "billing"})
+}
@@ -0,0 +1,56 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_NewClient(t *testing.T) {
+	client := NewClient(t,
+		SequenceCalls(
+			Call{
+				Input:    Input{Method: http.MethodPost, URL: mustParseURL("/login")},
+				Response: Resp(http.StatusOK).Cookie(&http.Cookie{Name: "session", Value: "abc", Path: "/"}).Response(),
+			},
+			Call{
+				Input: Input{
+					Method: http.MethodGet,
+					URL:    mustParseURL("/profile"),
+					Header: http.Header{"Cookie": {"session=abc"}},
+				},
+			},
+		),
+		WithCookieJar(),
+	)
+
+	if client.Timeout != DefaultClientTimeout {
+		t.Errorf("wrong client timeout, expected %s, actual %s", DefaultClientTimeout, client.Timeout)
+	}
+
+	resp, err := client.Post("http://localhost/login", "", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	resp, err = client.Get("http://localhost/profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_NewClient_Timeout(t *testing.T) {
+	calls := SequenceCalls()
+
+	if client := NewClient(t, calls, WithClientTimeout(time.Second)); client.Timeout != time.Second || client.Jar != nil {
+		t.Errorf("wrong client, timeout %s, jar %v", client.Timeout, client.Jar)
+	}
+
+	if client := NewClient(t, calls, WithClientTimeout(-1)); client.Timeout != 0 {
+		t.Errorf("timeout not disabled, actual %s", client.Timeout)
+	}
+}
@@ -0,0 +1,69 @@
+package httpmock
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type clockContextKey struct{}
+
+// WithClock makes the transport take the current time from now instead of
+// time.Now for the Date header of every response, Response.Expires,
+// Response.LastModified, Response.RetryAfter and {{now}} templates, so
+// client cache and expiry logic can be tested against a fake clock.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.clock = now
+	}
+}
+
+func attachClock(r *http.Request, now func() time.Time) *http.Request {
+	if now == nil {
+		return r
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), clockContextKey{}, now))
+}
+
+func clockFrom(ctx context.Context) func() time.Time {
+	now, _ := ctx.Value(clockContextKey{}).(func() time.Time)
+
+	return now
+}
+
+// renderTimeHeaders writes the Date header when the request has a clock and
+// the headers of Response time fields.
+func renderTimeHeaders(r *http.Request, response Response) Response {
+	clock := clockFrom(r.Context())
+
+	if clock == nil && response.Expires == 0 && response.LastModified == 0 && response.RetryAfter == 0 {
+		return response
+	}
+
+	if clock == nil {
+		clock = time.Now
+	}
+
+	now := clock().UTC()
+
+	header := response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	setTimeHeader := func(key string, enabled bool, t time.Time) {
+		if enabled && header.Get(key) == "" {
+			header.Set(key, t.Format(http.TimeFormat))
+		}
+	}
+
+	setTimeHeader("Date", true, now)
+	setTimeHeader("Expires", response.Expires != 0, now.Add(response.Expires))
+	setTimeHeader("Last-Modified", response.LastModified != 0, now.Add(-response.LastModified))
+	setTimeHeader("Retry-After", response.RetryAfter != 0, now.Add(response.RetryAfter))
+
+	response.Header = header
+
+	return response
+}
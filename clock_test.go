@@ -0,0 +1,81 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_WithClock(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	transport := NewTransport(t,
+		SequenceCalls(
+			Call{
+				Input: Input{Method: http.MethodGet},
+				Response: Response{
+					StatusCode:     http.StatusOK,
+					Expires:        time.Hour,
+					LastModified:   24 * time.Hour,
+					TemplateHeader: http.Header{"X-Generated-At": {"{{now}}"}},
+				},
+			},
+			Call{
+				Input: Input{Method: http.MethodGet},
+				Response: Response{
+					StatusCode: http.StatusServiceUnavailable,
+					RetryAfter: 30 * time.Second,
+				},
+			},
+		),
+		nil,
+		WithClock(func() time.Time { return now }),
+	)
+
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	CompareHeader(t, resp.Header, http.Header{
+		"Date":           {"Fri, 01 Mar 2024 12:00:00 GMT"},
+		"Expires":        {"Fri, 01 Mar 2024 13:00:00 GMT"},
+		"Last-Modified":  {"Thu, 29 Feb 2024 12:00:00 GMT"},
+		"X-Generated-At": {"2024-03-01T12:00:00Z"},
+	})
+
+	resp, err = client.Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	CompareHeader(t, resp.Header, http.Header{
+		"Date":        {"Fri, 01 Mar 2024 12:00:00 GMT"},
+		"Retry-After": {"Fri, 01 Mar 2024 12:00:30 GMT"},
+	})
+}
+
+func Test_WithClock_Server(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	server := NewServer(t,
+		SequenceCalls(Call{Input: Input{Method: http.MethodGet}, Response: Response{StatusCode: http.StatusOK}}),
+		nil,
+		WithClock(func() time.Time { return now }),
+	)
+
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	CompareHeader(t, resp.Header, http.Header{"Date": {"Fri, 01 Mar 2024 12:00:00 GMT"}})
+}
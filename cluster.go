@@ -0,0 +1,92 @@
+package httpmock
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Cluster is a group of servers sharing one pool of calls, it emulates a
+// load-balanced upstream for client-side balancing, failover and
+// retry-on-other-host logic. Calls are numbered across the cluster, every
+// server counts the calls it served. Servers are closed at Cleanup, close
+// one earlier to emulate a failed host.
+type Cluster struct {
+	Servers []*Server
+}
+
+func NewCluster(t TestReporter, size int, calls Calls, handleCall HandleCall, opts ...Option) *Cluster {
+	ts := newTransport(t, calls, handleCall, opts)
+	ts.events = newEventBroker()
+
+	t.Cleanup(ts.assert)
+	t.Cleanup(ts.webhooks.wait)
+
+	cluster := &Cluster{Servers: make([]*Server, size)}
+
+	for i := range cluster.Servers {
+		server := &Server{
+			transport: ts,
+			conns:     newConnTracker(),
+			drain:     newDrainTracker(),
+		}
+
+		server.start(nil)
+
+		t.Cleanup(server.Close)
+
+		cluster.Servers[i] = server
+	}
+
+	t.Cleanup(ts.awaitCalls)
+
+	return cluster
+}
+
+// URLs returns the base urls of the servers.
+func (c *Cluster) URLs() []string {
+	urls := make([]string, len(c.Servers))
+
+	for i, server := range c.Servers {
+		urls[i] = server.URL
+	}
+
+	return urls
+}
+
+// Served returns the count of calls served by every server.
+func (c *Cluster) Served() []int {
+	served := make([]int, len(c.Servers))
+
+	for i, server := range c.Servers {
+		served[i] = server.Served()
+	}
+
+	return served
+}
+
+// AssertAllServed reports servers which served no calls, e.g. when the
+// client does not balance requests.
+func (c *Cluster) AssertAllServed(t TestReporter) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	for i, served := range c.Served() {
+		if served == 0 {
+			t.Errorf("server %d %s served no calls", i, c.Servers[i].URL)
+		}
+	}
+}
+
+// servedCounter counts requests served by a server.
+type servedCounter struct {
+	count atomic.Int64
+}
+
+func (s *servedCounter) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.count.Add(1)
+
+		next.ServeHTTP(w, r)
+	})
+}
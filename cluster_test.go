@@ -0,0 +1,63 @@
+package httpmock
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func Test_Cluster_SharedCalls(t *testing.T) {
+	cluster := NewCluster(t, 3,
+		SequenceCalls(
+			Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("/items/1")}},
+			Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("/items/2")}},
+			Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("/items/3")}},
+		),
+		HandleCallCompareInput,
+	)
+
+	for i, url := range cluster.URLs() {
+		resp, err := http.Get(url + "/items/" + strconv.Itoa(i+1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	if served := cluster.Served(); !reflect.DeepEqual(served, []int{1, 1, 1}) {
+		t.Errorf("wrong served calls, expected [1 1 1], actual %v", served)
+	}
+
+	cluster.AssertAllServed(t)
+}
+
+func Test_Cluster_Failover(t *testing.T) {
+	cluster := NewCluster(t, 2,
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}}),
+		HandleCallCompareInput,
+	)
+
+	cluster.Servers[0].Close()
+
+	for _, url := range cluster.URLs() {
+		resp, err := http.Get(url)
+		if err != nil {
+			continue
+		}
+
+		resp.Body.Close()
+	}
+
+	if served := cluster.Served(); !reflect.DeepEqual(served, []int{0, 1}) {
+		t.Errorf("wrong served calls, expected [0 1], actual %v", served)
+	}
+
+	reporter := ExpectFailureTestReporter(
+		[]testReporterCall{{format: "server %d %s served no calls", args: []any{0, cluster.Servers[0].URL}}},
+		nil,
+	)(t)
+
+	cluster.AssertAllServed(reporter)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/amidgo/httpmock"
+)
+
+var methodConstants = map[string]string{
+	http.MethodGet:     "http.MethodGet",
+	http.MethodHead:    "http.MethodHead",
+	http.MethodPost:    "http.MethodPost",
+	http.MethodPut:     "http.MethodPut",
+	http.MethodPatch:   "http.MethodPatch",
+	http.MethodDelete:  "http.MethodDelete",
+	http.MethodConnect: "http.MethodConnect",
+	http.MethodOptions: "http.MethodOptions",
+	http.MethodTrace:   "http.MethodTrace",
+}
+
+// generateCalls writes go source declaring variable with []httpmock.Call
+// literals built from exchanges.
+func generateCalls(w io.Writer, packageName, variable string, exchanges []httpmock.Exchange) error {
+	var (
+		body     bytes.Buffer
+		usesHTTP bool
+	)
+
+	fmt.Fprintf(&body, "var %s = []httpmock.Call{\n", variable)
+
+	for i, exchange := range exchanges {
+		u, err := url.Parse(exchange.Request.URL)
+		if err != nil {
+			return fmt.Errorf("exchange %d, parse url, %w", i, err)
+		}
+
+		body.WriteString("{\nInput: httpmock.Input{\n")
+		method, isConstant := methodLiteral(exchange.Request.Method)
+		usesHTTP = usesHTTP || isConstant
+
+		fmt.Fprintf(&body, "Method: %s,\n", method)
+		fmt.Fprintf(&body, "URL: &url.URL{Path: %s", strconv.Quote(u.Path))
+
+		if u.RawQuery != "" {
+			fmt.Fprintf(&body, ", RawQuery: %s", strconv.Quote(u.RawQuery))
+		}
+
+		body.WriteString("},\n")
+
+		if len(exchange.Request.Body) > 0 {
+			fmt.Fprintf(&body, "Body: httpmock.RawBody(%s),\n", bytesLiteral(exchange.Request.Body))
+		}
+
+		body.WriteString("},\nResponse: httpmock.Response{\n")
+		fmt.Fprintf(&body, "StatusCode: %d,\n", exchange.Response.StatusCode)
+
+		if len(exchange.Response.Header) > 0 {
+			usesHTTP = true
+
+			body.WriteString("Header: http.Header{\n")
+
+			keys := make([]string, 0, len(exchange.Response.Header))
+			for key := range exchange.Response.Header {
+				keys = append(keys, key)
+			}
+
+			slices.Sort(keys)
+
+			for _, key := range keys {
+				values := make([]string, 0, len(exchange.Response.Header[key]))
+				for _, value := range exchange.Response.Header[key] {
+					values = append(values, strconv.Quote(value))
+				}
+
+				fmt.Fprintf(&body, "%s: {%s},\n", strconv.Quote(key), strings.Join(values, ", "))
+			}
+
+			body.WriteString("},\n")
+		}
+
+		if len(exchange.Response.Body) > 0 {
+			fmt.Fprintf(&body, "Body: httpmock.RawBody(%s),\n", bytesLiteral(exchange.Response.Body))
+		}
+
+		body.WriteString("},\n},\n")
+	}
+
+	body.WriteString("}\n")
+
+	var src bytes.Buffer
+
+	fmt.Fprintf(&src, "// Code generated by httpmock codegen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", packageName)
+
+	if usesHTTP {
+		src.WriteString("\"net/http\"\n")
+	}
+
+	if len(exchanges) > 0 {
+		src.WriteString("\"net/url\"\n\n")
+	}
+
+	src.WriteString("\"github.com/amidgo/httpmock\"\n)\n\n")
+	src.Write(body.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated source, %w", err)
+	}
+
+	_, err = w.Write(formatted)
+	if err != nil {
+		return fmt.Errorf("write generated source, %w", err)
+	}
+
+	return nil
+}
+
+func methodLiteral(method string) (literal string, isConstant bool) {
+	if constant, ok := methodConstants[method]; ok {
+		return constant, true
+	}
+
+	return strconv.Quote(method), false
+}
+
+func bytesLiteral(data []byte) string {
+	text := string(data)
+
+	if strconv.CanBackquote(text) && strings.Contains(text, "\"") {
+		return "`" + text + "`"
+	}
+
+	return strconv.Quote(text)
+}
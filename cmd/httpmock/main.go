@@ -0,0 +1,197 @@
+// Command httpmock records traffic to a real upstream through a reverse
+// proxy and turns it into cassettes or go source with httpmock.Call literals.
+//
+//	httpmock record -target https://api.example.com -listen 127.0.0.1:8080 -out cassette.json
+//	httpmock record -target https://api.example.com -format go -package client_test -out calls_test.go
+//	httpmock codegen -in cassette.json -package client_test -out calls_test.go
+//
+// record serves until it receives SIGINT or SIGTERM and writes the output on exit.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/amidgo/httpmock"
+)
+
+func main() {
+	err := run(context.Background(), os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: httpmock record|codegen [flags]")
+	}
+
+	switch args[0] {
+	case "record":
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return record(ctx, args[1:], stdout, stderr)
+	case "codegen":
+		return codegen(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown command %s, usage: httpmock record|codegen [flags]", args[0])
+	}
+}
+
+type outputFlags struct {
+	out         string
+	format      string
+	packageName string
+	variable    string
+}
+
+func (o *outputFlags) register(fs *flag.FlagSet, format string) {
+	fs.StringVar(&o.out, "out", "", "output file, stdout by default")
+	fs.StringVar(&o.format, "format", format, "output format, cassette or go")
+	fs.StringVar(&o.packageName, "package", "main", "package of generated go source")
+	fs.StringVar(&o.variable, "var", "recordedCalls", "variable name of generated go source")
+}
+
+// validate checks the flags before recording starts, so traffic is not
+// recorded to be dropped at exit.
+func (o *outputFlags) validate() error {
+	switch o.format {
+	case "cassette":
+		if o.out == "" {
+			return errors.New("cassette format requires -out")
+		}
+
+		return nil
+	case "go":
+		return nil
+	default:
+		return fmt.Errorf("unknown format %s, expected cassette or go", o.format)
+	}
+}
+
+func (o *outputFlags) write(cassette *httpmock.Cassette, stdout io.Writer) error {
+	if o.format == "cassette" {
+		return cassette.Save(o.out)
+	}
+
+	if o.out == "" {
+		return generateCalls(stdout, o.packageName, o.variable, cassette.Exchanges())
+	}
+
+	file, err := os.Create(o.out)
+	if err != nil {
+		return fmt.Errorf("create output file, %w", err)
+	}
+
+	err = generateCalls(file, o.packageName, o.variable, cassette.Exchanges())
+
+	closeErr := file.Close()
+	if err == nil && closeErr != nil {
+		err = fmt.Errorf("close output file, %w", closeErr)
+	}
+
+	return err
+}
+
+func record(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		target string
+		listen string
+		output outputFlags
+	)
+
+	fs.StringVar(&target, "target", "", "upstream base url")
+	fs.StringVar(&listen, "listen", "127.0.0.1:8080", "proxy listen address")
+	output.register(fs, "cassette")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	err = output.validate()
+	if err != nil {
+		return err
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil || targetURL.Scheme == "" || targetURL.Host == "" {
+		return fmt.Errorf("invalid -target %q, expected absolute url", target)
+	}
+
+	lis, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("listen %s, %w", listen, err)
+	}
+
+	cassette := &httpmock.Cassette{}
+
+	err = serveRecordingProxy(ctx, lis, targetURL, cassette, stderr)
+	if err != nil {
+		return err
+	}
+
+	return output.write(cassette, stdout)
+}
+
+func serveRecordingProxy(ctx context.Context, lis net.Listener, target *url.URL, cassette *httpmock.Cassette, stderr io.Writer) error {
+	server := &http.Server{Handler: httpmock.NewRecordingHandler(target, cassette)}
+
+	go func() {
+		<-ctx.Done()
+
+		server.Close()
+	}()
+
+	fmt.Fprintf(stderr, "recording %s on http://%s\n", target, lis.Addr())
+
+	err := server.Serve(lis)
+	if !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve recording proxy, %w", err)
+	}
+
+	return nil
+}
+
+func codegen(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("codegen", flag.ContinueOnError)
+
+	var (
+		in     string
+		output outputFlags
+	)
+
+	fs.StringVar(&in, "in", "", "cassette file")
+	output.register(fs, "go")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if output.format != "go" {
+		return fmt.Errorf("codegen writes go source, -format %s is not supported", output.format)
+	}
+
+	cassette, err := httpmock.LoadCassette(in)
+	if err != nil {
+		return err
+	}
+
+	return output.write(cassette, stdout)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/amidgo/httpmock"
+)
+
+func Test_generateCalls(t *testing.T) {
+	exchanges := []httpmock.Exchange{
+		{
+			Request: httpmock.RecordedRequest{
+				Method: http.MethodPost,
+				URL:    "http://api.example.com/users?dry=true",
+				Body:   httpmock.RecordedBody(`{"name":"dima"}`),
+			},
+			Response: httpmock.RecordedResponse{
+				StatusCode: http.StatusCreated,
+				Header:     http.Header{"Content-Type": {"application/json"}, "X-A": {"1", "2"}},
+				Body:       httpmock.RecordedBody(`{"id":1}`),
+			},
+		},
+		{
+			Request: httpmock.RecordedRequest{
+				Method: "PURGE",
+				URL:    "http://api.example.com/cache",
+			},
+			Response: httpmock.RecordedResponse{
+				StatusCode: http.StatusNoContent,
+			},
+		},
+	}
+
+	var out bytes.Buffer
+
+	err := generateCalls(&out, "client_test", "calls", exchanges)
+	if err != nil {
+		t.Fatalf("generate calls, unexpected error: %s", err)
+	}
+
+	expected := "// Code generated by httpmock codegen. DO NOT EDIT.\n\n" +
+		"package client_test\n\n" +
+		"import (\n" +
+		"\t\"net/http\"\n" +
+		"\t\"net/url\"\n\n" +
+		"\t\"github.com/amidgo/httpmock\"\n" +
+		")\n\n" +
+		"var calls = []httpmock.Call{\n" +
+		"\t{\n" +
+		"\t\tInput: httpmock.Input{\n" +
+		"\t\t\tMethod: http.MethodPost,\n" +
+		"\t\t\tURL:    &url.URL{Path: \"/users\", RawQuery: \"dry=true\"},\n" +
+		"\t\t\tBody:   httpmock.RawBody(`{\"name\":\"dima\"}`),\n" +
+		"\t\t},\n" +
+		"\t\tResponse: httpmock.Response{\n" +
+		"\t\t\tStatusCode: 201,\n" +
+		"\t\t\tHeader: http.Header{\n" +
+		"\t\t\t\t\"Content-Type\": {\"application/json\"},\n" +
+		"\t\t\t\t\"X-A\":          {\"1\", \"2\"},\n" +
+		"\t\t\t},\n" +
+		"\t\t\tBody: httpmock.RawBody(`{\"id\":1}`),\n" +
+		"\t\t},\n" +
+		"\t},\n" +
+		"\t{\n" +
+		"\t\tInput: httpmock.Input{\n" +
+		"\t\t\tMethod: \"PURGE\",\n" +
+		"\t\t\tURL:    &url.URL{Path: \"/cache\"},\n" +
+		"\t\t},\n" +
+		"\t\tResponse: httpmock.Response{\n" +
+		"\t\t\tStatusCode: 204,\n" +
+		"\t\t},\n" +
+		"\t},\n" +
+		"}\n"
+
+	if out.String() != expected {
+		t.Errorf("wrong generated source,\nexpected:\n%s\nactual:\n%s", expected, out.String())
+	}
+}
+
+func Test_record(t *testing.T) {
+	upstream := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			io.WriteString(w, "pong "+r.URL.Path)
+		}),
+	)
+	defer upstream.Close()
+
+	target, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cassette := &httpmock.Cassette{}
+	done := make(chan error, 1)
+
+	go func() {
+		done <- serveRecordingProxy(ctx, lis, target, cassette, io.Discard)
+	}()
+
+	resp, err := http.Get("http://" + lis.Addr().String() + "/ping")
+	if err != nil {
+		t.Fatalf("request through proxy, unexpected error: %s", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "pong /ping" {
+		t.Errorf("wrong proxied body, expected %s, actual %s", "pong /ping", body)
+	}
+
+	cancel()
+
+	err = <-done
+	if err != nil {
+		t.Fatalf("serve recording proxy, unexpected error: %s", err)
+	}
+
+	exchanges := cassette.Exchanges()
+	if len(exchanges) != 1 {
+		t.Fatalf("wrong recorded exchanges count, expected 1, actual %d", len(exchanges))
+	}
+
+	if exchanges[0].Request.URL != upstream.URL+"/ping" {
+		t.Errorf("wrong recorded url, expected %s, actual %s", upstream.URL+"/ping", exchanges[0].Request.URL)
+	}
+
+	out := filepath.Join(t.TempDir(), "cassette.json")
+
+	err = (&outputFlags{out: out, format: "cassette"}).write(cassette, io.Discard)
+	if err != nil {
+		t.Fatalf("write cassette, unexpected error: %s", err)
+	}
+
+	var source bytes.Buffer
+
+	err = run(context.Background(), []string{"codegen", "-in", out, "-package", "client_test"}, &source, io.Discard)
+	if err != nil {
+		t.Fatalf("run codegen, unexpected error: %s", err)
+	}
+
+	if !strings.Contains(source.String(), "var recordedCalls = []httpmock.Call{") {
+		t.Errorf("generated source does not declare recordedCalls:\n%s", source.String())
+	}
+}
+
+func Test_run_UnknownCommand(t *testing.T) {
+	err := run(context.Background(), []string{"replay"}, io.Discard, io.Discard)
+	if err == nil || !strings.HasPrefix(err.Error(), "unknown command replay") {
+		t.Errorf("wrong error, expected unknown command, actual %v", err)
+	}
+}
+
+func Test_run_InvalidOutputFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "record cassette without out",
+			args:     []string{"record", "-target", "http://localhost", "-listen", "127.0.0.1:0"},
+			expected: "cassette format requires -out",
+		},
+		{
+			name:     "record unknown format",
+			args:     []string{"record", "-target", "http://localhost", "-listen", "127.0.0.1:0", "-format", "gocode"},
+			expected: "unknown format gocode, expected cassette or go",
+		},
+		{
+			name:     "codegen cassette format",
+			args:     []string{"codegen", "-in", "cassette.json", "-format", "cassette"},
+			expected: "codegen writes go source, -format cassette is not supported",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := run(context.Background(), tc.args, io.Discard, io.Discard)
+			if err == nil || err.Error() != tc.expected {
+				t.Errorf("wrong error, expected %s, actual %v", tc.expected, err)
+			}
+		})
+	}
+}
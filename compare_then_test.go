@@ -0,0 +1,49 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_CompareThen(t *testing.T) {
+	respond := func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("X-Echo", string(body))
+
+		RespondCall(t, w, r, call)
+	}
+
+	client := &http.Client{
+		Transport: NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{{format: "1 call, wrong r.Method, expected %s, actual %s", args: []any{"PUT", "POST"}}},
+				nil,
+			)(t),
+			SequenceCalls(Call{
+				Input:    Input{Method: http.MethodPut, Body: RawBody("ping")},
+				Response: Response{Body: RawBody("pong")},
+			}),
+			CompareThen(respond),
+		),
+	}
+
+	resp, err := client.Post("http://localhost/items", "text/plain", strings.NewReader("ping"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if echo := resp.Header.Get("X-Echo"); echo != "ping" {
+		t.Errorf("request body not rewound, expected ping, actual %s", echo)
+	}
+
+	if string(body) != "pong" {
+		t.Errorf("wrong body, expected pong, actual %s", body)
+	}
+}
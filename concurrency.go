@@ -0,0 +1,57 @@
+package httpmock
+
+import "sync"
+
+// Concurrency records how many requests the mock served simultaneously, the
+// zero value is ready to use, see WithConcurrency.
+type Concurrency struct {
+	mu       sync.Mutex
+	inFlight int
+	max      int
+}
+
+// WithConcurrency records in-flight requests to c, a request is in flight
+// from the call match until the handler returns, Call.Delay included.
+func WithConcurrency(c *Concurrency) Option {
+	return func(o *options) {
+		o.concurrency = c
+	}
+}
+
+func (c *Concurrency) begin() (end func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight++
+	c.max = max(c.max, c.inFlight)
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.inFlight--
+	}
+}
+
+// InFlight returns the count of requests being served now.
+func (c *Concurrency) InFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.inFlight
+}
+
+// Max returns the maximum count of simultaneous requests observed.
+func (c *Concurrency) Max() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.max
+}
+
+// AssertMax reports when more than limit requests were in flight at once.
+func (c *Concurrency) AssertMax(t TestReporter, limit int) {
+	if observed := c.Max(); observed > limit {
+		t.Errorf("concurrency limit exceeded, limit %d, actual %d", limit, observed)
+	}
+}
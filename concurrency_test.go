@@ -0,0 +1,75 @@
+package httpmock
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func concurrentRequests(t *testing.T, client *http.Client, url string, count int) {
+	var wg sync.WaitGroup
+
+	for range count {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			resp, err := client.Get(url)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			resp.Body.Close()
+		}()
+	}
+
+	wg.Wait()
+}
+
+func Test_Concurrency(t *testing.T) {
+	concurrency := &Concurrency{}
+
+	transport := NewTransport(t,
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}, Delay: 50 * time.Millisecond}),
+		nil,
+		WithConcurrency(concurrency),
+	)
+
+	concurrentRequests(t, &http.Client{Transport: transport}, "http://localhost/items", 3)
+
+	if observed := concurrency.Max(); observed != 3 {
+		t.Errorf("wrong max concurrency, expected 3, actual %d", observed)
+	}
+
+	if inFlight := concurrency.InFlight(); inFlight != 0 {
+		t.Errorf("wrong in flight count, expected 0, actual %d", inFlight)
+	}
+
+	concurrency.AssertMax(t, 3)
+	concurrency.AssertMax(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "concurrency limit exceeded, limit %d, actual %d", args: []any{2, 3}},
+		},
+		nil,
+	)(t), 2)
+}
+
+func Test_Concurrency_Server(t *testing.T) {
+	concurrency := &Concurrency{}
+
+	server := NewServer(t,
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}, Delay: 20 * time.Millisecond}),
+		nil,
+		WithConcurrency(concurrency),
+	)
+
+	for range 3 {
+		concurrentRequests(t, server.Client(), server.URL, 1)
+	}
+
+	concurrency.AssertMax(t, 1)
+}
@@ -0,0 +1,81 @@
+package httpmock
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type connRequest struct {
+	remoteAddr string
+	fresh      bool
+	closed     bool
+}
+
+// connDirectives records the connection of every served request and
+// whether its response closed the connection.
+type connDirectives struct {
+	mu       sync.Mutex
+	requests []connRequest
+	served   map[string]int
+}
+
+func (c *connDirectives) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		closed := r.Close || headerHasToken(w.Header().Values("Connection"), "close")
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.served == nil {
+			c.served = make(map[string]int)
+		}
+
+		c.requests = append(c.requests, connRequest{
+			remoteAddr: r.RemoteAddr,
+			fresh:      c.served[r.RemoteAddr] == 0,
+			closed:     closed,
+		})
+
+		c.served[r.RemoteAddr]++
+	})
+}
+
+func headerHasToken(values []string, token string) bool {
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// AssertRedialedAfterClose checks sequential requests against connection
+// directives of their previous responses, see Response.Close: the request
+// after a closed connection must dial a new one, the request after a
+// kept-alive connection must reuse a pooled one.
+func (s *Server) AssertRedialedAfterClose(t TestReporter) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	s.directives.mu.Lock()
+	requests := append([]connRequest(nil), s.directives.requests...)
+	s.directives.mu.Unlock()
+
+	for i := 1; i < len(requests); i++ {
+		prev, cur := requests[i-1], requests[i]
+
+		switch {
+		case prev.closed && cur.remoteAddr == prev.remoteAddr:
+			t.Errorf("request %d sent over connection %s closed by request %d", i+1, cur.remoteAddr, i)
+		case !prev.closed && cur.fresh:
+			t.Errorf("request %d dialed a new connection, connection of request %d was kept alive", i+1, i)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func connDirectivesServer(t *testing.T) *Server {
+	return NewServer(t,
+		SequenceCalls(
+			Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("1")}},
+			Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("2"), Close: true}},
+			Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("3")}},
+		),
+		nil,
+	)
+}
+
+func getAll(t *testing.T, client func() *http.Client, url string, count int) {
+	for range count {
+		resp, err := client().Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+func Test_Response_Close(t *testing.T) {
+	server := connDirectivesServer(t)
+
+	client := &http.Client{Transport: &http.Transport{}}
+
+	getAll(t, func() *http.Client { return client }, server.URL, 3)
+
+	server.AssertRedialedAfterClose(t)
+	server.AssertConnsOpened(t, 2)
+}
+
+func Test_Response_Close_NotPooled(t *testing.T) {
+	server := connDirectivesServer(t)
+
+	getAll(t, func() *http.Client { return &http.Client{Transport: &http.Transport{}} }, server.URL, 3)
+
+	server.AssertRedialedAfterClose(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "request %d dialed a new connection, connection of request %d was kept alive", args: []any{2, 1}},
+		},
+		nil,
+	)(t))
+}
+
+func Test_Response_Close_Transport(t *testing.T) {
+	transport := NewTransport(t,
+		SequenceCalls(Call{Input: Input{Method: http.MethodGet}, Response: Response{Close: true}}),
+		nil,
+	)
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://localhost/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if !resp.Close {
+		t.Errorf("response is not closing the connection")
+	}
+}
@@ -0,0 +1,131 @@
+package httpmock
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ConnStats describes connections the server accepted.
+type ConnStats struct {
+	// Opened is the count of accepted connections.
+	Opened int
+	// Closed is the count of closed or hijacked connections.
+	Closed int
+	// Idle is the count of open connections waiting for a request.
+	Idle int
+	// Requests is the count of requests read from all connections.
+	Requests int
+	// Reused is the count of requests read from a connection which served
+	// a request before.
+	Reused int
+}
+
+type connInfo struct {
+	requests int
+	state    http.ConnState
+}
+
+type connTracker struct {
+	mu    sync.Mutex
+	conns map[net.Conn]*connInfo
+	stats ConnStats
+	tls   []TLSConnState
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{
+		conns: make(map[net.Conn]*connInfo),
+	}
+}
+
+func (c *connTracker) track(conn net.Conn, state http.ConnState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, ok := c.conns[conn]
+	if !ok {
+		info = &connInfo{}
+		c.conns[conn] = info
+		c.stats.Opened++
+	}
+
+	if info.state == http.StateIdle {
+		c.stats.Idle--
+	}
+
+	info.state = state
+
+	switch state {
+	case http.StateActive:
+		if info.requests > 0 {
+			c.stats.Reused++
+		} else {
+			c.trackTLS(conn)
+		}
+
+		info.requests++
+		c.stats.Requests++
+	case http.StateIdle:
+		c.stats.Idle++
+	case http.StateClosed, http.StateHijacked:
+		c.stats.Closed++
+
+		delete(c.conns, conn)
+	}
+}
+
+func (c *connTracker) snapshot() ConnStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// ConnStats returns statistics of accepted connections.
+func (s *Server) ConnStats() ConnStats {
+	return s.conns.snapshot()
+}
+
+// AssertConnsOpened reports when the count of accepted connections differs
+// from expected.
+func (s *Server) AssertConnsOpened(t TestReporter, expected int) {
+	if opened := s.ConnStats().Opened; opened != expected {
+		t.Errorf("wrong opened connections count, expected %d, actual %d", expected, opened)
+	}
+}
+
+// AssertConnsReused reports when some request after the first one was sent
+// over a new connection.
+func (s *Server) AssertConnsReused(t TestReporter) {
+	stats := s.ConnStats()
+
+	if expected := stats.Requests - 1; stats.Requests > 0 && stats.Reused != expected {
+		t.Errorf("connections not reused, expected %d reused requests, actual %d", expected, stats.Reused)
+	}
+}
+
+// AssertIdleConnsClosed reports when connections are left open after the
+// requests are done, e.g. http.Transport.CloseIdleConnections was not called.
+// The server sees the close asynchronously, so it waits up to a second.
+func (s *Server) AssertIdleConnsClosed(t TestReporter) {
+	deadline := time.Now().Add(time.Second)
+
+	for {
+		stats := s.ConnStats()
+
+		open := stats.Opened - stats.Closed
+		if open == 0 {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Errorf("idle connections not closed, open %d", open)
+
+			return
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
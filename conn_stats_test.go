@@ -0,0 +1,88 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func Test_Server_ConnStats(t *testing.T) {
+	server := NewStaticServer(t, Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("ok")}})
+
+	client := &http.Client{Transport: &http.Transport{}}
+
+	for range 3 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	server.AssertConnsOpened(t, 1)
+	server.AssertConnsReused(t)
+
+	client.CloseIdleConnections()
+
+	server.AssertIdleConnsClosed(t)
+
+	stats := server.ConnStats()
+
+	expected := ConnStats{Opened: 1, Closed: 1, Requests: 3, Reused: 2}
+	if stats != expected {
+		t.Errorf("wrong conn stats, expected %+v, actual %+v", expected, stats)
+	}
+}
+
+func Test_Server_ConnStats_NotReused(t *testing.T) {
+	server := NewStaticServer(t, Call{Input: Input{Method: http.MethodGet}})
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	server.AssertConnsOpened(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "wrong opened connections count, expected %d, actual %d", args: []any{1, 2}},
+		},
+		nil,
+	)(t), 1)
+
+	server.AssertConnsReused(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "connections not reused, expected %d reused requests, actual %d", args: []any{1, 0}},
+		},
+		nil,
+	)(t))
+}
+
+func Test_Server_ConnStats_IdleNotClosed(t *testing.T) {
+	server := NewStaticServer(t, Call{Input: Input{Method: http.MethodGet}})
+
+	client := &http.Client{Transport: &http.Transport{}}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	server.AssertIdleConnsClosed(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "idle connections not closed, open %d", args: []any{1}},
+		},
+		nil,
+	)(t))
+
+	client.CloseIdleConnections()
+}
@@ -0,0 +1,290 @@
+package httpmock
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Controller verifies calls recorded gomock style: expectations are matched
+// by input in any order, constrained by Times and After, and checked by
+// AssertExpectations, which runs at Cleanup as well. Expectations with
+// indistinguishable inputs are checked when they are added: one behind an
+// identical expectation expected any times never matches and fails the
+// test, others are logged. SequenceCalls, StaticCalls and Mock serve calls
+// by the call number, identical inputs are served in turn there and are not
+// checked.
+//
+//	ctrl := httpmock.NewController(t)
+//	login := ctrl.EXPECT().Request(http.MethodPost, "/login").Return(Response{StatusCode: 200})
+//	ctrl.EXPECT().Request(http.MethodGet, "/users").Times(2).After(login)
+type Controller struct {
+	t TestReporter
+
+	mu       sync.Mutex
+	expected []*ExpectedCall
+	asserted bool
+}
+
+// Recorder records expectations of the controller.
+type Recorder struct {
+	ctrl *Controller
+}
+
+// ExpectedCall is a recorded expectation, by default it is expected once.
+type ExpectedCall struct {
+	ctrl     *Controller
+	input    Input
+	response Response
+	doError  error
+	min      int
+	max      int
+	calls    int
+	prereqs  []*ExpectedCall
+	site     callSite
+}
+
+func NewController(t TestReporter) *Controller {
+	ctrl := &Controller{t: t}
+
+	t.Cleanup(func() {
+		ctrl.AssertExpectations(t)
+	})
+
+	return ctrl
+}
+
+func (c *Controller) EXPECT() *Recorder {
+	return &Recorder{ctrl: c}
+}
+
+// Call expects a request matching the input, see CompareInput.
+func (r *Recorder) Call(input Input) *ExpectedCall {
+	return r.expect(input, definitionSite())
+}
+
+func (r *Recorder) expect(input Input, site callSite) *ExpectedCall {
+	e := &ExpectedCall{
+		ctrl:     r.ctrl,
+		input:    input,
+		response: Response{StatusCode: http.StatusOK},
+		min:      1,
+		max:      1,
+		site:     site,
+	}
+
+	r.ctrl.mu.Lock()
+	defer r.ctrl.mu.Unlock()
+
+	r.ctrl.expected = append(r.ctrl.expected, e)
+	r.ctrl.checkDuplicate()
+
+	return e
+}
+
+// Request expects the method and the target path with an optional query.
+func (r *Recorder) Request(method, target string) *ExpectedCall {
+	u, err := url.Parse(target)
+	if err != nil {
+		r.ctrl.t.Fatalf("parse expectation target %s, %s", target, err)
+	}
+
+	return r.expect(Input{Method: method, URL: u}, definitionSite())
+}
+
+// Return sets the response served for matched requests.
+func (e *ExpectedCall) Return(response Response) *ExpectedCall {
+	return e.update(func() { e.response = response })
+}
+
+// ReturnError fails matched requests with err.
+func (e *ExpectedCall) ReturnError(err error) *ExpectedCall {
+	return e.update(func() { e.doError = err })
+}
+
+func (e *ExpectedCall) Times(n int) *ExpectedCall {
+	return e.update(func() { e.min, e.max = n, n })
+}
+
+func (e *ExpectedCall) MinTimes(n int) *ExpectedCall {
+	return e.update(func() {
+		e.min = n
+
+		if e.max >= 0 && e.max < n {
+			e.max = n
+		}
+	})
+}
+
+// MaxTimes sets the upper bound, it lowers MinTimes when needed.
+func (e *ExpectedCall) MaxTimes(n int) *ExpectedCall {
+	return e.update(func() {
+		e.max = n
+		e.min = min(e.min, n)
+	})
+}
+
+func (e *ExpectedCall) AnyTimes() *ExpectedCall {
+	return e.update(func() { e.min, e.max = 0, -1 })
+}
+
+// After matches e only once prereq got its minimum calls.
+func (e *ExpectedCall) After(prereq *ExpectedCall) *ExpectedCall {
+	return e.update(func() { e.prereqs = append(e.prereqs, prereq) })
+}
+
+// InOrder makes every call expected after the previous one.
+func InOrder(calls ...*ExpectedCall) {
+	for i := 1; i < len(calls); i++ {
+		calls[i].After(calls[i-1])
+	}
+}
+
+func (e *ExpectedCall) update(update func()) *ExpectedCall {
+	e.ctrl.mu.Lock()
+	defer e.ctrl.mu.Unlock()
+
+	update()
+
+	return e
+}
+
+func (e *ExpectedCall) String() string {
+	target := ""
+	if e.input.URL != nil {
+		target = e.input.URL.RequestURI()
+	}
+
+	return strings.TrimSpace(e.input.Method + " " + target)
+}
+
+func (e *ExpectedCall) exhausted() bool {
+	return e.max >= 0 && e.calls >= e.max
+}
+
+func (e *ExpectedCall) satisfied() bool {
+	return e.calls >= e.min
+}
+
+// Transport returns the transport matching requests against expectations.
+func (c *Controller) Transport() http.RoundTripper {
+	return c
+}
+
+func (c *Controller) RoundTrip(r *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if r.Body != nil {
+		var err error
+
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	e, err := c.match(r, body)
+	if err != nil {
+		c.t.Errorf("%s", err.Error())
+
+		return nil, err
+	}
+
+	if e.doError != nil {
+		return nil, e.doError
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return ResponseResponder(e.response)(r)
+}
+
+func (c *Controller) match(r *http.Request, body []byte) (*ExpectedCall, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var reasons []string
+
+	for _, e := range c.expected {
+		reporter := &collectTestReporter{}
+
+		req := r.Clone(r.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		CompareInput(reporter, req, e.input)
+
+		if len(reporter.errors) > 0 {
+			continue
+		}
+
+		if e.exhausted() {
+			reasons = append(reasons, fmt.Sprintf("%s has already been called %d times", e, e.calls))
+
+			continue
+		}
+
+		if prereq := e.missingPrereq(); prereq != nil {
+			reasons = append(reasons, fmt.Sprintf("%s is expected after %s", e, prereq))
+
+			continue
+		}
+
+		e.calls++
+
+		return e, nil
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, "no expectation matched")
+	}
+
+	return nil, fmt.Errorf("unexpected call %s %s, %s", r.Method, r.URL.RequestURI(), strings.Join(reasons, ", "))
+}
+
+func (e *ExpectedCall) missingPrereq() *ExpectedCall {
+	for _, prereq := range e.prereqs {
+		if !prereq.satisfied() {
+			return prereq
+		}
+	}
+
+	return nil
+}
+
+// AssertExpectations reports expectations called less than expected, it
+// reports once, later calls do nothing.
+func (c *Controller) AssertExpectations(t TestReporter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.asserted {
+		return
+	}
+
+	c.asserted = true
+
+	for _, e := range c.expected {
+		if !e.satisfied() {
+			t.Errorf("missing calls of %s, expected at least %d, actual %d", e.String(), e.min, e.calls)
+		}
+	}
+}
+
+// collectTestReporter collects errors instead of reporting them.
+type collectTestReporter struct {
+	errors []string
+}
+
+func (c *collectTestReporter) Errorf(format string, args ...any) {
+	c.errors = append(c.errors, fmt.Sprintf(format, args...))
+}
+
+func (c *collectTestReporter) Fatalf(format string, args ...any) {
+	c.Errorf(format, args...)
+}
+
+func (c *collectTestReporter) Cleanup(func()) {}
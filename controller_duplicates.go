@@ -0,0 +1,63 @@
+package httpmock
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// checkDuplicate reports the expectation added last when its input is
+// indistinguishable from an earlier one: a shadowed expectation, behind an
+// unbounded one without After, can never match and fails the test, a
+// reachable one matches only once the earlier is exhausted and is logged
+// with Logf when the reporter has it. Called with c.mu held.
+func (c *Controller) checkDuplicate() {
+	i := len(c.expected) - 1
+	later := c.expected[i]
+
+	for j, earlier := range c.expected[:i] {
+		if !sameInput(earlier.input, later.input) {
+			continue
+		}
+
+		switch {
+		case earlier.max < 0 && len(earlier.prereqs) == 0:
+			c.t.Errorf("expectation %d %s defined at %s is shadowed by expectation %d with the same input expected any times", i+1, later.String(), later.site, j+1)
+		default:
+			if logger, ok := c.t.(interface {
+				Logf(format string, args ...any)
+			}); ok {
+				logger.Logf("expectation %d %s defined at %s has the same input as expectation %d, it matches once expectation %d is exhausted", i+1, later.String(), later.site, j+1, j+1)
+			}
+		}
+
+		return
+	}
+}
+
+// sameInput reports inputs no request can tell apart, inputs with
+// functions, e.g. QueryMatch rules, are treated as distinct.
+func sameInput(a, b Input) bool {
+	aBody, bBody := a.Body, b.Body
+	a.Body, b.Body = nil, nil
+
+	if a.URL != nil && b.URL != nil {
+		if a.URL.String() != b.URL.String() {
+			return false
+		}
+
+		a.URL, b.URL = nil, nil
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		return false
+	}
+
+	if aBody == nil || bBody == nil {
+		return aBody == nil && bBody == nil
+	}
+
+	aBytes, aErr := aBody.Bytes()
+	bBytes, bErr := bBody.Bytes()
+
+	return aErr == nil && bErr == nil && reflect.TypeOf(aBody) == reflect.TypeOf(bBody) && bytes.Equal(aBytes, bBytes)
+}
@@ -0,0 +1,141 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func controllerDo(t *testing.T, client *http.Client, method, target string) (*http.Response, error) {
+	req, err := http.NewRequest(method, "http://localhost"+target, http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+
+	return resp, err
+}
+
+func Test_Controller(t *testing.T) {
+	ctrl := NewController(t)
+
+	login := ctrl.EXPECT().Request(http.MethodPost, "/login").Return(Response{StatusCode: http.StatusNoContent})
+	users := ctrl.EXPECT().Request(http.MethodGet, "/users").Times(2).After(login)
+	ctrl.EXPECT().Request(http.MethodGet, "/health").AnyTimes()
+	logout := ctrl.EXPECT().Request(http.MethodPost, "/logout")
+
+	InOrder(users, logout)
+
+	client := &http.Client{Transport: ctrl.Transport()}
+
+	for _, step := range []struct{ method, target string }{
+		{http.MethodGet, "/health"},
+		{http.MethodPost, "/login"},
+		{http.MethodGet, "/users"},
+		{http.MethodGet, "/health"},
+		{http.MethodGet, "/users"},
+		{http.MethodPost, "/logout"},
+	} {
+		_, err := controllerDo(t, client, step.method, step.target)
+		if err != nil {
+			t.Fatalf("%s %s, unexpected error: %s", step.method, step.target, err)
+		}
+	}
+}
+
+func Test_Controller_Violations(t *testing.T) {
+	reporter := ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "%s", args: []any{"unexpected call GET /users, GET /users is expected after POST /login"}},
+			{format: "%s", args: []any{"unexpected call GET /orders, no expectation matched"}},
+			{format: "%s", args: []any{"unexpected call POST /login, POST /login has already been called 1 times"}},
+			{format: "missing calls of %s, expected at least %d, actual %d", args: []any{"GET /users", 1, 0}},
+		},
+		nil,
+	)(t)
+
+	ctrl := NewController(reporter)
+
+	login := ctrl.EXPECT().Request(http.MethodPost, "/login")
+	ctrl.EXPECT().Request(http.MethodGet, "/users").After(login)
+
+	client := &http.Client{Transport: ctrl.Transport()}
+
+	for _, step := range []struct{ method, target string }{
+		{http.MethodGet, "/users"},
+		{http.MethodGet, "/orders"},
+		{http.MethodPost, "/login"},
+		{http.MethodPost, "/login"},
+	} {
+		_, _ = controllerDo(t, client, step.method, step.target)
+	}
+}
+
+func Test_Controller_UnexpectedCallEscapes(t *testing.T) {
+	ctrl := NewController(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "%s", args: []any{"unexpected call GET /b%20c?q=%41, no expectation matched"}},
+		},
+		nil,
+	)(t))
+
+	_, _ = controllerDo(t, &http.Client{Transport: ctrl.Transport()}, http.MethodGet, "/b%20c?q=%41")
+}
+
+func Test_Controller_ReturnError(t *testing.T) {
+	ctrl := NewController(t)
+
+	doErr := errors.New("connection refused")
+
+	ctrl.EXPECT().Call(Input{Method: http.MethodGet}).ReturnError(doErr).MinTimes(1)
+
+	_, err := controllerDo(t, &http.Client{Transport: ctrl.Transport()}, http.MethodGet, "/any")
+	if !errors.Is(err, doErr) {
+		t.Errorf("wrong error, expected %s, actual %v", doErr, err)
+	}
+
+	ctrl.AssertExpectations(t)
+}
+
+func Test_Controller_Duplicates(t *testing.T) {
+	reporter := &testReporterMock{t: t}
+
+	ctrl := NewController(reporter)
+
+	ctrl.EXPECT().Request(http.MethodGet, "/health").AnyTimes()
+	_, _, line, _ := runtime.Caller(0)
+	ctrl.EXPECT().Request(http.MethodGet, "/health").AnyTimes()
+	ctrl.EXPECT().Request(http.MethodGet, "/users")
+	ctrl.EXPECT().Request(http.MethodGet, "/users").Return(Response{StatusCode: http.StatusNotFound})
+
+	expected := []testReporterCall{
+		{
+			format: "expectation %d %s defined at %s is shadowed by expectation %d with the same input expected any times",
+			args:   []any{2, "GET /health", callSite(fmt.Sprintf("controller_test.go:%d", line+1)), 1},
+		},
+	}
+
+	if !reflect.DeepEqual(reporter.errorfCalls, expected) {
+		t.Fatalf("duplicates not reported when added,\nexpected %v,\n\nactual %v", expected, reporter.errorfCalls)
+	}
+
+	client := &http.Client{Transport: ctrl.Transport()}
+
+	for _, target := range []string{"/health", "/users", "/users"} {
+		_, err := controllerDo(t, client, http.MethodGet, target)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(reporter.errorfCalls) != 1 {
+		t.Errorf("wrong errorf calls %v", reporter.errorfCalls)
+	}
+}
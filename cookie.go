@@ -0,0 +1,120 @@
+package httpmock
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+type cookieRoundTrip struct {
+	cookie *http.Cookie
+
+	mu    sync.Mutex
+	set   bool
+	setAt time.Time
+	path  string
+}
+
+// CookieRoundTrip sets cookie in the response of the first call and checks
+// that every later request presents it while the request path is in the
+// cookie path scope and the cookie is not expired by Expires or MaxAge, and
+// does not present it otherwise. handleCall serves the calls, nil means
+// HandleCallCompareInput.
+func CookieRoundTrip(cookie *http.Cookie, handleCall HandleCall) HandleCall {
+	if handleCall == nil {
+		handleCall = HandleCallCompareInput
+	}
+
+	c := &cookieRoundTrip{cookie: cookie}
+
+	return func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		if c.setCookie(w, r) {
+			handleCall(t, w, r, call)
+
+			return
+		}
+
+		c.compare(t, r)
+
+		handleCall(t, w, r, call)
+	}
+}
+
+func (c *cookieRoundTrip) setCookie(w http.ResponseWriter, r *http.Request) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.set {
+		return false
+	}
+
+	c.set = true
+	c.setAt = time.Now()
+
+	c.path = c.cookie.Path
+	if c.path == "" || !strings.HasPrefix(c.path, "/") {
+		c.path = defaultCookiePath(r.URL.Path)
+	}
+
+	http.SetCookie(w, c.cookie)
+
+	return true
+}
+
+func (c *cookieRoundTrip) compare(t TestReporter, r *http.Request) {
+	c.mu.Lock()
+	expected := c.pathMatch(r.URL.Path) && !c.expired(time.Now())
+	c.mu.Unlock()
+
+	presented, err := r.Cookie(c.cookie.Name)
+
+	switch {
+	case expected && err != nil:
+		t.Errorf("cookie %s not presented", c.cookie.Name)
+	case expected && presented.Value != c.cookie.Value:
+		t.Errorf("wrong cookie %s value, expected %s, actual %s", c.cookie.Name, c.cookie.Value, presented.Value)
+	case !expected && err == nil:
+		t.Errorf("cookie %s presented out of its path scope or after expiry, path %s", c.cookie.Name, r.URL.Path)
+	}
+}
+
+func (c *cookieRoundTrip) expired(now time.Time) bool {
+	switch {
+	case c.cookie.MaxAge < 0:
+		return true
+	case c.cookie.MaxAge > 0:
+		return !now.Before(c.setAt.Add(time.Duration(c.cookie.MaxAge) * time.Second))
+	case !c.cookie.Expires.IsZero():
+		return !now.Before(c.cookie.Expires)
+	}
+
+	return false
+}
+
+// pathMatch implements RFC 6265 section 5.1.4.
+func (c *cookieRoundTrip) pathMatch(requestPath string) bool {
+	if requestPath == "" {
+		requestPath = "/"
+	}
+
+	if requestPath == c.path {
+		return true
+	}
+
+	if !strings.HasPrefix(requestPath, c.path) {
+		return false
+	}
+
+	return strings.HasSuffix(c.path, "/") || requestPath[len(c.path)] == '/'
+}
+
+// defaultCookiePath implements RFC 6265 section 5.1.4.
+func defaultCookiePath(requestPath string) string {
+	if requestPath == "" || requestPath[0] != '/' || strings.Count(requestPath, "/") == 1 {
+		return "/"
+	}
+
+	return path.Dir(requestPath)
+}
@@ -0,0 +1,118 @@
+package httpmock
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"testing"
+	"time"
+)
+
+func cookieRoundTripClient(t *testing.T, cookie *http.Cookie, paths ...string) {
+	calls := make([]Call, 0, len(paths))
+	for range paths {
+		calls = append(calls, Call{Input: Input{Method: http.MethodGet}})
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{
+		Transport: NewTransport(t, SequenceCalls(calls...), CookieRoundTrip(cookie, nil)),
+		Jar:       jar,
+	}
+
+	for _, path := range paths {
+		resp, err := client.Get("http://localhost" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+}
+
+func Test_CookieRoundTrip(t *testing.T) {
+	cookie := &http.Cookie{Name: "session", Value: "abc", Path: "/api"}
+
+	cookieRoundTripClient(t, cookie, "/api/login", "/api/users", "/api", "/apiv2", "/")
+}
+
+func Test_CookieRoundTrip_DefaultPath(t *testing.T) {
+	cookie := &http.Cookie{Name: "session", Value: "abc"}
+
+	cookieRoundTripClient(t, cookie, "/account/login", "/account/profile", "/orders")
+}
+
+func Test_CookieRoundTrip_Expired(t *testing.T) {
+	cookie := &http.Cookie{Name: "session", Value: "abc", Path: "/", Expires: time.Now().Add(-time.Hour)}
+
+	cookieRoundTripClient(t, cookie, "/login", "/profile")
+}
+
+type leakyCookieTransport struct {
+	base http.RoundTripper
+}
+
+func (l leakyCookieTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Cookie", "session=stale")
+
+	return l.base.RoundTrip(r)
+}
+
+func Test_CookieRoundTrip_Violations(t *testing.T) {
+	t.Run("not presented", func(t *testing.T) {
+		cookie := &http.Cookie{Name: "session", Value: "abc", Path: "/"}
+
+		transport := NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "2 call, cookie %s not presented", args: []any{"session"}},
+				},
+				nil,
+			)(t),
+			StaticCalls(Call{Input: Input{Method: http.MethodGet}}),
+			CookieRoundTrip(cookie, nil),
+		)
+
+		client := &http.Client{Transport: transport}
+
+		for range 2 {
+			resp, err := client.Get("http://localhost/profile")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp.Body.Close()
+		}
+	})
+
+	t.Run("wrong value and out of scope", func(t *testing.T) {
+		cookie := &http.Cookie{Name: "session", Value: "abc", Path: "/api"}
+
+		transport := NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "2 call, wrong cookie %s value, expected %s, actual %s", args: []any{"session", "abc", "stale"}},
+					{format: "3 call, cookie %s presented out of its path scope or after expiry, path %s", args: []any{"session", "/web"}},
+				},
+				nil,
+			)(t),
+			StaticCalls(Call{Input: Input{Method: http.MethodGet}}),
+			CookieRoundTrip(cookie, nil),
+		)
+
+		client := &http.Client{Transport: leakyCookieTransport{base: transport}}
+
+		for _, path := range []string{"/api/login", "/api/users", "/web"} {
+			resp, err := client.Get("http://localhost" + path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp.Body.Close()
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package httpmock
+
+import (
+	"context"
+	"time"
+)
+
+// Deadline is the window of time left until the request context deadline
+// when the request is handled, zero Min or Max is not checked.
+//
+// Server requests never carry the client deadline, so it is useful with
+// NewTransport only.
+type Deadline struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// AnyDeadline requires a deadline without checking its value.
+func AnyDeadline() *Deadline {
+	return &Deadline{}
+}
+
+func CompareDeadline(t TestReporter, ctx context.Context, inputDeadline *Deadline) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	if inputDeadline == nil {
+		return
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Errorf("request context has no deadline")
+
+		return
+	}
+
+	left := time.Until(deadline)
+
+	if inputDeadline.Min > 0 && left < inputDeadline.Min {
+		t.Errorf("request deadline too close, expected at least %s, actual %s", inputDeadline.Min, left.Round(time.Millisecond))
+	}
+
+	if inputDeadline.Max > 0 && left > inputDeadline.Max {
+		t.Errorf("request deadline too far, expected at most %s, actual %s", inputDeadline.Max, left.Round(time.Millisecond))
+	}
+}
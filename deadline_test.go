@@ -0,0 +1,82 @@
+package httpmock
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func doWithTimeout(t *testing.T, transport http.RoundTripper, timeout time.Duration) {
+	ctx := context.Background()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/items", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_CompareDeadline(t *testing.T) {
+	t.Run("any deadline", func(t *testing.T) {
+		transport := NewTransport(t,
+			SequenceCalls(Call{Input: Input{Method: http.MethodGet, Deadline: AnyDeadline()}}),
+			nil,
+		)
+
+		doWithTimeout(t, transport, time.Second)
+	})
+
+	t.Run("deadline in window", func(t *testing.T) {
+		transport := NewTransport(t,
+			SequenceCalls(Call{Input: Input{Method: http.MethodGet, Deadline: &Deadline{Min: time.Second, Max: 10 * time.Second}}}),
+			nil,
+		)
+
+		doWithTimeout(t, transport, 5*time.Second)
+	})
+
+	t.Run("no deadline", func(t *testing.T) {
+		transport := NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "1 call, request context has no deadline"},
+				},
+				nil,
+			)(t),
+			SequenceCalls(Call{Input: Input{Method: http.MethodGet, Deadline: AnyDeadline()}}),
+			nil,
+		)
+
+		doWithTimeout(t, transport, 0)
+	})
+
+	t.Run("deadline too far", func(t *testing.T) {
+		reporter := &testReporterMock{t: t}
+
+		transport := NewTransport(
+			reporter,
+			SequenceCalls(Call{Input: Input{Method: http.MethodGet, Deadline: &Deadline{Max: time.Second}}}),
+			nil,
+		)
+
+		doWithTimeout(t, transport, time.Minute)
+
+		if len(reporter.errorfCalls) != 1 || reporter.errorfCalls[0].format != "1 call, request deadline too far, expected at most %s, actual %s" {
+			t.Errorf("wrong reported errors, actual %+v", reporter.errorfCalls)
+		}
+	})
+}
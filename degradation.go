@@ -0,0 +1,155 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// ErrDegradationDrop is returned by transports for calls dropped by
+// BurstDrops.
+var ErrDegradationDrop = errors.New("call dropped by degradation profile")
+
+// DegradationProfile is a reproducible failure model applied to calls by
+// Degrade, every call decides its fault from the seed and its ordinal only,
+// so the same seed fails the same calls regardless of timing.
+type DegradationProfile struct {
+	Name string
+
+	fault func(seed uint64, calledTimes int) Fault
+}
+
+// FlakyErrors answers rate of calls with the status code and no body.
+func FlakyErrors(rate float64, statusCode int) DegradationProfile {
+	return DegradationProfile{
+		Name: fmt.Sprintf("flaky-%gpct-errors", rate*100),
+		fault: func(seed uint64, calledTimes int) Fault {
+			if degradationRand(seed, calledTimes).Float64() >= rate {
+				return nil
+			}
+
+			return FaultStatus(statusCode, nil)
+		},
+	}
+}
+
+// SlowTail delays calls above the percentile, e.g. 0.99 delays one call of
+// a hundred.
+func SlowTail(percentile float64, delay time.Duration) DegradationProfile {
+	return DegradationProfile{
+		Name: fmt.Sprintf("slow-p%g-%s", percentile*100, delay),
+		fault: func(seed uint64, calledTimes int) Fault {
+			if degradationRand(seed, calledTimes).Float64() < percentile {
+				return nil
+			}
+
+			return func(call Call) Call {
+				call.Delay += delay
+
+				return call
+			}
+		},
+	}
+}
+
+// BurstDrops drops runs of length consecutive calls, a run starts at rate
+// of calls. Dropped calls compare their input and fail like Call.DoError
+// with ErrDegradationDrop, servers abort the connection.
+func BurstDrops(rate float64, length int) DegradationProfile {
+	return DegradationProfile{
+		Name: fmt.Sprintf("burst-drops-%gpct-%d", rate*100, length),
+		fault: func(seed uint64, calledTimes int) Fault {
+			for start := max(1, calledTimes-length+1); start <= calledTimes; start++ {
+				if degradationRand(seed^0x9e3779b97f4a7c15, start).Float64() < rate {
+					return FaultError(ErrDegradationDrop)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+var degradationProfiles = map[string]DegradationProfile{
+	"flaky-5pct-errors": FlakyErrors(0.05, http.StatusServiceUnavailable),
+	"slow-p99-2s":       SlowTail(0.99, 2*time.Second),
+	"burst-drops":       BurstDrops(0.02, 5),
+}
+
+// LookupDegradationProfile returns the shipped profile by name:
+// flaky-5pct-errors answers 5% of calls with 503, slow-p99-2s delays 1% of
+// calls by 2s and burst-drops drops runs of 5 calls starting at 2% of calls.
+func LookupDegradationProfile(name string) (DegradationProfile, bool) {
+	profile, ok := degradationProfiles[name]
+	if ok {
+		profile.Name = name
+	}
+
+	return profile, ok
+}
+
+type degradedCalls struct {
+	calls    Calls
+	seed     uint64
+	profiles []DegradationProfile
+}
+
+// Degrade applies the profiles to the calls with the seed and labels
+// degraded calls with the profile name, e.g. "4 call [degradation=slow-p99-2s]".
+// The input of degraded calls is kept and compared, calls dropped by
+// BurstDrops compare it before they fail.
+func Degrade(calls Calls, seed uint64, profiles ...DegradationProfile) Calls {
+	return degradedCalls{
+		calls:    calls,
+		seed:     seed,
+		profiles: profiles,
+	}
+}
+
+func (d degradedCalls) Call(calledTimes int) (Call, bool) {
+	return d.callRequest(calledTimes, nil)
+}
+
+func (d degradedCalls) callRequest(calledTimes int, r *http.Request) (Call, bool) {
+	call, ok := callFor(d.calls, calledTimes, r)
+	if !ok {
+		return call, false
+	}
+
+	for _, profile := range d.profiles {
+		fault := profile.fault(d.seed, calledTimes)
+		if fault == nil {
+			continue
+		}
+
+		input := call.Input
+
+		call = fault(call)
+		call.Input = input
+		call.faulted = true
+
+		call.Labels = maps.Clone(call.Labels)
+		if call.Labels == nil {
+			call.Labels = make(map[string]string)
+		}
+
+		call.Labels["degradation"] = profile.Name
+	}
+
+	return call, true
+}
+
+func (d degradedCalls) validate(marshalJSON bool) error {
+	return validateWrapped(d.calls, marshalJSON)
+}
+
+func (d degradedCalls) Done(calledTimes int) bool {
+	return d.calls.Done(calledTimes)
+}
+
+func degradationRand(seed uint64, calledTimes int) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, uint64(calledTimes)))
+}
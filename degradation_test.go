@@ -0,0 +1,119 @@
+package httpmock
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+func degradedStatuses(calls Calls, count int) []int {
+	statuses := make([]int, count)
+
+	for i := range statuses {
+		call, _ := calls.Call(i + 1)
+		statuses[i] = call.Response.StatusCode
+	}
+
+	return statuses
+}
+
+func Test_Degrade_FlakyErrors(t *testing.T) {
+	calls := StaticCalls(Call{Input: Input{Method: http.MethodGet}, Response: Response{StatusCode: http.StatusOK}})
+
+	profile, ok := LookupDegradationProfile("flaky-5pct-errors")
+	if !ok {
+		t.Fatal("flaky-5pct-errors profile not found")
+	}
+
+	statuses := degradedStatuses(Degrade(calls, 42, profile), 1000)
+
+	if again := degradedStatuses(Degrade(calls, 42, profile), 1000); !slices.Equal(statuses, again) {
+		t.Fatal("same seed degraded different calls")
+	}
+
+	if other := degradedStatuses(Degrade(calls, 7, profile), 1000); slices.Equal(statuses, other) {
+		t.Fatal("different seeds degraded the same calls")
+	}
+
+	failed := 0
+
+	for _, status := range statuses {
+		if status == http.StatusServiceUnavailable {
+			failed++
+		}
+	}
+
+	if failed < 25 || failed > 75 {
+		t.Errorf("wrong failed calls count, expected about 50, actual %d", failed)
+	}
+}
+
+func Test_Degrade_Profiles(t *testing.T) {
+	calls := Degrade(
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}}),
+		1,
+		SlowTail(0.5, time.Second),
+		BurstDrops(0.1, 3),
+	)
+
+	var (
+		delayed     int
+		dropped     []int
+		delayLabels int
+	)
+
+	for i := 1; i <= 200; i++ {
+		call, _ := calls.Call(i)
+
+		if call.Delay == time.Second {
+			delayed++
+		}
+
+		if call.Labels["degradation"] == "slow-p50-1s" || call.Labels["degradation"] == "burst-drops-10pct-3" {
+			delayLabels++
+		}
+
+		if errors.Is(call.DoError, ErrDegradationDrop) {
+			dropped = append(dropped, i)
+		}
+
+		if call.Input.Method != http.MethodGet {
+			t.Fatalf("call %d lost its input", i)
+		}
+	}
+
+	if delayed < 70 || delayed > 130 {
+		t.Errorf("wrong delayed calls count, expected about 100, actual %d", delayed)
+	}
+
+	if len(dropped) == 0 || delayLabels == 0 {
+		t.Fatalf("no calls degraded, dropped %v", dropped)
+	}
+
+	first := dropped[0]
+	if !slices.Equal(dropped[:3], []int{first, first + 1, first + 2}) {
+		t.Errorf("drops are not a burst, dropped %v", dropped)
+	}
+}
+
+func Test_Degrade_DropComparesInput(t *testing.T) {
+	calls := Degrade(StaticCalls(Call{Input: Input{Method: http.MethodPost}}), 1, BurstDrops(1, 1))
+
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "1 call [degradation=burst-drops-100pct-1], wrong r.Method, expected %s, actual %s", args: []any{"POST", "GET"}},
+			},
+			nil,
+		)(t),
+		calls,
+		nil,
+	)
+
+	_, err := (&http.Client{Transport: transport}).Get("http://localhost/items")
+	if !errors.Is(err, ErrDegradationDrop) {
+		t.Errorf("expect dropped call error, actual %v", err)
+	}
+}
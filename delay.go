@@ -0,0 +1,57 @@
+package httpmock
+
+import (
+	"context"
+	"net/http"
+)
+
+// DelayPlacement is the point where HandleCallCompareInput waits for
+// Call.Delay.
+type DelayPlacement int
+
+const (
+	// DelayAfterResponse waits after the response is written and webhooks
+	// are scheduled, it is the default. RoundTrip returns after the delay,
+	// servers send an unflushed response when the handler returns, so the
+	// client sees the delay unless the body was flushed or exceeds the
+	// write buffer.
+	DelayAfterResponse DelayPlacement = iota
+	// DelayBeforeCompare waits before the input comparison, before the
+	// request body is read.
+	DelayBeforeCompare
+	// DelayBeforeResponse waits after the input comparison, before the
+	// response header is written, so clients see it as time to first byte.
+	DelayBeforeResponse
+)
+
+type delayPlacementContextKey struct{}
+
+// WithDelayPlacement selects where Call.Delay is waited for, see
+// DelayPlacement.
+func WithDelayPlacement(placement DelayPlacement) Option {
+	return func(o *options) {
+		o.delayPlacement = placement
+	}
+}
+
+func attachDelayPlacement(r *http.Request, placement DelayPlacement) *http.Request {
+	if placement == DelayAfterResponse {
+		return r
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), delayPlacementContextKey{}, placement))
+}
+
+// delayAt waits for the call delay when it is placed at placement.
+func delayAt(r *http.Request, call Call, placement DelayPlacement) {
+	if call.Delay <= 0 {
+		return
+	}
+
+	current, _ := r.Context().Value(delayPlacementContextKey{}).(DelayPlacement)
+	if current != placement {
+		return
+	}
+
+	sleepContext(r.Context(), call.Delay)
+}
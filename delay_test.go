@@ -0,0 +1,72 @@
+package httpmock
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_WithDelayPlacement(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	tests := []struct {
+		name                            string
+		placement                       DelayPlacement
+		compareDelayed, responseDelayed bool
+	}{
+		{name: "after response", placement: DelayAfterResponse},
+		{name: "before compare", placement: DelayBeforeCompare, compareDelayed: true, responseDelayed: true},
+		{name: "before response", placement: DelayBeforeResponse, responseDelayed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var compared, written time.Time
+
+			call := Call{
+				Input: Input{
+					Method: http.MethodPost,
+					Body: DynamicBody(func() ([]byte, error) {
+						compared = time.Now()
+
+						return []byte("ping"), nil
+					}),
+				},
+				Response: Response{
+					Body: DynamicBody(func() ([]byte, error) {
+						written = time.Now()
+
+						return []byte("pong"), nil
+					}),
+				},
+				Delay: delay,
+			}
+
+			client := &http.Client{
+				Transport: NewTransport(t, SequenceCalls(call), nil, WithDelayPlacement(tt.placement)),
+			}
+
+			start := time.Now()
+
+			resp, err := client.Post("http://localhost/ping", "text/plain", strings.NewReader("ping"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp.Body.Close()
+
+			if elapsed := time.Since(start); elapsed < delay {
+				t.Errorf("round trip not delayed, elapsed %s", elapsed)
+			}
+
+			if delayed := compared.Sub(start) >= delay; delayed != tt.compareDelayed {
+				t.Errorf("wrong compare delay, expected delayed %t, actual %s", tt.compareDelayed, compared.Sub(start))
+			}
+
+			if delayed := written.Sub(start) >= delay; delayed != tt.responseDelayed {
+				t.Errorf("wrong response delay, expected delayed %t, actual %s", tt.responseDelayed, written.Sub(start))
+			}
+		})
+	}
+}
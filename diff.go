@@ -0,0 +1,228 @@
+package httpmock
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContextLines = 3
+
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns a unified diff of the two texts, empty when they are
+// equal.
+func unifiedDiff(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromName, toName)
+
+	for start := 0; start < len(ops); {
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+
+		if start == len(ops) {
+			break
+		}
+
+		hunkStart := max(start-diffContextLines, 0)
+		hunkEnd := start
+
+		for unchanged := 0; hunkEnd < len(ops) && unchanged <= 2*diffContextLines; hunkEnd++ {
+			if ops[hunkEnd].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+
+		for hunkEnd > start && ops[hunkEnd-1].kind == ' ' {
+			hunkEnd--
+		}
+
+		hunkEnd = min(hunkEnd+diffContextLines, len(ops))
+
+		writeHunk(&builder, ops, hunkStart, hunkEnd)
+
+		start = hunkEnd
+	}
+
+	return builder.String()
+}
+
+func writeHunk(builder *strings.Builder, ops []diffOp, start, end int) {
+	fromLine, toLine := 1, 1
+
+	for _, op := range ops[:start] {
+		if op.kind != '+' {
+			fromLine++
+		}
+
+		if op.kind != '-' {
+			toLine++
+		}
+	}
+
+	fromCount, toCount := 0, 0
+
+	for _, op := range ops[start:end] {
+		if op.kind != '+' {
+			fromCount++
+		}
+
+		if op.kind != '-' {
+			toCount++
+		}
+	}
+
+	fmt.Fprintf(builder, "@@ -%s +%s @@\n", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount))
+
+	for _, op := range ops[start:end] {
+		builder.WriteByte(op.kind)
+		builder.WriteString(op.line)
+		builder.WriteByte('\n')
+	}
+}
+
+func hunkRange(line, count int) string {
+	if count == 0 {
+		line--
+	}
+
+	if count == 1 {
+		return fmt.Sprint(line)
+	}
+
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines builds the shortest edit script with the linear space variant
+// of the Myers O(ND) algorithm, so memory stays linear in the line count.
+func diffLines(from, to []string) []diffOp {
+	return appendDiff(make([]diffOp, 0, len(from)+len(to)), from, to)
+}
+
+// appendDiff trims the common prefix and suffix and splits the rest at the
+// middle snake until only insertions or deletions are left.
+func appendDiff(ops []diffOp, from, to []string) []diffOp {
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+
+	ops = appendOps(ops, ' ', from[:prefix])
+	from, to = from[prefix:], to[prefix:]
+
+	suffix := 0
+	for suffix < len(from) && suffix < len(to) && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+
+	common := from[len(from)-suffix:]
+	from, to = from[:len(from)-suffix], to[:len(to)-suffix]
+
+	switch {
+	case len(from) == 0:
+		ops = appendOps(ops, '+', to)
+	case len(to) == 0:
+		ops = appendOps(ops, '-', from)
+	default:
+		x, y, u, v := middleSnake(from, to)
+
+		ops = appendDiff(ops, from[:x], to[:y])
+		ops = appendOps(ops, ' ', from[x:u])
+		ops = appendDiff(ops, from[u:], to[v:])
+	}
+
+	return appendOps(ops, ' ', common)
+}
+
+func appendOps(ops []diffOp, kind byte, lines []string) []diffOp {
+	for _, line := range lines {
+		ops = append(ops, diffOp{kind: kind, line: line})
+	}
+
+	return ops
+}
+
+// middleSnake returns the snake from (x, y) to (u, v) in the middle of the
+// shortest edit script, searched from both ends at once. from and to are
+// not empty and differ in the first and the last lines, so both halves of
+// the script have fewer edits than the whole one.
+func middleSnake(from, to []string) (x, y, u, v int) {
+	n, m := len(from), len(to)
+	delta := n - m
+	odd := delta%2 != 0
+
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+
+	// forward[k] and backward[k] are the furthest x on diagonal k = x-y,
+	// backward paths run on the reversed lines.
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := furthest(forward, offset, k, d)
+			y := x - k
+			startX, startY := x, y
+
+			for x < n && y < m && from[x] == to[y] {
+				x++
+				y++
+			}
+
+			forward[offset+k] = x
+
+			if c := delta - k; odd && c >= -(d-1) && c <= d-1 && x+backward[offset+c] >= n {
+				return startX, startY, x, y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			x := furthest(backward, offset, k, d)
+			y := x - k
+			startX, startY := x, y
+
+			for x < n && y < m && from[n-1-x] == to[m-1-y] {
+				x++
+				y++
+			}
+
+			backward[offset+k] = x
+
+			if c := delta - k; !odd && c >= -d && c <= d && x+forward[offset+c] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+
+	panic("diff, middle snake not found")
+}
+
+// furthest returns the x a d-path on diagonal k starts its snake from.
+func furthest(v []int, offset, k, d int) int {
+	if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+		return v[offset+k+1]
+	}
+
+	return v[offset+k-1] + 1
+}
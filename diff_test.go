@@ -0,0 +1,80 @@
+package httpmock
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_unifiedDiff(t *testing.T) {
+	tests := []struct {
+		Name     string
+		From, To string
+		Expected string
+	}{
+		{
+			Name:     "equal",
+			From:     "a\nb\n",
+			To:       "a\nb\n",
+			Expected: "",
+		},
+		{
+			Name: "single change with context",
+			From: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			To:   "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			Expected: "--- a\n+++ b\n" +
+				"@@ -2,7 +2,7 @@\n" +
+				" 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			Name: "separate hunks",
+			From: "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			To:   "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			Expected: "--- a\n+++ b\n" +
+				"@@ -1,4 +1,4 @@\n" +
+				"-a\n+A\n 1\n 2\n 3\n" +
+				"@@ -7,4 +7,4 @@\n" +
+				" 6\n 7\n 8\n-b\n+B\n",
+		},
+		{
+			Name: "from empty",
+			From: "",
+			To:   "x\n",
+			Expected: "--- a\n+++ b\n" +
+				"@@ -0,0 +1 @@\n" +
+				"+x\n",
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.Name, func(t *testing.T) {
+			diff := unifiedDiff("a", "b", tst.From, tst.To)
+			if diff != tst.Expected {
+				t.Errorf("wrong diff,\nexpected:\n%s\nactual:\n%s", tst.Expected, diff)
+			}
+		})
+	}
+}
+
+func Test_unifiedDiff_LargeSnapshots(t *testing.T) {
+	from := make([]string, 50_000)
+	for i := range from {
+		from[i] = "line " + strconv.Itoa(i)
+	}
+
+	to := append([]string(nil), from...)
+	to[10] = "changed 10"
+	to[40_000] = "changed 40000"
+	to = append(to[:25_000], to[25_001:]...)
+
+	diff := unifiedDiff("a", "b", strings.Join(from, "\n")+"\n", strings.Join(to, "\n")+"\n")
+
+	expected := "--- a\n+++ b\n" +
+		"@@ -8,7 +8,7 @@\n line 7\n line 8\n line 9\n-line 10\n+changed 10\n line 11\n line 12\n line 13\n" +
+		"@@ -24998,7 +24998,6 @@\n line 24997\n line 24998\n line 24999\n-line 25000\n line 25001\n line 25002\n line 25003\n" +
+		"@@ -39998,7 +39997,7 @@\n line 39997\n line 39998\n line 39999\n-line 40000\n+changed 40000\n line 40001\n line 40002\n line 40003\n"
+
+	if diff != expected {
+		t.Errorf("wrong diff,\nexpected:\n%s\nactual:\n%s", expected, diff)
+	}
+}
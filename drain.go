@@ -0,0 +1,113 @@
+package httpmock
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type drainRequest struct {
+	atShutdown bool
+}
+
+// drainTracker counts requests in flight when the shutdown started and how
+// they finished.
+type drainTracker struct {
+	mu        sync.Mutex
+	active    map[*drainRequest]struct{}
+	completed int
+	aborted   int
+}
+
+func newDrainTracker() *drainTracker {
+	return &drainTracker{active: make(map[*drainRequest]struct{})}
+}
+
+func (d *drainTracker) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := d.begin()
+		defer d.end(request, r)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (d *drainTracker) begin() *drainRequest {
+	request := &drainRequest{}
+
+	d.mu.Lock()
+	d.active[request] = struct{}{}
+	d.mu.Unlock()
+
+	return request
+}
+
+func (d *drainTracker) end(request *drainRequest, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.active, request)
+
+	if !request.atShutdown {
+		return
+	}
+
+	if r.Context().Err() != nil {
+		d.aborted++
+	} else {
+		d.completed++
+	}
+}
+
+func (d *drainTracker) shutdown() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for request := range d.active {
+		request.atShutdown = true
+	}
+}
+
+// ShutdownGracefully stops accepting connections, ends open Watch
+// responses and waits until in-flight responses are written or ctx is done,
+// see AssertDrained.
+func (s *Server) ShutdownGracefully(ctx context.Context) error {
+	s.drain.shutdown()
+	s.transport.events.close()
+
+	return s.Config.Shutdown(ctx)
+}
+
+// DrainStats counts requests in flight when ShutdownGracefully started.
+type DrainStats struct {
+	// Completed requests were responded while the client waited.
+	Completed int
+	// Aborted requests were cancelled by the client or by a shutdown
+	// deadline before their response was written.
+	Aborted int
+}
+
+// DrainStats returns how requests in flight at ShutdownGracefully finished.
+func (s *Server) DrainStats() DrainStats {
+	s.drain.mu.Lock()
+	defer s.drain.mu.Unlock()
+
+	return DrainStats{
+		Completed: s.drain.completed,
+		Aborted:   s.drain.aborted,
+	}
+}
+
+// AssertDrained reports requests in flight at ShutdownGracefully which the
+// client abandoned instead of waiting for their responses.
+func (s *Server) AssertDrained(t TestReporter) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	stats := s.DrainStats()
+
+	if stats.Aborted > 0 {
+		t.Errorf("in-flight requests not drained, completed %d, aborted %d", stats.Completed, stats.Aborted)
+	}
+}
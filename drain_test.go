@@ -0,0 +1,121 @@
+package httpmock
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func drainServer(reporter TestReporter, concurrency *Concurrency) *Server {
+	return NewServer(reporter,
+		StaticCalls(Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{StatusCode: http.StatusOK, Body: RawBody("ok")},
+			Delay:    100 * time.Millisecond,
+		}),
+		nil,
+		WithConcurrency(concurrency),
+	)
+}
+
+func waitInFlight(t *testing.T, concurrency *Concurrency) {
+	deadline := time.Now().Add(time.Second)
+
+	for concurrency.InFlight() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("request not in flight")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_Server_ShutdownGracefully(t *testing.T) {
+	concurrency := &Concurrency{}
+
+	server := drainServer(t, concurrency)
+
+	client := server.Client()
+
+	done := make(chan error, 1)
+
+	go func() {
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		done <- err
+	}()
+
+	waitInFlight(t, concurrency)
+
+	err := server.ShutdownGracefully(context.Background())
+	if err != nil {
+		t.Fatalf("shutdown, unexpected error: %s", err)
+	}
+
+	err = <-done
+	if err != nil {
+		t.Fatalf("in-flight request, unexpected error: %s", err)
+	}
+
+	_, err = client.Get(server.URL)
+	if err == nil {
+		t.Fatal("expect error on request after shutdown")
+	}
+
+	if stats := server.DrainStats(); stats != (DrainStats{Completed: 1}) {
+		t.Errorf("wrong drain stats, expected {1 0}, actual %+v", stats)
+	}
+
+	server.AssertDrained(t)
+}
+
+func Test_Server_AssertDrained(t *testing.T) {
+	concurrency := &Concurrency{}
+
+	server := drainServer(t, concurrency)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, http.NoBody)
+
+		resp, err := server.Client().Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	waitInFlight(t, concurrency)
+
+	shutdown := make(chan error, 1)
+
+	go func() {
+		shutdown <- server.ShutdownGracefully(context.Background())
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+
+	err := <-shutdown
+	if err != nil {
+		t.Fatalf("shutdown, unexpected error: %s", err)
+	}
+
+	server.AssertDrained(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "in-flight requests not drained, completed %d, aborted %d", args: []any{0, 1}},
+			},
+			nil,
+		)(t),
+	)
+}
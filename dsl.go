@@ -0,0 +1,282 @@
+package httpmock
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mock collects expectations written in chains, for users migrating from
+// gock-like libraries:
+//
+//	mock := httpmock.New(t)
+//	mock.Get("/users").MatchHeader("Accept", "application/json").Reply(http.StatusOK).JSON(users)
+//
+//	client := mock.Client()
+//
+// Expectations compile to calls served in the order they were declared and
+// may be added after the client is created. Expectations with identical
+// inputs are served in turn, they are not reported as duplicates like
+// Controller ones.
+type Mock struct {
+	t TestReporter
+
+	mu           sync.Mutex
+	expectations []*Expectation
+	ids          map[string]int
+}
+
+// Expectation is the input part of the chain, see Mock.
+type Expectation struct {
+	mock *Mock
+	call Call
+}
+
+// Reply is the response part of the chain, see Mock.
+type Reply struct {
+	expectation *Expectation
+	builder     ResponseBuilder
+}
+
+func New(t TestReporter) *Mock {
+	return &Mock{t: t}
+}
+
+func (m *Mock) Get(target string) *Expectation {
+	return m.Request(http.MethodGet, target)
+}
+
+func (m *Mock) Head(target string) *Expectation {
+	return m.Request(http.MethodHead, target)
+}
+
+func (m *Mock) Post(target string) *Expectation {
+	return m.Request(http.MethodPost, target)
+}
+
+func (m *Mock) Put(target string) *Expectation {
+	return m.Request(http.MethodPut, target)
+}
+
+func (m *Mock) Patch(target string) *Expectation {
+	return m.Request(http.MethodPatch, target)
+}
+
+func (m *Mock) Delete(target string) *Expectation {
+	return m.Request(http.MethodDelete, target)
+}
+
+// Request expects the method and the target path with an optional query,
+// absolute urls are accepted, only their path and query are compared.
+func (m *Mock) Request(method, target string) *Expectation {
+	u, err := url.Parse(target)
+	if err != nil {
+		m.t.Fatalf("parse expectation target %s, %s", target, err)
+
+		return &Expectation{mock: m}
+	}
+
+	e := &Expectation{
+		mock: m,
+		call: Call{
+			Input: Input{
+				Method: method,
+				URL:    u,
+			},
+			Response: Response{StatusCode: http.StatusOK},
+		},
+	}
+
+	m.mu.Lock()
+	m.expectations = append(m.expectations, e)
+	m.mu.Unlock()
+
+	return e
+}
+
+// Calls returns the expectations as calls, see SequenceCalls.
+func (m *Mock) Calls() Calls {
+	return mockCalls{mock: m}
+}
+
+// Transport returns NewTransport serving the expectations.
+func (m *Mock) Transport(opts ...Option) http.RoundTripper {
+	return NewTransport(m.t, m.Calls(), nil, opts...)
+}
+
+// Client returns NewClient serving the expectations.
+func (m *Mock) Client(opts ...Option) *http.Client {
+	return NewClient(m.t, m.Calls(), opts...)
+}
+
+// MatchHeader expects the header value, values are compared exactly.
+func (e *Expectation) MatchHeader(key, value string) *Expectation {
+	return e.update(func(call *Call) {
+		call.Input.Header = cloneHeader(call.Input.Header)
+		call.Input.Header.Add(key, value)
+	})
+}
+
+// MatchQuery expects the query parameter value.
+func (e *Expectation) MatchQuery(key, value string) *Expectation {
+	return e.update(func(call *Call) {
+		u := *call.Input.URL
+
+		query := u.Query()
+		query.Add(key, value)
+
+		u.RawQuery = query.Encode()
+		call.Input.URL = &u
+	})
+}
+
+// MatchBody expects the request body, see CompareBody.
+func (e *Expectation) MatchBody(body Body) *Expectation {
+	return e.update(func(call *Call) {
+		call.Input.Body = body
+	})
+}
+
+// MatchJSON expects the JSON encoded value as request body.
+func (e *Expectation) MatchJSON(value any) *Expectation {
+	return e.MatchBody(JSONBody(value))
+}
+
+// Reply starts the response with the status code.
+func (e *Expectation) Reply(statusCode int) *Reply {
+	r := &Reply{expectation: e, builder: Resp(statusCode)}
+
+	r.apply()
+
+	return r
+}
+
+// ReplyError makes the call fail with err, see Call.DoError.
+func (e *Expectation) ReplyError(err error) {
+	e.update(func(call *Call) {
+		call.DoError = err
+	})
+}
+
+// Delay delays the response, see Call.Delay.
+func (e *Expectation) Delay(delay time.Duration) *Expectation {
+	return e.update(func(call *Call) {
+		call.Delay = delay
+	})
+}
+
+func (e *Expectation) update(update func(call *Call)) *Expectation {
+	e.mock.mu.Lock()
+	defer e.mock.mu.Unlock()
+
+	if e.call.Input.URL == nil {
+		e.call.Input.URL = &url.URL{}
+	}
+
+	update(&e.call)
+
+	return e
+}
+
+func (r *Reply) Body(body Body) *Reply {
+	r.builder = r.builder.Body(body)
+
+	return r.apply()
+}
+
+func (r *Reply) JSON(value any) *Reply {
+	r.builder = r.builder.JSON(value)
+
+	return r.apply()
+}
+
+func (r *Reply) Text(text string) *Reply {
+	r.builder = r.builder.Text(text)
+
+	return r.apply()
+}
+
+func (r *Reply) Header(key, value string) *Reply {
+	r.builder = r.builder.Header(key, value)
+
+	return r.apply()
+}
+
+func (r *Reply) SetHeader(key, value string) *Reply {
+	r.builder = r.builder.SetHeader(key, value)
+
+	return r.apply()
+}
+
+func (r *Reply) Cookie(cookie *http.Cookie) *Reply {
+	r.builder = r.builder.Cookie(cookie)
+
+	return r.apply()
+}
+
+// Created replies 201 Created with the Location built from the pattern and
+// expects a follow-up GET of that location, {id} in the pattern is replaced
+// with ids counted per pattern from 1. It returns the reply of the GET.
+//
+//	mock.Post("/users").Reply(http.StatusCreated).Created("/users/{id}").JSON(user)
+func (r *Reply) Created(pattern string) *Reply {
+	mock := r.expectation.mock
+
+	mock.mu.Lock()
+
+	if mock.ids == nil {
+		mock.ids = make(map[string]int)
+	}
+
+	mock.ids[pattern]++
+	id := mock.ids[pattern]
+
+	mock.mu.Unlock()
+
+	return r.CreatedID(pattern, strconv.Itoa(id))
+}
+
+// CreatedID is Created with the id chosen by the test, e.g. the id the
+// client sends.
+func (r *Reply) CreatedID(pattern, id string) *Reply {
+	location := strings.ReplaceAll(pattern, "{id}", id)
+
+	r.builder.response.StatusCode = http.StatusCreated
+	r.builder = r.builder.SetHeader("Location", location)
+	r.apply()
+
+	return r.expectation.mock.Get(location).Reply(http.StatusOK)
+}
+
+func (r *Reply) apply() *Reply {
+	r.expectation.update(func(call *Call) {
+		call.Response = r.builder.Response()
+	})
+
+	return r
+}
+
+type mockCalls struct {
+	mock *Mock
+}
+
+func (m mockCalls) Call(calledTimes int) (Call, bool) {
+	m.mock.mu.Lock()
+	defer m.mock.mu.Unlock()
+
+	if calledTimes > len(m.mock.expectations) {
+		return Call{}, false
+	}
+
+	return m.mock.expectations[calledTimes-1].call, true
+}
+
+func (m mockCalls) Done(calledTimes int) bool {
+	m.mock.mu.Lock()
+	defer m.mock.mu.Unlock()
+
+	return calledTimes == len(m.mock.expectations)
+}
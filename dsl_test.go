@@ -0,0 +1,125 @@
+package httpmock
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_Mock(t *testing.T) {
+	mock := New(t)
+
+	mock.Get("/users").
+		MatchHeader("Accept", "application/json").
+		MatchQuery("role", "admin").
+		Reply(http.StatusOK).
+		JSON([]string{"amidman"}).
+		Header("X-Total", "1")
+
+	client := mock.Client()
+
+	mock.Post("http://api.local/users").MatchJSON(map[string]string{"name": "bob"}).Reply(http.StatusCreated)
+
+	doErr := errors.New("connection reset")
+	mock.Delete("/users/1").ReplyError(doErr)
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/users?role=admin", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != `["amidman"]` || resp.Header.Get("X-Total") != "1" || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("wrong response, body %s, header %v", body, resp.Header)
+	}
+
+	resp, err = client.Post("http://localhost/users", "application/json", strings.NewReader(`{"name":"bob"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("wrong status code, expected %d, actual %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	req, err = http.NewRequest(http.MethodDelete, "http://localhost/users/1", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Do(req)
+	if !errors.Is(err, doErr) {
+		t.Errorf("wrong error, expected %s, actual %v", doErr, err)
+	}
+}
+
+func Test_Mock_Unmatched(t *testing.T) {
+	mock := New(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "1 call, wrong r.Method, expected %s, actual %s", args: []any{http.MethodPut, http.MethodGet}},
+			{format: "assert handler calls, not all calls were handled"},
+		},
+		nil,
+	)(t))
+
+	mock.Put("/items/1").Reply(http.StatusNoContent)
+	mock.Get("/items/1").Reply(http.StatusOK)
+
+	resp, err := mock.Client().Get("http://localhost/items/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_Reply_Created(t *testing.T) {
+	mock := New(t)
+
+	mock.Post("/users").Reply(http.StatusCreated).Created("/users/{id}").JSON(map[string]string{"name": "bob"})
+	mock.Post("/users").Reply(http.StatusCreated).Created("/users/{id}").Text("alice")
+	mock.Put("/groups/admins").Reply(http.StatusCreated).CreatedID("/groups/{id}", "admins")
+
+	client := mock.Client()
+
+	for _, step := range []struct {
+		method, target, location, body string
+	}{
+		{method: http.MethodPost, target: "/users", location: "/users/1"},
+		{method: http.MethodGet, target: "/users/1", body: `{"name":"bob"}`},
+		{method: http.MethodPost, target: "/users", location: "/users/2"},
+		{method: http.MethodGet, target: "/users/2", body: "alice"},
+		{method: http.MethodPut, target: "/groups/admins", location: "/groups/admins"},
+		{method: http.MethodGet, target: "/groups/admins"},
+	} {
+		req, err := http.NewRequest(step.method, "http://localhost"+step.target, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.Header.Get("Location") != step.location || string(body) != step.body {
+			t.Errorf("%s %s, wrong response, location %s, body %s", step.method, step.target, resp.Header.Get("Location"), body)
+		}
+	}
+}
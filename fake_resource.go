@@ -0,0 +1,276 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Resource is an in-memory REST collection served by FakeResource:
+//
+//	POST   /users       creates an object, assigns "id" if missing, 201 with Location
+//	GET    /users       lists objects in creation order
+//	GET    /users/{id}  returns the object or 404
+//	PUT    /users/{id}  replaces the object or 404
+//	DELETE /users/{id}  deletes the object, 204 or 404
+//
+// Bodies are JSON objects, other requests are reported to the TestReporter.
+type Resource struct {
+	*httptest.Server
+
+	t    TestReporter
+	path string
+
+	mu     sync.Mutex
+	nextID int
+	ids    []string
+	items  map[string]map[string]any
+}
+
+// FakeResource serves the collection at path, the server is closed at
+// Cleanup. Resource is an http.Handler as well, so it can be used with
+// NewHandlerTransport.
+func FakeResource(t TestReporter, path string) *Resource {
+	resource := &Resource{
+		t:     t,
+		path:  "/" + strings.Trim(path, "/"),
+		items: make(map[string]map[string]any),
+	}
+
+	resource.Server = httptest.NewServer(resource)
+
+	t.Cleanup(resource.Close)
+
+	return resource
+}
+
+// Put stores the object under the id, use it to seed the collection.
+func (s *Resource) Put(id string, object map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(id, object)
+}
+
+// Get returns the stored object by id.
+func (s *Resource) Get(id string) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object, ok := s.items[id]
+
+	return object, ok
+}
+
+// Len returns the count of stored objects.
+func (s *Resource) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.ids)
+}
+
+func (s *Resource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, isItem, ok := s.parsePath(r.URL.Path)
+	if !ok {
+		s.unexpected(w, r, http.StatusNotFound)
+
+		return
+	}
+
+	switch {
+	case !isItem && r.Method == http.MethodGet:
+		s.list(w)
+	case !isItem && r.Method == http.MethodPost:
+		s.create(w, r)
+	case isItem && r.Method == http.MethodGet:
+		s.get(w, id)
+	case isItem && r.Method == http.MethodPut:
+		s.replace(w, r, id)
+	case isItem && r.Method == http.MethodDelete:
+		s.delete(w, id)
+	default:
+		s.unexpected(w, r, http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Resource) parsePath(path string) (id string, isItem, ok bool) {
+	path = strings.TrimSuffix(path, "/")
+
+	if path == s.path {
+		return "", false, true
+	}
+
+	id, found := strings.CutPrefix(path, s.path+"/")
+	if !found || id == "" || strings.Contains(id, "/") {
+		return "", false, false
+	}
+
+	return id, true, true
+}
+
+func (s *Resource) list(w http.ResponseWriter) {
+	objects := make([]map[string]any, 0, len(s.ids))
+
+	for _, id := range s.ids {
+		objects = append(objects, s.items[id])
+	}
+
+	s.writeJSON(w, http.StatusOK, objects)
+}
+
+func (s *Resource) create(w http.ResponseWriter, r *http.Request) {
+	object, ok := s.readObject(w, r)
+	if !ok {
+		return
+	}
+
+	id, hasID := objectID(object)
+	if !hasID {
+		s.nextID++
+
+		for s.items[strconv.Itoa(s.nextID)] != nil {
+			s.nextID++
+		}
+
+		id = strconv.Itoa(s.nextID)
+		object["id"] = s.nextID
+	}
+
+	if _, exists := s.items[id]; exists {
+		s.writeJSON(w, http.StatusConflict, map[string]string{"error": "already exists"})
+
+		return
+	}
+
+	s.put(id, object)
+
+	w.Header().Set("Location", s.path+"/"+id)
+	s.writeJSON(w, http.StatusCreated, object)
+}
+
+func (s *Resource) get(w http.ResponseWriter, id string) {
+	object, ok := s.items[id]
+	if !ok {
+		s.notFound(w)
+
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, object)
+}
+
+func (s *Resource) replace(w http.ResponseWriter, r *http.Request, id string) {
+	if _, ok := s.items[id]; !ok {
+		s.notFound(w)
+
+		return
+	}
+
+	object, ok := s.readObject(w, r)
+	if !ok {
+		return
+	}
+
+	object["id"] = s.items[id]["id"]
+	s.items[id] = object
+
+	s.writeJSON(w, http.StatusOK, object)
+}
+
+func (s *Resource) delete(w http.ResponseWriter, id string) {
+	if _, ok := s.items[id]; !ok {
+		s.notFound(w)
+
+		return
+	}
+
+	delete(s.items, id)
+
+	for i := range s.ids {
+		if s.ids[i] == id {
+			s.ids = append(s.ids[:i], s.ids[i+1:]...)
+
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Resource) put(id string, object map[string]any) {
+	if _, exists := s.items[id]; !exists {
+		s.ids = append(s.ids, id)
+	}
+
+	s.items[id] = object
+}
+
+func (s *Resource) readObject(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("fake resource %s, read body from request, %s", s.path, err)
+		s.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+
+		return nil, false
+	}
+
+	var object map[string]any
+
+	err = json.Unmarshal(body, &object)
+	if err != nil || object == nil {
+		s.t.Errorf("fake resource %s, %s %s body is not a json object, body %s", s.path, r.Method, r.URL.Path, body)
+		s.writeJSON(w, http.StatusBadRequest, map[string]string{"error": "body is not a json object"})
+
+		return nil, false
+	}
+
+	return object, true
+}
+
+func (s *Resource) unexpected(w http.ResponseWriter, r *http.Request, statusCode int) {
+	s.t.Errorf("fake resource %s, unexpected request %s %s", s.path, r.Method, r.URL.Path)
+
+	s.writeJSON(w, statusCode, map[string]string{"error": http.StatusText(statusCode)})
+}
+
+func (s *Resource) notFound(w http.ResponseWriter) {
+	s.writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+}
+
+func (s *Resource) writeJSON(w http.ResponseWriter, statusCode int, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		s.t.Errorf("fake resource %s, marshal response, %s", s.path, err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	_, _ = w.Write(data)
+}
+
+func objectID(object map[string]any) (string, bool) {
+	switch id := object["id"].(type) {
+	case string:
+		return id, id != ""
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64), true
+	case nil:
+		return "", false
+	default:
+		return fmt.Sprint(id), true
+	}
+}
@@ -0,0 +1,121 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_FakeResource(t *testing.T) {
+	users := FakeResource(t, "/users")
+
+	type step struct {
+		method             string
+		path               string
+		body               string
+		expectedStatusCode int
+		expectedBody       string
+		expectedLocation   string
+	}
+
+	steps := []step{
+		{http.MethodGet, "/users", "", http.StatusOK, `[]`, ""},
+		{http.MethodPost, "/users", `{"name":"dima"}`, http.StatusCreated, `{"id":1,"name":"dima"}`, "/users/1"},
+		{http.MethodPost, "/users/", `{"name":"amidman"}`, http.StatusCreated, `{"id":2,"name":"amidman"}`, "/users/2"},
+		{http.MethodPost, "/users", `{"id":"admin","name":"root"}`, http.StatusCreated, `{"id":"admin","name":"root"}`, "/users/admin"},
+		{http.MethodPost, "/users", `{"id":"admin"}`, http.StatusConflict, `{"error":"already exists"}`, ""},
+		{http.MethodGet, "/users/1", "", http.StatusOK, `{"id":1,"name":"dima"}`, ""},
+		{http.MethodPut, "/users/1", `{"id":5,"name":"dmitry"}`, http.StatusOK, `{"id":1,"name":"dmitry"}`, ""},
+		{http.MethodDelete, "/users/2", "", http.StatusNoContent, ``, ""},
+		{http.MethodGet, "/users/2", "", http.StatusNotFound, `{"error":"not found"}`, ""},
+		{http.MethodPut, "/users/2", `{}`, http.StatusNotFound, `{"error":"not found"}`, ""},
+		{http.MethodDelete, "/users/2", "", http.StatusNotFound, `{"error":"not found"}`, ""},
+		{http.MethodGet, "/users", "", http.StatusOK, `[{"id":1,"name":"dmitry"},{"id":"admin","name":"root"}]`, ""},
+	}
+
+	for _, st := range steps {
+		req, err := http.NewRequest(st.method, users.URL+st.path, strings.NewReader(st.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != st.expectedStatusCode {
+			t.Errorf("%s %s, wrong status code, expected %d, actual %d", st.method, st.path, st.expectedStatusCode, resp.StatusCode)
+		}
+
+		if string(body) != st.expectedBody {
+			t.Errorf("%s %s, wrong body, expected %s, actual %s", st.method, st.path, st.expectedBody, body)
+		}
+
+		if location := resp.Header.Get("Location"); location != st.expectedLocation {
+			t.Errorf("%s %s, wrong Location, expected %s, actual %s", st.method, st.path, st.expectedLocation, location)
+		}
+	}
+
+	if users.Len() != 2 {
+		t.Errorf("wrong stored objects count, expected 2, actual %d", users.Len())
+	}
+}
+
+func Test_FakeResource_UnexpectedUsage(t *testing.T) {
+	tr := ExpectFailureTestReporter(
+		[]testReporterCall{
+			{
+				format: "fake resource %s, unexpected request %s %s",
+				args:   []any{"/users", http.MethodGet, "/orders"},
+			},
+			{
+				format: "fake resource %s, unexpected request %s %s",
+				args:   []any{"/users", http.MethodPatch, "/users/1"},
+			},
+			{
+				format: "fake resource %s, %s %s body is not a json object, body %s",
+				args:   []any{"/users", http.MethodPost, "/users", []byte("[1]")},
+			},
+		},
+		nil,
+	)(t)
+
+	users := FakeResource(tr, "users")
+	users.Put("1", map[string]any{"id": 1})
+
+	client := &http.Client{Transport: NewHandlerTransport(users)}
+
+	requests := []struct {
+		method             string
+		path               string
+		body               string
+		expectedStatusCode int
+	}{
+		{http.MethodGet, "/orders", "", http.StatusNotFound},
+		{http.MethodPatch, "/users/1", "{}", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/users", "[1]", http.StatusBadRequest},
+	}
+
+	for _, r := range requests {
+		req, err := http.NewRequest(r.method, "http://localhost"+r.path, strings.NewReader(r.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		if resp.StatusCode != r.expectedStatusCode {
+			t.Errorf("%s %s, wrong status code, expected %d, actual %d", r.method, r.path, r.expectedStatusCode, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package httpmock
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Fault alters a call, its input is kept by FailOnCalls.
+type Fault func(call Call) Call
+
+// FaultError fails the call with err, see Call.DoError.
+func FaultError(err error) Fault {
+	return func(call Call) Call {
+		call.DoError = err
+
+		return call
+	}
+}
+
+// FaultStatus answers the call with the status code and body instead of
+// its response.
+func FaultStatus(statusCode int, body Body) Fault {
+	return func(call Call) Call {
+		call.Response = Response{StatusCode: statusCode, Body: body}
+
+		return call
+	}
+}
+
+// FaultTimeout blocks the call until the request deadline, see
+// Call.Timeout.
+func FaultTimeout() Fault {
+	return func(call Call) Call {
+		call.Timeout = true
+
+		return call
+	}
+}
+
+type failOnCalls struct {
+	calls    Calls
+	ordinals []int
+	fault    Fault
+}
+
+// FailOnCalls injects the fault into the calls with the ordinals, starting
+// from 1, e.g. FailOnCalls(calls, []int{2, 5}, FaultStatus(503, nil)). The
+// input of faulted calls is kept and compared, calls failed with FaultError
+// or FaultTimeout compare it before they end. Done is left to the wrapped
+// calls.
+func FailOnCalls(calls Calls, ordinals []int, fault Fault) Calls {
+	return failOnCalls{
+		calls:    calls,
+		ordinals: ordinals,
+		fault:    fault,
+	}
+}
+
+func (f failOnCalls) Call(calledTimes int) (Call, bool) {
+	return f.callRequest(calledTimes, nil)
+}
+
+func (f failOnCalls) callRequest(calledTimes int, r *http.Request) (Call, bool) {
+	call, ok := callFor(f.calls, calledTimes, r)
+	if !ok || !slices.Contains(f.ordinals, calledTimes) {
+		return call, ok
+	}
+
+	input := call.Input
+
+	call = f.fault(call)
+	call.Input = input
+	call.faulted = true
+
+	return call, true
+}
+
+// compareFaultedInput compares the input of faulted calls which DoError or
+// Timeout end before the call is handled.
+func compareFaultedInput(t TestReporter, r *http.Request, call Call) {
+	if !call.faulted || (call.DoError == nil && !call.Timeout) {
+		return
+	}
+
+	CompareInput(withFailureKind(t, ErrInputMismatch), r, call.Input)
+}
+
+func (f failOnCalls) validate(marshalJSON bool) error {
+	return validateWrapped(f.calls, marshalJSON)
+}
+
+func (f failOnCalls) Done(calledTimes int) bool {
+	return f.calls.Done(calledTimes)
+}
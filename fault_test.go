@@ -0,0 +1,109 @@
+package httpmock
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func Test_FailOnCalls(t *testing.T) {
+	errInjected := errors.New("injected")
+
+	calls := SequenceCalls(
+		Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("1")}},
+		Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("2")}},
+		Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("3")}},
+		Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("4")}},
+	)
+
+	calls = FailOnCalls(calls, []int{2}, FaultStatus(http.StatusServiceUnavailable, RawBody("unavailable")))
+	calls = FailOnCalls(calls, []int{3}, FaultError(errInjected))
+
+	client := &http.Client{Transport: NewTransport(t, calls, nil)}
+
+	for _, expected := range []string{"1", "unavailable", "", "4"} {
+		resp, err := client.Get("http://localhost/items")
+
+		if expected == "" {
+			if !errors.Is(err, errInjected) {
+				t.Errorf("expect injected error, actual %v", err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(body) != expected {
+			t.Errorf("wrong body, expected %s, actual %s", expected, body)
+		}
+	}
+}
+
+func Test_FailOnCalls_KeepsInput(t *testing.T) {
+	calls := FailOnCalls(
+		SequenceCalls(Call{Input: Input{Method: http.MethodPost}}),
+		[]int{1},
+		func(call Call) Call {
+			return Call{}
+		},
+	)
+
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{{format: "1 call, wrong r.Method, expected %s, actual %s", args: []any{"POST", "GET"}}},
+			nil,
+		)(t),
+		calls,
+		nil,
+	)
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_FailOnCalls_ErrorComparesInput(t *testing.T) {
+	errInjected := errors.New("injected")
+
+	calls := FailOnCalls(
+		SequenceCalls(
+			Call{Input: Input{Method: http.MethodPost}},
+			Call{Input: Input{Method: http.MethodPost}},
+		),
+		[]int{1, 2},
+		FaultError(errInjected),
+	)
+
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "1 call, wrong r.Method, expected %s, actual %s", args: []any{"POST", "GET"}},
+			},
+			nil,
+		)(t),
+		calls,
+		nil,
+	)
+
+	client := &http.Client{Transport: transport}
+
+	_, err := client.Get("http://localhost/items")
+	if !errors.Is(err, errInjected) {
+		t.Errorf("expect injected error, actual %v", err)
+	}
+
+	_, err = client.Post("http://localhost/items", "", http.NoBody)
+	if !errors.Is(err, errInjected) {
+		t.Errorf("expect injected error, actual %v", err)
+	}
+}
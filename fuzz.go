@@ -0,0 +1,264 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// fuzzSource turns fuzz input into choices, an exhausted source chooses
+// zero, which keeps the template as is.
+type fuzzSource struct {
+	data []byte
+}
+
+func (s *fuzzSource) byte() byte {
+	if len(s.data) == 0 {
+		return 0
+	}
+
+	b := s.data[0]
+	s.data = s.data[1:]
+
+	return b
+}
+
+func (s *fuzzSource) intn(n int) int {
+	return int(s.byte()) % n
+}
+
+func (s *fuzzSource) string() string {
+	n := s.intn(16)
+
+	var b bytes.Buffer
+
+	for range n {
+		b.WriteByte(s.byte())
+	}
+
+	return b.String()
+}
+
+var fuzzStatusCodes = []int{
+	http.StatusOK, http.StatusCreated, http.StatusNoContent, http.StatusMovedPermanently,
+	http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusTooManyRequests,
+	http.StatusInternalServerError, http.StatusServiceUnavailable,
+}
+
+// FuzzResponse derives a valid response from the template and fuzz input,
+// for use in a func passed to testing.F.Fuzz: the status code, headers and
+// body vary, JSON bodies stay valid JSON with values replaced, dropped,
+// added or retyped. Empty input returns the template.
+func FuzzResponse(template Response, data []byte) (Response, error) {
+	src := &fuzzSource{data: data}
+
+	response := template
+	response.Header = template.Header.Clone()
+
+	if choice := src.intn(4); choice == 1 {
+		response.StatusCode = fuzzStatusCodes[src.intn(len(fuzzStatusCodes))]
+	}
+
+	for _, key := range sortedKeys(response.Header) {
+		switch src.intn(4) {
+		case 1:
+			response.Header.Del(key)
+		case 2:
+			response.Header.Set(key, src.string())
+		}
+	}
+
+	if template.Body == nil {
+		return response, nil
+	}
+
+	body, err := template.Body.Bytes()
+	if err != nil {
+		return Response{}, fmt.Errorf("fuzz response body, %w", err)
+	}
+
+	response.Body = RawBody(fuzzBody(src, body))
+
+	return response, nil
+}
+
+// FuzzRequest derives a valid request to baseURL from the template input
+// and fuzz input: query values and headers vary and JSON bodies are mutated
+// like FuzzResponse does.
+func FuzzRequest(template Input, baseURL string, data []byte) (*http.Request, error) {
+	src := &fuzzSource{data: data}
+
+	target, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse base url, %w", err)
+	}
+
+	method, _, _ := strings.Cut(template.Method, "|")
+	if method == "" || method == AnyMethod {
+		method = http.MethodGet
+	}
+
+	if template.URL != nil {
+		target = target.JoinPath(template.URL.Path)
+
+		query := template.URL.Query()
+
+		for _, key := range sortedKeys(query) {
+			if src.intn(3) == 1 {
+				query.Set(key, src.string())
+			}
+		}
+
+		target.RawQuery = query.Encode()
+	}
+
+	var body []byte
+
+	if template.Body != nil {
+		body, err = template.Body.Bytes()
+		if err != nil {
+			return nil, fmt.Errorf("fuzz request body, %w", err)
+		}
+
+		body = fuzzBody(src, body)
+	}
+
+	r, err := http.NewRequest(method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create fuzz request, %w", err)
+	}
+
+	for key, values := range template.Header {
+		r.Header[key] = append([]string(nil), values...)
+	}
+
+	for _, key := range sortedKeys(r.Header) {
+		if src.intn(4) == 1 {
+			r.Header.Set(key, src.string())
+		}
+	}
+
+	return r, nil
+}
+
+func fuzzBody(src *fuzzSource, body []byte) []byte {
+	var value any
+
+	if json.Unmarshal(body, &value) == nil {
+		mutated, err := json.Marshal(fuzzJSON(src, value))
+		if err == nil {
+			return mutated
+		}
+	}
+
+	body = bytes.Clone(body)
+
+	switch src.intn(4) {
+	case 1:
+		if len(body) > 0 {
+			body = body[:src.intn(len(body))]
+		}
+	case 2:
+		for i := range body {
+			body[i] ^= src.byte()
+		}
+	case 3:
+		body = append(body, src.string()...)
+	}
+
+	return body
+}
+
+func fuzzJSON(src *fuzzSource, value any) any {
+	switch src.intn(8) {
+	case 1:
+		return nil
+	case 2:
+		return src.string()
+	case 3:
+		return []any{math.MaxInt64, -1, 0.5, 1e308}[src.intn(4)]
+	case 4:
+		return src.intn(2) == 1
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		mutated := make(map[string]any, len(value))
+
+		for _, key := range sortedKeys(value) {
+			switch src.intn(6) {
+			case 1:
+				continue
+			case 2:
+				mutated["unknown_"+strconv.Itoa(src.intn(100))] = src.string()
+			}
+
+			mutated[key] = fuzzJSON(src, value[key])
+		}
+
+		return mutated
+	case []any:
+		mutated := make([]any, 0, len(value))
+
+		for _, item := range value {
+			if src.intn(6) == 1 {
+				continue
+			}
+
+			mutated = append(mutated, fuzzJSON(src, item))
+		}
+
+		return mutated
+	default:
+		return value
+	}
+}
+
+// FuzzClient serves the fuzzed response, see FuzzResponse, to do and fails
+// when do panics, e.g. a client parser indexing a missing field. Errors
+// returned by clients are expected and ignored.
+//
+//	f.Fuzz(func(t *testing.T, data []byte) {
+//		httpmock.FuzzClient(t, template, data, func(client *http.Client) {
+//			_, _ = api.New(client).GetUser(ctx, 1)
+//		})
+//	})
+func FuzzClient(t TestReporter, template Response, data []byte, do func(client *http.Client)) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	response, err := FuzzResponse(template, data)
+	if err != nil {
+		t.Fatalf("fuzz client, %s", err)
+
+		return
+	}
+
+	transport := NewTransport(t, StaticCalls(Call{Response: response}), func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		err := WriteResponse(w, call.Response)
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+	})
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			var body []byte
+
+			if response.Body != nil {
+				body, _ = response.Body.Bytes()
+			}
+
+			t.Fatalf("fuzz client, panic on response with status %d, body %s, %v\n%s", response.StatusCode, body, recovered, debug.Stack())
+		}
+	}()
+
+	do(&http.Client{Transport: transport})
+}
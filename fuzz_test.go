@@ -0,0 +1,94 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var fuzzTemplate = Response{
+	StatusCode: http.StatusOK,
+	Header:     http.Header{"Content-Type": {"application/json"}},
+	Body:       RawBody(`{"id":1,"name":"apple","tags":["red","sweet"]}`),
+}
+
+func Test_FuzzResponse_EmptyInput(t *testing.T) {
+	response, err := FuzzResponse(fuzzTemplate, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := response.Body.Bytes()
+
+	if response.StatusCode != http.StatusOK || !reflect.DeepEqual(response.Header, fuzzTemplate.Header) || string(body) != `{"id":1,"name":"apple","tags":["red","sweet"]}` {
+		t.Errorf("empty input changed the template, %+v %s", response, body)
+	}
+}
+
+func Test_FuzzRequest(t *testing.T) {
+	r, err := FuzzRequest(
+		Input{Method: "POST|PUT", URL: mustParseURL("/items?limit=10"), Body: RawBody(`{"id":1}`)},
+		"http://localhost",
+		[]byte{0, 1, 5, 'a', 0, 2, 3, 'x', 'y', 'z'},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	if r.Method != http.MethodPost || r.URL.Path != "/items" || !json.Valid(body) {
+		t.Errorf("invalid fuzz request, %s %s %s", r.Method, r.URL, body)
+	}
+}
+
+func Test_FuzzClient_Panic(t *testing.T) {
+	reporter := &testReporterMock{t: t}
+
+	FuzzClient(reporter, fuzzTemplate, nil, func(client *http.Client) {
+		var item struct {
+			Tags []string `json:"tags"`
+		}
+
+		resp, err := client.Get("http://localhost/items/1")
+		if err != nil {
+			return
+		}
+
+		defer resp.Body.Close()
+
+		_ = json.NewDecoder(resp.Body).Decode(&item)
+
+		_ = item.Tags[5]
+	})
+
+	if len(reporter.fatalfCalls) != 1 || !strings.HasPrefix(reporter.fatalfCalls[0].format, "fuzz client, panic on response") {
+		t.Errorf("expect panic reported, actual %+v", reporter.fatalfCalls)
+	}
+}
+
+func Fuzz_FuzzClient(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{1, 3, 0, 2, 7, 1, 5, 2, 4})
+	f.Add([]byte("\x02\x02\x01\x06\x01\x03\x02\x05abc"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		FuzzClient(t, fuzzTemplate, data, func(client *http.Client) {
+			resp, err := client.Get("http://localhost/items/1")
+			if err != nil {
+				return
+			}
+
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.Header.Get("Content-Type") == "application/json" && !json.Valid(body) && len(body) > 0 {
+				t.Errorf("json body became invalid, %s", body)
+			}
+		})
+	})
+}
@@ -0,0 +1,288 @@
+package httpmock
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Generator produces values for template helpers and records them, so a
+// later call can assert that the client echoed them back:
+//
+//	{{uuid}}                       random UUID v4
+//	{{now}}                        current time in RFC3339
+//	{{now RFC1123}}                named time layout, unix, unixmilli or a go layout in quotes
+//	{{now HTTP offset=1h}}         time shifted by a go duration, e.g. for Expires
+//	{{seq}}                        sequence number starting from 1
+//	{{uuid as=order}}              records the value under the order name
+//	{{generated.order}}            last value recorded under the name
+//	{{generated.order.[0]}}        value by index
+//
+// Values without as= are recorded under the helper name, seq counters are
+// kept per name.
+type Generator struct {
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	values   map[string][]string
+	counters map[string]int
+}
+
+// DefaultGenerator renders TemplateBody and Response.TemplateHeader values
+// outside of transports and servers, e.g. with Body.Bytes. Transports and
+// servers render them with a generator of their own, see WithGenerator, so
+// values and sequences do not leak between tests.
+var DefaultGenerator = NewGenerator()
+
+func NewGenerator() *Generator {
+	return &Generator{
+		Now:      time.Now,
+		values:   make(map[string][]string),
+		counters: make(map[string]int),
+	}
+}
+
+type generatorContextKey struct{}
+
+// WithGenerator makes the transport render templates with g instead of a
+// generator of its own, so the test can read the values it generated.
+// Generator.Body bodies keep their generator.
+func WithGenerator(g *Generator) Option {
+	return func(o *options) {
+		o.generator = g
+	}
+}
+
+func attachGenerator(r *http.Request, g *Generator) *http.Request {
+	if g == nil {
+		return r
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), generatorContextKey{}, g))
+}
+
+func generatorFrom(ctx context.Context) *Generator {
+	g, _ := ctx.Value(generatorContextKey{}).(*Generator)
+
+	return g
+}
+
+// Body returns a TemplateBody rendered with the generator.
+func (g *Generator) Body(template string) Body {
+	return templateBody{template: template, generator: g}
+}
+
+// Values returns all values recorded under the name.
+func (g *Generator) Values(name string) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return append([]string(nil), g.values[name]...)
+}
+
+// Last returns the last value recorded under the name.
+func (g *Generator) Last(name string) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	values := g.values[name]
+	if len(values) == 0 {
+		return "", false
+	}
+
+	return values[len(values)-1], true
+}
+
+func (g *Generator) record(name, value string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.values[name] = append(g.values[name], value)
+}
+
+func (g *Generator) next(name string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.counters[name]++
+
+	return g.counters[name]
+}
+
+func (g *Generator) now() time.Time {
+	if g.Now == nil {
+		return time.Now()
+	}
+
+	return g.Now()
+}
+
+var timeLayouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"HTTP":        http.TimeFormat,
+	"DateOnly":    time.DateOnly,
+	"DateTime":    time.DateTime,
+}
+
+func (g *Generator) eval(helper string, args []string, now func() time.Time) (string, bool) {
+	positional, named := splitHelperArgs(args)
+
+	name := helper
+	if as, ok := named["as"]; ok {
+		name = as
+	}
+
+	var value string
+
+	switch helper {
+	case "uuid":
+		value = newUUID()
+	case "now":
+		layout := "RFC3339"
+		if len(positional) > 0 {
+			layout = positional[0]
+		}
+
+		tm := now()
+
+		if offset, ok := named["offset"]; ok {
+			d, err := time.ParseDuration(offset)
+			if err != nil {
+				return "", false
+			}
+
+			tm = tm.Add(d)
+		}
+
+		value = formatTime(tm, layout)
+	case "seq":
+		value = strconv.Itoa(g.next(name))
+	default:
+		return "", false
+	}
+
+	g.record(name, value)
+
+	return value, true
+}
+
+func (g *Generator) evalGenerated(expression string) (string, error) {
+	parts := strings.Split(expression, ".")[1:]
+	if len(parts) == 0 {
+		return "", fmt.Errorf("generated value name not specified")
+	}
+
+	values := g.Values(parts[0])
+	if len(values) == 0 {
+		return "", fmt.Errorf("no values generated for %s", parts[0])
+	}
+
+	if len(parts) == 1 {
+		return values[len(values)-1], nil
+	}
+
+	return templateIndex(values, parts[1:])
+}
+
+func formatTime(t time.Time, layout string) string {
+	switch layout {
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "unixmilli":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+
+	if named, ok := timeLayouts[layout]; ok {
+		layout = named
+	}
+
+	if layout == http.TimeFormat {
+		t = t.UTC()
+	}
+
+	return t.Format(layout)
+}
+
+func splitHelperArgs(args []string) (positional []string, named map[string]string) {
+	named = make(map[string]string)
+
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if ok && !strings.ContainsAny(key, `"' `) {
+			named[key] = unquoteHelperArg(value)
+
+			continue
+		}
+
+		positional = append(positional, unquoteHelperArg(arg))
+	}
+
+	return positional, named
+}
+
+func unquoteHelperArg(arg string) string {
+	if len(arg) >= 2 && (arg[0] == '"' || arg[0] == '\'') && arg[len(arg)-1] == arg[0] {
+		return arg[1 : len(arg)-1]
+	}
+
+	return arg
+}
+
+// splitTemplateFields splits the expression by spaces keeping quoted parts.
+func splitTemplateFields(expression string) []string {
+	var (
+		fields  []string
+		current strings.Builder
+		quote   byte
+	)
+
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+
+		switch {
+		case quote != 0:
+			current.WriteByte(c)
+
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+
+			current.WriteByte(c)
+		case c == ' ' || c == '\t':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+
+	return fields
+}
+
+func newUUID() string {
+	var uuid [16]byte
+
+	_, _ = rand.Read(uuid[:])
+
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+}
@@ -0,0 +1,221 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func fixedGenerator() *Generator {
+	g := NewGenerator()
+	g.Now = func() time.Time {
+		return time.Date(2024, time.March, 5, 10, 30, 0, 0, time.FixedZone("MSK", 3*60*60))
+	}
+
+	return g
+}
+
+func Test_Generator_Helpers(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Template string
+		Expected string
+	}{
+		{
+			Name:     "now default layout",
+			Template: "{{now}}",
+			Expected: "2024-03-05T10:30:00+03:00",
+		},
+		{
+			Name:     "now named and quoted layouts",
+			Template: `{{now HTTP}}|{{now unix}}|{{now "2006/01/02 15:04"}}`,
+			Expected: "Tue, 05 Mar 2024 07:30:00 GMT|1709623800|2024/03/05 10:30",
+		},
+		{
+			Name:     "sequences per name",
+			Template: "{{seq}},{{seq}},{{seq as=orders}},{{seq}}",
+			Expected: "1,2,1,3",
+		},
+		{
+			Name:     "generated references",
+			Template: "{{seq as=id}}-{{seq as=id}}:{{generated.id}},{{generated.id.[0]}}",
+			Expected: "1-2:2,1",
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.Name, func(t *testing.T) {
+			bytes, err := fixedGenerator().Body(tst.Template).Bytes()
+			if err != nil {
+				t.Fatalf("render template, unexpected error: %s", err)
+			}
+
+			if string(bytes) != tst.Expected {
+				t.Errorf("wrong rendered template, expected %s, actual %s", tst.Expected, bytes)
+			}
+		})
+	}
+}
+
+func Test_Generator_UUID(t *testing.T) {
+	g := NewGenerator()
+
+	bytes, err := g.Body("{{uuid}} {{uuid as=order}}").Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := strings.Fields(string(bytes))
+
+	for _, id := range ids {
+		if !uuidPattern.MatchString(id) {
+			t.Errorf("wrong uuid format %s", id)
+		}
+	}
+
+	if ids[0] == ids[1] {
+		t.Errorf("expect different uuids, actual %s twice", ids[0])
+	}
+
+	if values := g.Values("uuid"); !slices.Equal(values, ids[:1]) {
+		t.Errorf("wrong recorded uuid values, expected %v, actual %v", ids[:1], values)
+	}
+
+	if last, ok := g.Last("order"); !ok || last != ids[1] {
+		t.Errorf("wrong last order value, expected %s, actual %s", ids[1], last)
+	}
+
+	_, err = g.Body("{{generated.missing}}").Bytes()
+	if err == nil || err.Error() != "render template expression {{generated.missing}}, no values generated for missing" {
+		t.Errorf("wrong error for missing generated value, actual %v", err)
+	}
+}
+
+func Test_Generator_ClientEchoesGeneratedValue(t *testing.T) {
+	g := NewGenerator()
+
+	calls := SequenceCalls(
+		Call{
+			Input: Input{
+				Method: http.MethodPost,
+			},
+			Response: Resp(http.StatusCreated).
+				Body(g.Body(`{"id":"{{uuid as=order}}"}`)).
+				Header("X-Created-At", "{{now unix}}").
+				Response(),
+		},
+		Call{
+			Input: Input{
+				Method: http.MethodPut,
+				Body:   g.Body(`{"order":"{{generated.order}}"}`),
+			},
+		},
+	)
+
+	client := &http.Client{Transport: NewTransport(t, calls, nil)}
+
+	resp, err := client.Post("http://localhost/orders", "", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	id := strings.TrimSuffix(strings.TrimPrefix(string(body), `{"id":"`), `"}`)
+
+	if !uuidPattern.MatchString(id) {
+		t.Fatalf("wrong generated id in body %s", body)
+	}
+
+	if createdAt := resp.Header.Get("X-Created-At"); createdAt == "" {
+		t.Errorf("X-Created-At header not rendered")
+	}
+
+	req, err := http.NewRequest(http.MethodPut, "http://localhost/orders", strings.NewReader(`{"order":"`+id+`"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_Generator_PerTransport(t *testing.T) {
+	call := Call{
+		Input:    Input{Method: http.MethodGet},
+		Response: Response{Body: TemplateBody("{{seq}}")},
+	}
+
+	g := NewGenerator()
+
+	transports := []http.RoundTripper{
+		NewTransport(t, StaticCalls(call), nil, WithGenerator(g)),
+		NewTransport(t, StaticCalls(call), nil),
+	}
+
+	for _, transport := range transports {
+		for _, expected := range []string{"1", "2"} {
+			resp, err := (&http.Client{Transport: transport}).Get("http://localhost/")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if string(body) != expected {
+				t.Errorf("wrong sequence value, expected %s, actual %s", expected, body)
+			}
+		}
+	}
+
+	if values := g.Values("seq"); !slices.Equal(values, []string{"1", "2"}) {
+		t.Errorf("wrong recorded seq values, expected [1 2], actual %v", values)
+	}
+
+	if _, ok := DefaultGenerator.Last("seq"); ok {
+		t.Errorf("transport recorded values to DefaultGenerator")
+	}
+}
+
+func Test_Generator_HeaderSeesBodyValues(t *testing.T) {
+	g := NewGenerator()
+
+	transport := NewTransport(t,
+		SequenceCalls(
+			Call{
+				Input: Input{Method: http.MethodPost},
+				Response: Response{
+					StatusCode:     http.StatusCreated,
+					Body:           g.Body(`{"id":"{{uuid as=order}}"}`),
+					TemplateHeader: http.Header{"Location": {"/orders/{{generated.order}}"}},
+				},
+			},
+		),
+		nil,
+	)
+
+	resp, err := (&http.Client{Transport: transport}).Post("http://localhost/orders", "", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	id, _ := g.Last("order")
+
+	if location := resp.Header.Get("Location"); location != "/orders/"+id {
+		t.Errorf("wrong Location header, expected /orders/%s, actual %s", id, location)
+	}
+}
@@ -0,0 +1,34 @@
+package httpmock
+
+import (
+	"time"
+)
+
+// gracePollInterval is how often the count of calls is checked during the
+// grace period.
+const gracePollInterval = 5 * time.Millisecond
+
+// WithGracePeriod waits at Cleanup up to d for the expected calls which have
+// not arrived yet before reporting "not all calls were handled", for clients
+// which flush asynchronously on shutdown. Servers are closed after the wait.
+func WithGracePeriod(d time.Duration) Option {
+	return func(o *options) {
+		o.gracePeriod = d
+	}
+}
+
+// awaitCalls waits until the calls are done or the grace period passes, it
+// waits once, later calls return at once.
+func (h *transport) awaitCalls() {
+	if h.options.gracePeriod <= 0 {
+		return
+	}
+
+	h.grace.Do(func() {
+		deadline := time.Now().Add(h.options.gracePeriod)
+
+		for !h.calls.Done(int(h.called())) && time.Now().Before(deadline) {
+			time.Sleep(gracePollInterval)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_WithGracePeriod_Transport(t *testing.T) {
+	client := &http.Client{
+		Transport: NewTransport(t,
+			SequenceCalls(Call{Input: Input{Method: http.MethodPost}}),
+			nil,
+			WithGracePeriod(time.Second),
+		),
+	}
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+
+		resp, err := client.Post("http://localhost/flush", "text/plain", http.NoBody)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+}
+
+func Test_WithGracePeriod_Server(t *testing.T) {
+	server := NewServer(t,
+		SequenceCalls(Call{Input: Input{Method: http.MethodPost}}),
+		nil,
+		WithGracePeriod(time.Second),
+	)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+
+		resp, err := http.Post(server.URL+"/flush", "text/plain", http.NoBody)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+}
+
+func Test_WithGracePeriod_Expires(t *testing.T) {
+	start := time.Now()
+
+	t.Run("missing call", func(t *testing.T) {
+		NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{{format: "assert handler calls, not all calls were handled"}},
+				nil,
+			)(t),
+			SequenceCalls(Call{}),
+			nil,
+			WithGracePeriod(40*time.Millisecond),
+		)
+	})
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("grace period not waited for, elapsed %s", elapsed)
+	}
+}
@@ -0,0 +1,34 @@
+package httpmock
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// HandleCallContext is HandleCall receiving the request context explicitly,
+// long running handlers must return once ctx is done, so mocks don't outlive
+// the test when clients give up early.
+type HandleCallContext func(ctx context.Context, t TestReporter, w http.ResponseWriter, r *http.Request, call Call)
+
+// HandleCallWithContext adapts handle to HandleCall, ctx is the request
+// context.
+func HandleCallWithContext(handle HandleCallContext) HandleCall {
+	return func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		handle(r.Context(), t, w, r, call)
+	}
+}
+
+// sleepContext waits for d or until ctx is done, it returns false in the
+// latter case.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
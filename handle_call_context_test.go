@@ -0,0 +1,71 @@
+package httpmock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_Delay_HonorsCancellation(t *testing.T) {
+	call := Call{Input: Input{Method: http.MethodGet}, Delay: 5 * time.Second}
+
+	server := NewStaticServer(t, call)
+
+	transports := map[string]http.RoundTripper{
+		"transport": NewTransport(t, StaticCalls(call), nil),
+		"server":    server.Client().Transport,
+	}
+
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, http.NoBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+
+			_, err = (&http.Client{Transport: transport}).Do(req)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("wrong error, expected %s, actual %v", context.DeadlineExceeded, err)
+			}
+
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("delay not canceled, elapsed %s", elapsed)
+			}
+		})
+	}
+}
+
+type contextKey struct{}
+
+func Test_HandleCallWithContext(t *testing.T) {
+	handleCall := HandleCallWithContext(func(ctx context.Context, _ TestReporter, w http.ResponseWriter, _ *http.Request, _ Call) {
+		value, _ := ctx.Value(contextKey{}).(string)
+
+		w.Header().Set("X-Value", value)
+	})
+
+	transport := NewTransport(t, SequenceCalls(Call{}), handleCall)
+
+	req, err := http.NewRequestWithContext(context.WithValue(context.Background(), contextKey{}, "passed"), http.MethodGet, "http://localhost", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if value := resp.Header.Get("X-Value"); value != "passed" {
+		t.Errorf("wrong context value, expected passed, actual %s", value)
+	}
+}
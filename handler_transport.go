@@ -0,0 +1,56 @@
+package httpmock
+
+import (
+	"net/http"
+)
+
+// HandlerExpectations verify the calls to a handler wrapped by
+// NewVerifiedHandlerTransport.
+type HandlerExpectations struct {
+	// MinCalls is the least count of calls expected by Cleanup.
+	MinCalls int
+	// MaxCalls limits the count of calls, calls beyond it are reported and
+	// not served, 0 means no limit.
+	MaxCalls int
+	// ForbiddenPaths are url paths, or globs like Input.URL paths, the
+	// handler must not be called with. Such calls are reported and answered
+	// with 403 Forbidden without calling the handler.
+	ForbiddenPaths []string
+}
+
+// handlerCalls allows calls up to MaxCalls and is done after MinCalls.
+type handlerCalls struct {
+	expect HandlerExpectations
+}
+
+func (c handlerCalls) Call(calledTimes int) (Call, bool) {
+	if c.expect.MaxCalls > 0 && calledTimes > c.expect.MaxCalls {
+		return Call{}, false
+	}
+
+	return Call{}, true
+}
+
+func (c handlerCalls) Done(calledTimes int) bool {
+	return calledTimes >= c.expect.MinCalls
+}
+
+// NewVerifiedHandlerTransport serves requests with the handler like
+// NewHandlerTransport and verifies them: the expectations, the options,
+// e.g. WithRequestVerifier or WithTranscript, and at Cleanup the count of
+// calls and unclosed response bodies. Failures are reported to t.
+func NewVerifiedHandlerTransport(t TestReporter, h http.Handler, expect HandlerExpectations, opts ...Option) http.RoundTripper {
+	return NewTransport(t, handlerCalls{expect: expect}, func(t TestReporter, w http.ResponseWriter, r *http.Request, _ Call) {
+		for _, path := range expect.ForbiddenPaths {
+			if _, ok := matchPathGlob(path, r.URL.Path); ok {
+				t.Errorf("forbidden path %s, matches %s", r.URL.Path, path)
+
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	}, opts...)
+}
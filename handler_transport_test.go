@@ -0,0 +1,61 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func Test_NewVerifiedHandlerTransport(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.URL.Path)
+	})
+
+	client := &http.Client{
+		Transport: NewVerifiedHandlerTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{{format: "2 call, forbidden path %s, matches %s", args: []any{"/admin/users", "/admin/**"}}},
+				[]testReporterCall{{format: "no expected calls left"}},
+			)(t),
+			handler,
+			HandlerExpectations{MaxCalls: 2, ForbiddenPaths: []string{"/admin/**"}},
+		),
+	}
+
+	for _, tt := range []struct {
+		path       string
+		statusCode int
+		body       string
+	}{
+		{path: "/items", statusCode: http.StatusOK, body: "/items"},
+		{path: "/admin/users", statusCode: http.StatusForbidden},
+		{path: "/items", statusCode: StatusNoCallsLeft},
+	} {
+		resp, err := client.Get("http://localhost" + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.statusCode {
+			t.Errorf("%s, wrong status code, expected %d, actual %d", tt.path, tt.statusCode, resp.StatusCode)
+		}
+
+		if tt.body != "" && string(body) != tt.body {
+			t.Errorf("%s, wrong body, expected %s, actual %s", tt.path, tt.body, body)
+		}
+	}
+}
+
+func Test_NewVerifiedHandlerTransport_MinCalls(t *testing.T) {
+	NewVerifiedHandlerTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{{format: "assert handler calls, not all calls were handled"}},
+			nil,
+		)(t),
+		http.NotFoundHandler(),
+		HandlerExpectations{MinCalls: 1},
+	)
+}
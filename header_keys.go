@@ -0,0 +1,32 @@
+package httpmock
+
+import (
+	"net/http"
+	"slices"
+)
+
+// WithHeaderKeysCheck makes NewTransport report request header keys which
+// are not canonical, e.g. x-api-key set by direct map access instead of
+// Header.Set. Header.Get misses such keys, so they break matching and real
+// servers alike. Servers parse keys canonical, so only NewTransport sees them.
+func WithHeaderKeysCheck() Option {
+	return func(o *options) {
+		o.headerKeysCheck = true
+	}
+}
+
+func checkHeaderKeys(t TestReporter, header http.Header) {
+	keys := make([]string, 0, len(header))
+
+	for key := range header {
+		if canonical := http.CanonicalHeaderKey(key); canonical != key {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		t.Errorf("non-canonical header key %s, expected %s", key, http.CanonicalHeaderKey(key))
+	}
+}
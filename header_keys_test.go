@@ -0,0 +1,33 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_WithHeaderKeysCheck(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "1 call, non-canonical header key %s, expected %s", args: []any{"x-api-key", "X-Api-Key"}},
+				{format: "1 call, non-canonical header key %s, expected %s", args: []any{"x-request-id", "X-Request-Id"}},
+			},
+			nil,
+		)(t),
+		SequenceCalls(Call{Input: Input{Method: http.MethodGet}}),
+		nil,
+		WithHeaderKeysCheck(),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/items", http.NoBody)
+	req.Header.Set("Accept", "application/json")
+	req.Header["x-request-id"] = []string{"1"}
+	req.Header["x-api-key"] = []string{"secret"}
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
@@ -0,0 +1,96 @@
+package httpmock
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// WithHistory records every exchange served by the transport or server
+// into the sink: the request with the body part the handler read and the
+// written response. Sink errors are reported.
+func WithHistory(sink RecordSink) Option {
+	return func(o *options) {
+		o.history = sink
+	}
+}
+
+// historyWriter captures the written response.
+type historyWriter struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *historyWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+		w.header = w.ResponseWriter.Header().Clone()
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *historyWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	w.body.Write(p)
+
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *historyWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *historyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// recordHistory captures the exchange, the returned func records it into
+// the sink once the call is served.
+func recordHistory(t TestReporter, sink RecordSink, w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request, func()) {
+	var requestBody bytes.Buffer
+
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = readCloser{
+			Reader: io.TeeReader(r.Body, &requestBody),
+			Closer: r.Body,
+		}
+	}
+
+	hw := &historyWriter{ResponseWriter: w}
+
+	request := RecordedRequest{
+		Method: r.Method,
+		URL:    r.URL.String(),
+		Header: cloneNonEmptyHeader(r.Header),
+	}
+
+	return hw, r, func() {
+		status := hw.status
+		if status == 0 {
+			status = http.StatusOK
+			hw.header = hw.ResponseWriter.Header().Clone()
+		}
+
+		request.Body = requestBody.Bytes()
+
+		err := sink.Record(Exchange{
+			Request: request,
+			Response: RecordedResponse{
+				StatusCode: status,
+				Header:     cloneNonEmptyHeader(hw.header),
+				Body:       hw.body.Bytes(),
+			},
+		})
+		if err != nil {
+			t.Errorf("record exchange, %s", err)
+		}
+	}
+}
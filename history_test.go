@@ -0,0 +1,53 @@
+package httpmock
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_WithHistory(t *testing.T) {
+	history := &Cassette{}
+
+	server := NewServer(t,
+		SequenceCalls(Call{
+			Input: Input{Method: http.MethodPost, Body: RawBody(`{"name":"dima"}`)},
+			Response: Response{
+				StatusCode: http.StatusCreated,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       RawBody(`{"id":1}`),
+			},
+		}),
+		nil,
+		WithHistory(history),
+	)
+
+	resp, err := server.Client().Post(server.URL+"/users", "application/json", strings.NewReader(`{"name":"dima"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	exchanges := history.Exchanges()
+	if len(exchanges) != 1 {
+		t.Fatalf("wrong history length, expected 1, actual %d", len(exchanges))
+	}
+
+	exchange := exchanges[0]
+
+	if exchange.Request.Method != http.MethodPost || exchange.Request.URL != "/users" || string(exchange.Request.Body) != `{"name":"dima"}` {
+		t.Errorf("wrong recorded request, actual %+v", exchange.Request)
+	}
+
+	expectedResponse := RecordedResponse{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       RecordedBody(`{"id":1}`),
+	}
+
+	if !reflect.DeepEqual(exchange.Response, expectedResponse) {
+		t.Errorf("wrong recorded response, expected %+v, actual %+v", expectedResponse, exchange.Response)
+	}
+}
@@ -0,0 +1,81 @@
+package httpmock
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// MapHosts returns a DialContext for http.Transport which dials the mapped
+// address instead of the host, so code with hardcoded hosts reaches the mock
+// without changing its configuration. Keys are hosts, matching any port, or
+// host:port, values are addresses, e.g.
+// MapHosts(map[string]string{"api.example.com": server.Listener.Addr().String()}).
+// Other hosts are dialed as usual. The request host is kept, so Host headers
+// and TLS SNI still name the production host.
+func MapHosts(hosts map[string]string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{}
+
+	return mapHosts(dialer.DialContext, func(addr string) (string, string, bool) {
+		if target, ok := hosts[addr]; ok {
+			return "tcp", target, true
+		}
+
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return "", "", false
+		}
+
+		target, ok := hosts[host]
+
+		return "tcp", target, ok
+	})
+}
+
+func mapHosts(dial dialFunc, lookup func(addr string) (network, target string, ok bool)) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if mappedNetwork, target, ok := lookup(addr); ok {
+			return dial(ctx, mappedNetwork, target)
+		}
+
+		return dial(ctx, network, addr)
+	}
+}
+
+// HostClient returns a client like Server.Client dialing the server for the
+// hosts on any port, e.g. server.HostClient("api.example.com"). Requests
+// keep the host in the url and the Host header.
+func (s *Server) HostClient(hosts ...string) *http.Client {
+	client := *s.Client()
+
+	transport := client.Transport.(*http.Transport).Clone()
+
+	dial := transport.DialContext
+	if dial == nil {
+		dialer := &net.Dialer{}
+		dial = dialer.DialContext
+	}
+
+	addr := s.Listener.Addr()
+
+	transport.DialContext = mapHosts(dial, func(target string) (string, string, bool) {
+		host, _, err := net.SplitHostPort(target)
+		if err != nil {
+			host = target
+		}
+
+		for _, mapped := range hosts {
+			if host == mapped {
+				return addr.Network(), addr.String(), true
+			}
+		}
+
+		return "", "", false
+	})
+
+	client.Transport = transport
+
+	return &client
+}
@@ -0,0 +1,66 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func Test_MapHosts(t *testing.T) {
+	server := NewServer(t,
+		SequenceCalls(Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{Body: RawBody("mapped")},
+		}),
+		func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+			if r.Host != "api.example.com" {
+				t.Errorf("wrong host, expected api.example.com, actual %s", r.Host)
+			}
+
+			HandleCallCompareInput(t, w, r, call)
+		},
+	)
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: MapHosts(map[string]string{"api.example.com": server.Listener.Addr().String()}),
+		},
+	}
+
+	resp, err := client.Get("http://api.example.com/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "mapped" {
+		t.Errorf("wrong body, expected mapped, actual %s", body)
+	}
+}
+
+func Test_Server_HostClient(t *testing.T) {
+	server := NewServer(t,
+		SequenceCalls(Call{Input: Input{Method: http.MethodGet}}),
+		func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+			if r.Host != "api.example.com:8443" {
+				t.Errorf("wrong host, expected api.example.com:8443, actual %s", r.Host)
+			}
+
+			HandleCallCompareInput(t, w, r, call)
+		},
+	)
+
+	resp, err := server.HostClient("api.example.com").Get("http://api.example.com:8443/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	_, err = server.HostClient("api.example.com").Get("http://other.invalid/items")
+	if err == nil {
+		t.Error("expect error for unmapped host")
+	}
+}
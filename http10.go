@@ -0,0 +1,56 @@
+package httpmock
+
+import (
+	"net/http"
+)
+
+// WithHTTP10 makes NewServer answer like an HTTP/1.0 upstream: the status
+// line is HTTP/1.0, there is no keep-alive and no chunked encoding, the
+// body is delimited by the connection close unless the response sets
+// Content-Length, and the connection is closed after every response.
+// Responses are buffered, so streaming bodies arrive at once.
+func WithHTTP10() Option {
+	return func(o *options) {
+		o.http10 = true
+	}
+}
+
+func http10Handler(t TestReporter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buffered := newResponseWriter()
+
+		next.ServeHTTP(buffered, r)
+
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Errorf("http/1.0, response writer does not support hijacking")
+
+			return
+		}
+
+		conn, _, err := hijacker.Hijack()
+		if err != nil {
+			t.Errorf("http/1.0, hijack connection, %s", err)
+
+			return
+		}
+
+		defer conn.Close()
+
+		resp := buffered.result(r)
+		resp.Proto = "HTTP/1.0"
+		resp.ProtoMajor, resp.ProtoMinor = 1, 0
+		resp.TransferEncoding = nil
+		resp.Close = true
+
+		resp.Header = resp.Header.Clone()
+		resp.Header.Del("Connection")
+		resp.Header.Del("Keep-Alive")
+
+		if resp.Header.Get("Content-Length") == "" {
+			resp.ContentLength = -1
+		}
+
+		_ = resp.Write(conn)
+	})
+}
@@ -0,0 +1,42 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func Test_WithHTTP10(t *testing.T) {
+	server := NewServer(t,
+		StaticCalls(Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{Body: RawBody("hello"), TransferEncoding: TransferChunked},
+		}),
+		nil,
+		WithHTTP10(),
+	)
+
+	client := &http.Client{Transport: &http.Transport{}}
+
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if err != nil || string(body) != "hello" {
+			t.Errorf("wrong body, expected hello, actual %s, %v", body, err)
+		}
+
+		if resp.Proto != "HTTP/1.0" || !resp.Close || resp.ContentLength != -1 || len(resp.TransferEncoding) != 0 {
+			t.Errorf("not an http/1.0 response, proto %s, close %t, content length %d, transfer encoding %v",
+				resp.Proto, resp.Close, resp.ContentLength, resp.TransferEncoding,
+			)
+		}
+	}
+
+	server.AssertConnsOpened(t, 2)
+}
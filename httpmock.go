@@ -2,14 +2,15 @@ package httpmock
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"net/http/httptest"
 	"net/url"
 	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -18,40 +19,144 @@ type Body interface {
 	Bytes() ([]byte, error)
 }
 
+// BodyComparer is implemented by input bodies that match the request body
+// by their own rules instead of comparing bytes.
+// body is valid until CompareBody returns.
+type BodyComparer interface {
+	CompareBody(t TestReporter, body []byte)
+}
+
+// RequestBody is implemented by response bodies rendered for the incoming
+// request, see RenderResponse.
+type RequestBody interface {
+	Body
+	RequestBytes(r *http.Request) ([]byte, error)
+}
+
 type RawBody []byte
 
 func (r RawBody) Bytes() ([]byte, error) {
 	return r, nil
 }
 
-type jsonBody struct{ value any }
+type jsonBody struct {
+	value any
+	once  sync.Once
+	bytes []byte
+	err   error
+}
 
-func (j jsonBody) Bytes() ([]byte, error) {
-	return json.Marshal(j.value)
+func (j *jsonBody) Bytes() ([]byte, error) {
+	j.once.Do(func() {
+		j.bytes, j.err = json.Marshal(j.value)
+	})
+
+	return j.bytes, j.err
 }
 
+// JSONBody marshals value on the first use and reuses the result for every
+// call, use DynamicBody for values changed between calls.
 func JSONBody(value any) Body {
-	return jsonBody{value: value}
+	return &jsonBody{value: value}
+}
+
+// DynamicBody is regenerated for every call.
+type DynamicBody func() ([]byte, error)
+
+func (d DynamicBody) Bytes() ([]byte, error) {
+	return d()
 }
 
 type Call struct {
 	Input    Input
 	Response Response
 	DoError  error
-	Delay    time.Duration
+	// Delay is waited for by HandleCallCompareInput after the response is
+	// written, WithDelayPlacement moves it before the comparison or the
+	// response.
+	Delay time.Duration
+	// FirstByte is waited for after the call is handled, before the
+	// response header is sent, clients see it as time to first byte, e.g.
+	// http.Transport.ResponseHeaderTimeout.
+	FirstByte time.Duration
+	// BodyTransfer spreads sending the response body over the duration
+	// after the header, so client body read timeouts can be targeted
+	// separately. Servers buffer the body written by the handler.
+	BodyTransfer time.Duration
+	// Timeout blocks the call until the request context is done and returns
+	// its error, so http.Client reports the *url.Error it produces on real
+	// timeouts. The request must have a deadline.
+	Timeout bool
+	// Webhooks are sent after the response is written, see Webhook.
+	Webhooks []Webhook
+	// ReadWithin is the longest time the client may take to read the
+	// response: from the response being returned until its body is closed
+	// on transports, from the first write until the response is written and
+	// flushed on servers. Stalling clients are reported.
+	ReadWithin time.Duration
+	// Labels identify the call in failures next to its number, e.g.
+	// "7 call [step=checkout tenant=acme], wrong r.Method, ...".
+	Labels map[string]string
+
+	// faulted calls compare their input before DoError or Timeout end
+	// them, see FailOnCalls.
+	faulted bool
 }
 
 type Input struct {
+	// Method may be a set of methods, GET|HEAD, or AnyMethod.
 	Method string
 	Body   Body
 	Header http.Header
-	URL    *url.URL
+	// URL path may be a glob, * matches one segment or a part of it, **
+	// any number of segments and {name} one segment captured for templates
+	// as request.pathVars.name, e.g. /v1/*/items/** or /users/{id}.
+	URL *url.URL
+	// QueryMatch matches query values by rules, keys of URL query are
+	// compared exactly as well.
+	QueryMatch QueryMatch
+	// Deadline requires the request context to have a deadline, see
+	// CompareDeadline.
+	Deadline *Deadline
+	// ClientCert matches the client certificate of mTLS servers, see
+	// CompareClientCert.
+	ClientCert *ClientCert
+	// TransferEncoding is the encoding of the request body, TransferChunked
+	// or TransferIdentity, see CompareTransferEncoding.
+	TransferEncoding string
+	// Proxy matches proxy metadata headers, see CompareProxyHeaders.
+	Proxy *ProxyHeaders
+
+	// exactQuery compares the query of URL verbatim and rejects requests
+	// with a query when URL has none, see ParseWireMockMappings.
+	exactQuery bool
 }
 
 type Response struct {
 	StatusCode int
 	Body       Body
+	// BodyReader is written instead of Body and closed, it can be read once,
+	// so the call must not be repeated. NewTransport reports at Cleanup when
+	// the client left its response body unclosed.
+	BodyReader io.ReadCloser
 	Header     http.Header
+	// Trailer is sent after the body, see WriteTrailer.
+	Trailer http.Header
+	// TemplateHeader values are rendered per request like TemplateBody and
+	// added to Header by RenderResponse.
+	TemplateHeader http.Header
+	// Expires, LastModified and RetryAfter are written by RenderResponse as
+	// HTTP dates relative to now, see WithClock: Expires and RetryAfter
+	// after it, LastModified before it. Headers set in Header are kept.
+	Expires      time.Duration
+	LastModified time.Duration
+	RetryAfter   time.Duration
+	// TransferEncoding forces the response encoding, TransferChunked or
+	// TransferIdentity, by default the server picks it.
+	TransferEncoding string
+	// Close sends Connection: close, the server closes the connection after
+	// the response, see Server.AssertRedialedAfterClose.
+	Close bool
 }
 
 type Calls interface {
@@ -61,102 +166,331 @@ type Calls interface {
 	Done(calledTimes int) bool
 }
 
-type sequenceCalls []Call
+type sequenceCalls struct {
+	calls []Call
+	site  callSite
+}
 
 func SequenceCalls(calls ...Call) Calls {
-	return sequenceCalls(calls)
+	return sequenceCalls{calls: calls, site: definitionSite()}
 }
 
 func (s sequenceCalls) Call(calledTimes int) (Call, bool) {
 	calledTimes--
 
-	if calledTimes >= len(s) {
+	if calledTimes >= len(s.calls) {
 		return Call{}, false
 	}
 
-	return s[calledTimes], true
+	return s.calls[calledTimes], true
 }
 
 func (s sequenceCalls) Done(calledTimes int) bool {
-	if len(s) == 0 {
+	if len(s.calls) == 0 {
 		return true
 	}
 
-	return calledTimes == len(s)
+	return calledTimes == len(s.calls)
+}
+
+func (s sequenceCalls) validate(marshalJSON bool) error {
+	return validateCalls(s.calls, s.site, marshalJSON)
 }
 
-type staticCalls []Call
+type staticCalls struct {
+	calls     []Call
+	selection Selection
+	site      callSite
+}
 
+// StaticCalls serves the calls repeatedly in round robin, see RoundRobin,
+// they are never exhausted.
 func StaticCalls(calls ...Call) Calls {
-	return staticCalls(calls)
+	return staticCalls{calls: calls, selection: RoundRobin(), site: definitionSite()}
+}
+
+// SelectCalls serves the calls repeatedly, the selection picks the call for
+// every request, they are never exhausted.
+func SelectCalls(selection Selection, calls ...Call) Calls {
+	return staticCalls{calls: calls, selection: selection, site: definitionSite()}
 }
 
 func (s staticCalls) Call(calledTimes int) (Call, bool) {
-	if len(s) == 0 {
+	if len(s.calls) == 0 {
 		return Call{}, false
 	}
 
-	if len(s) == 1 || calledTimes == 1 {
-		return s[0], true
+	selection := s.selection
+	if selection == nil {
+		selection = RoundRobin()
 	}
 
-	index := (calledTimes - 1 + len(s)) % len(s)
-
-	return s[index], true
+	return s.calls[selection(calledTimes, len(s.calls))], true
 }
 
 func (staticCalls) Done(int) bool {
 	return true
 }
 
+func (s staticCalls) validate(marshalJSON bool) error {
+	return validateCalls(s.calls, s.site, marshalJSON)
+}
+
 type HandleCall func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call)
 
 type transport struct {
 	t           TestReporter
 	calledTimes atomic.Int64
+	sharded     *shardedCounter
 	handleCall  func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call)
 	calls       Calls
+	options     options
+	events      *eventBroker
+	webhooks    webhooks
+	bodies      responseBodies
+	transcript  *transcript
+	report      *jsonReport
+	generator   *Generator
+	grace       sync.Once
 }
 
+// NewHandlerTransport serves every request with the handler and reports
+// nothing, see NewVerifiedHandlerTransport for a verified one.
 func NewHandlerTransport(h http.Handler) http.RoundTripper {
 	return &transport{
 		t:     nilTestReporter{},
-		calls: staticCalls{{}},
+		calls: staticCalls{calls: []Call{{}}},
 		handleCall: func(_ TestReporter, w http.ResponseWriter, r *http.Request, _ Call) {
 			h.ServeHTTP(w, r)
 		},
 	}
 }
 
-func NewTransport(t TestReporter, calls Calls, handleCall HandleCall) http.RoundTripper {
+func NewTransport(t TestReporter, calls Calls, handleCall HandleCall, opts ...Option) http.RoundTripper {
+	ts := newTransport(t, calls, handleCall, opts)
+
+	t.Cleanup(ts.assert)
+	t.Cleanup(ts.webhooks.wait)
+	t.Cleanup(func() { ts.bodies.assert(t) })
+
+	return ts
+}
+
+func newTransport(t TestReporter, calls Calls, handleCall HandleCall, opts []Option) *transport {
 	ts := &transport{
 		t:          t,
 		calls:      calls,
 		handleCall: handleCall,
 	}
 
-	t.Cleanup(ts.assert)
+	for _, opt := range opts {
+		opt(&ts.options)
+	}
+
+	ts.generator = ts.options.generator
+	if ts.generator == nil {
+		ts.generator = NewGenerator()
+	}
+
+	if calls, ok := calls.(validator); ok {
+		if err := calls.validate(ts.options.jsonBodyCheck); err != nil {
+			t.Fatalf("%s", err)
+		}
+	}
+
+	if ts.options.shardedCalls {
+		if !shardable(calls) {
+			t.Fatalf("sharded calls, calls depend on the call number, use StaticCalls with one call")
+		}
+
+		ts.sharded = newShardedCounter()
+	}
+
+	if ts.options.transcript {
+		ts.transcript = &transcript{}
+		ts.t = failureReporter{TestReporter: t, transcript: ts.transcript}
+
+		t.Cleanup(func() { ts.transcript.print(t) })
+	}
+
+	if ts.options.jsonReport != "" {
+		ts.report = &jsonReport{path: ts.options.jsonReport}
+		ts.t = reportReporter{TestReporter: ts.t, report: ts.report}
+
+		t.Cleanup(func() { ts.report.write(t, ts.called()) })
+	}
 
 	return ts
 }
 
 func (h *transport) RoundTrip(r *http.Request) (*http.Response, error) {
-	calledTimes := h.calledTimes.Add(1)
+	calledTimes, t := h.next()
 
-	t := errorfTestReporterWithCallNumber(h.t, calledTimes)
-
-	call, ok := h.calls.Call(int(calledTimes))
+	call, ok := callFor(h.calls, int(calledTimes), r)
 	if !ok {
+		h.noteTranscript(calledTimes, r, "no expected calls left")
+
+		if h.options.callErrors {
+			t.Errorf("no expected calls left")
+
+			return nil, fmt.Errorf("%w, %w, call %d, %s %s", ErrUnexpectedCall, ErrNoCallsLeft, calledTimes, r.Method, r.URL)
+		}
+
 		t.Fatalf("no expected calls left")
 
-		return &http.Response{}, nil
+		w := newResponseWriter()
+		writeNoCallsLeft(w, StatusNoCallsLeft, calledTimes, r)
+
+		return w.result(r), nil
 	}
 
+	t = withLabels(t, call.Labels)
+
+	var failures *callErrorReporter
+
+	if h.options.callErrors {
+		failures = &callErrorReporter{TestReporter: t}
+		t = failures
+	}
+
+	defer h.matched(calledTimes)()
+
+	compareFaultedInput(t, r, call)
+
 	if call.DoError != nil {
+		h.noteTranscript(calledTimes, r, "error "+call.DoError.Error())
+
 		return nil, call.DoError
 	}
 
-	w := httptest.NewRecorder()
+	if call.Timeout {
+		return nil, waitTimeout(t, r)
+	}
+
+	if h.options.requestBodyCheck {
+		var check func()
+
+		r, check = checkRequestBody(t, r)
+		defer check()
+	}
+
+	w := newResponseWriter()
+
+	h.serveCall(t, w, r, call, calledTimes)
+
+	err := r.Context().Err()
+	if err != nil {
+		return nil, err
+	}
+
+	if failures != nil {
+		if err := failures.err(calledTimes); err != nil {
+			return nil, err
+		}
+	}
+
+	if call.FirstByte > 0 && !sleepContext(r.Context(), call.FirstByte) {
+		return nil, r.Context().Err()
+	}
+
+	resp := w.result(r)
+	resp.Body = newTransferBody(r.Context(), resp.Body, int64(w.body.Len()), call.BodyTransfer)
+
+	if h.options.bodyCloseCheck || call.Response.BodyReader != nil {
+		resp.Body = h.bodies.track(calledTimes, resp.Body)
+	}
+
+	if call.ReadWithin > 0 {
+		resp.Body = newReadWithinBody(t, resp.Body, call.ReadWithin)
+	}
+
+	return resp, nil
+}
+
+// next counts the call and returns its number with the reporter prefixing
+// errors with it.
+func (h *transport) next() (int64, TestReporter) {
+	if h.sharded != nil {
+		return h.sharded.add(), h.t
+	}
+
+	calledTimes := h.calledTimes.Add(1)
+
+	return calledTimes, errorfTestReporterWithCallNumber(h.t, calledTimes)
+}
+
+func (h *transport) called() int64 {
+	if h.sharded != nil {
+		return h.sharded.load()
+	}
+
+	return h.calledTimes.Load()
+}
+
+// matched records the matched call, the returned func is called when the
+// call is served.
+func (h *transport) matched(calledTimes int64) (served func()) {
+	if h.options.timeline != nil {
+		h.options.timeline.record(int(calledTimes))
+	}
+
+	if h.options.timings == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+
+	return func() {
+		h.options.timings.record(int(calledTimes), time.Since(start))
+	}
+}
+
+func (h *transport) serveCall(t TestReporter, w http.ResponseWriter, r *http.Request, call Call, calledTimes int64) {
+	if h.transcript != nil {
+		var finish func()
+
+		w, r, finish = h.transcript.record(calledTimes, w, r)
+		defer finish()
+	}
+
+	if h.options.concurrency != nil {
+		defer h.options.concurrency.begin()()
+	}
+
+	limitRequestBody(w, r, h.options.maxBodySize)
+
+	inputT := withFailureKind(t, ErrInputMismatch)
+
+	checkUserAgent(inputT, r, h.options.userAgent)
+
+	if h.options.headerKeysCheck {
+		checkHeaderKeys(inputT, r.Header)
+	}
+
+	for _, verify := range h.options.verifiers {
+		verify(inputT, r)
+	}
+
+	if h.options.history != nil {
+		var record func()
+
+		w, r, record = recordHistory(t, h.options.history, w, r)
+		defer record()
+	}
+
+	if h.options.sessions != nil {
+		r = h.options.sessions.attach(w, r)
+	}
+
+	if h.events != nil {
+		r = h.events.attach(r)
+	}
+
+	r = h.webhooks.attach(r)
+	r = h.options.match.attach(r)
+	r = attachClock(r, h.options.clock)
+	r = attachGenerator(r, h.generator)
+	r = attachDelayPlacement(r, h.options.delayPlacement)
 
 	handleCall := HandleCallCompareInput
 	if h.handleCall != nil {
@@ -164,57 +498,335 @@ func (h *transport) RoundTrip(r *http.Request) (*http.Response, error) {
 	}
 
 	handleCall(t, w, r, call)
+}
 
-	return w.Result(), nil
+func (h *transport) noteTranscript(calledTimes int64, r *http.Request, note string) {
+	if h.transcript != nil {
+		h.transcript.note(calledTimes, r, note)
+	}
 }
 
 func (h *transport) assert() {
-	calledTimes := h.calledTimes.Load()
+	h.awaitCalls()
+
+	calledTimes := h.called()
 
 	if !h.calls.Done(int(calledTimes)) {
+		if h.report != nil {
+			h.report.unfulfilled()
+		}
+
 		h.t.Errorf("assert handler calls, not all calls were handled")
 	}
 }
 
 func HandleCallCompareInput(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
-	CompareInput(t, r, call.Input)
+	r = compareCall(t, r, call, false)
+
+	RespondCall(t, w, r, call)
+}
+
+// CompareThen compares the input like HandleCallCompareInput and calls
+// respond to write the response, so the comparison is kept when only the
+// response writing is customized, e.g. streamed. respond gets the request
+// with the body rewound and the path variables of Input.URL, Call.Delay
+// placed before the comparison is waited for.
+func CompareThen(respond HandleCall) HandleCall {
+	return func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		r = compareCall(t, r, call, true)
+
+		respond(t, w, r, call)
+	}
+}
+
+// compareCall compares the input, keepBody keeps the request body readable
+// after the comparison even when the call response does not need it.
+func compareCall(t TestReporter, r *http.Request, call Call, keepBody bool) *http.Request {
+	delayAt(r, call, DelayBeforeCompare)
+
+	var rewind func()
+
+	if keepBody {
+		rewind = bufferRequestBody(r)
+	} else {
+		rewind = rewindableRequestBody(r, call)
+	}
+
+	CompareInput(withFailureKind(t, ErrInputMismatch), r, call.Input)
 
-	err := WriteResponse(w, call.Response)
+	rewind()
+
+	return withPathVars(r, call.Input.URL)
+}
+
+// RespondCall renders and writes the call response and schedules its
+// webhooks, it is the response half of HandleCallCompareInput.
+func RespondCall(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+	t = withFailureKind(t, ErrResponseWrite)
+
+	response, err := RenderResponse(r, call.Response)
 	if err != nil {
 		t.Errorf(err.Error())
+
+		return
+	}
+
+	delayAt(r, call, DelayBeforeResponse)
+
+	if stream, ok := response.Body.(StreamBody); ok {
+		DeclareTrailer(w, response.Trailer)
+		WriteHeader(w, response.Header, response.StatusCode)
+
+		err = stream.WriteStream(w, r)
+		if err == nil {
+			WriteTrailer(w, response.Trailer)
+		}
+	} else {
+		err = WriteResponse(w, response)
+	}
+
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+
+	ScheduleWebhooks(t, r, call.Webhooks)
+
+	delayAt(r, call, DelayAfterResponse)
+}
+
+// RenderResponse resolves response parts which depend on the request.
+func RenderResponse(r *http.Request, response Response) (Response, error) {
+	response = renderTimeHeaders(r, response)
+
+	// the body is rendered first, so header templates see the values it
+	// generated with the same generator.
+	generator := bodyGenerator(response.Body)
+
+	if body, ok := response.Body.(RequestBody); ok {
+		bytes, err := body.RequestBytes(r)
+		if err != nil {
+			return Response{}, fmt.Errorf("render response body, %w", err)
+		}
+
+		response.Body = RawBody(bytes)
+	}
+
+	if len(response.TemplateHeader) > 0 {
+		header, err := renderHeader(r, generator, response.Header, response.TemplateHeader)
+		if err != nil {
+			return Response{}, fmt.Errorf("render response header, %w", err)
+		}
+
+		response.Header = header
+		response.TemplateHeader = nil
+	}
+
+	if response.Close {
+		response.Header = response.Header.Clone()
+		if response.Header == nil {
+			response.Header = make(http.Header)
+		}
+
+		response.Header.Set("Connection", "close")
+	}
+
+	return renderTransferEncoding(response)
+}
+
+func waitTimeout(t TestReporter, r *http.Request) error {
+	ctx := r.Context()
+
+	if _, ok := ctx.Deadline(); !ok {
+		t.Errorf("call timeout, request context has no deadline")
+
+		return context.DeadlineExceeded
 	}
 
-	if call.Delay > 0 {
-		<-time.After(call.Delay)
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+// rewindableRequestBody buffers r.Body when the response or webhooks are
+// rendered from the request, so it can be read again after the input
+// comparison.
+func rewindableRequestBody(r *http.Request, call Call) (rewind func()) {
+	_, requestBody := call.Response.Body.(RequestBody)
+
+	requestBody = requestBody || len(call.Response.TemplateHeader) > 0 || webhooksReadRequestBody(call.Webhooks)
+
+	if !requestBody {
+		return func() {}
 	}
+
+	return bufferRequestBody(r)
+}
+
+// bufferRequestBody reads r.Body, so it can be read again after rewind.
+func bufferRequestBody(r *http.Request) (rewind func()) {
+	if r.Body == nil {
+		return func() {}
+	}
+
+	body, err := io.ReadAll(r.Body)
+
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), failingReader{err: err}))
+
+	return func() {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+}
+
+// failingReader returns err from every Read, io.EOF if err is nil.
+type failingReader struct {
+	err error
+}
+
+func (f failingReader) Read([]byte) (int, error) {
+	if f.err == nil {
+		return 0, io.EOF
+	}
+
+	return 0, f.err
+}
+
+// CompareStatusCode reports a status code different from expected, use it
+// with CompareHeader and CompareBody to check responses on the client side.
+func CompareStatusCode(t TestReporter, responseStatusCode, expectedStatusCode int) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	if responseStatusCode != expectedStatusCode {
+		t.Errorf("wrong response status code, expected %d, actual %d", expectedStatusCode, responseStatusCode)
+	}
+}
+
+// AssertResponse compares the response received by a client with expected,
+// zero StatusCode means 200, body comparison reads resp.Body to the end.
+// Response bodies rendered for the request are rendered with resp.Request.
+func AssertResponse(t TestReporter, resp *http.Response, expected Response) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	if resp.Request != nil {
+		rendered, err := RenderResponse(resp.Request, expected)
+		if err != nil {
+			t.Errorf(err.Error())
+
+			return
+		}
+
+		expected = rendered
+	}
+
+	statusCode := expected.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	CompareStatusCode(t, resp.StatusCode, statusCode)
+	CompareHeader(t, resp.Header, expected.Header)
+	CompareBody(t, resp.Body, expected.Body)
+	CompareHeader(t, resp.Trailer, expected.Trailer)
 }
 
 func CompareInput(t TestReporter, r *http.Request, input Input) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	match := matchOptionsFrom(r.Context())
+
 	CompareMethod(t, r.Method, input.Method)
-	CompareURL(t, r.URL, input.URL)
+
+	urlMatch := match
+	urlMatch.rawQuery = match.rawQuery || input.exactQuery
+
+	compareURL(t, r.URL, input.URL, urlMatch)
+
+	if input.exactQuery && input.URL.RawQuery == "" && r.URL.RawQuery != "" {
+		t.Errorf("wrong url.RawQuery, expected no query, actual %s", r.URL.RawQuery)
+	}
+
+	if len(input.QueryMatch) > 0 {
+		CompareQueryMatch(t, match.query(r.URL.Query()), input.QueryMatch)
+	}
+
 	CompareBody(t, r.Body, input.Body)
 	CompareHeader(t, r.Header, input.Header)
+	CompareDeadline(t, r.Context(), input.Deadline)
+	CompareClientCert(t, r, input.ClientCert)
+	CompareTransferEncoding(t, r, input.TransferEncoding)
+	CompareProxyHeaders(t, r.Header, input.Proxy)
 }
 
 func CompareMethod(t TestReporter, requestMethod, inputMethod string) {
-	if requestMethod != inputMethod {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	if !methodMatch(requestMethod, inputMethod) {
 		t.Errorf("wrong r.Method, expected %s, actual %s", inputMethod, requestMethod)
 	}
 }
 
 func CompareURL(t TestReporter, requestURL, inputURL *url.URL) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	compareURL(t, requestURL, inputURL, matchOptions{})
+}
+
+func compareURL(t TestReporter, requestURL, inputURL *url.URL, match matchOptions) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
 	if inputURL == nil {
 		return
 	}
 
-	if requestURL.Path != inputURL.Path {
+	reason, ok := match.comparePath(requestURL.Path, inputURL.Path)
+
+	switch {
+	case !ok && reason != "":
+		t.Errorf("wrong url.Path, expected %s, actual %s, %s", inputURL.Path, requestURL.Path, reason)
+	case !ok:
 		t.Errorf("wrong url.Path, expected %s, actual %s", inputURL.Path, requestURL.Path)
 	}
 
-	CompareQuery(t, requestURL.Query(), inputURL.Query())
+	if match.rawQuery {
+		CompareRawQuery(t, requestURL.RawQuery, inputURL.RawQuery)
+
+		return
+	}
+
+	CompareQuery(t, match.query(requestURL.Query()), match.query(inputURL.Query()))
+}
+
+// CompareRawQuery compares the query verbatim, order and encoding included.
+func CompareRawQuery(t TestReporter, requestRawQuery, inputRawQuery string) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	if inputRawQuery == "" {
+		return
+	}
+
+	if requestRawQuery != inputRawQuery {
+		t.Errorf("wrong url.RawQuery, expected %s, actual %s", inputRawQuery, requestRawQuery)
+	}
 }
 
 func CompareQuery(t TestReporter, requestQuery, inputQuery url.Values) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
 	if len(inputQuery) == 0 {
 		return
 	}
@@ -243,13 +855,27 @@ func CompareQuery(t TestReporter, requestQuery, inputQuery url.Values) {
 }
 
 func CompareBody(t TestReporter, requestBody io.Reader, inputBody Body) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
 	if requestBody == nil {
 		requestBody = io.NopCloser(new(bytes.Reader))
 	}
 
-	bodyBytes, err := io.ReadAll(requestBody)
+	buf, err := readPooled(requestBody)
+	defer putBuffer(buf)
+
 	if err != nil {
-		t.Errorf("read body from request, %s", err)
+		withFailureKind(t, ErrBodyRead).Errorf("read body from request, %s", err)
+
+		return
+	}
+
+	bodyBytes := buf.Bytes()
+
+	if comparer, ok := inputBody.(BodyComparer); ok {
+		comparer.CompareBody(t, bodyBytes)
 
 		return
 	}
@@ -271,6 +897,10 @@ func CompareBody(t TestReporter, requestBody io.Reader, inputBody Body) {
 }
 
 func CompareHeader(t TestReporter, requestHeader, inputHeader http.Header) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
 	keys := make([]string, 0, len(inputHeader))
 	for key := range inputHeader {
 		keys = append(keys, key)
@@ -293,13 +923,27 @@ func CompareHeader(t TestReporter, requestHeader, inputHeader http.Header) {
 }
 
 func WriteResponse(w http.ResponseWriter, response Response) error {
+	DeclareTrailer(w, response.Trailer)
 	WriteHeader(w, response.Header, response.StatusCode)
 
+	if response.BodyReader != nil {
+		err := writeBodyReader(w, response.BodyReader)
+		if err != nil {
+			return err
+		}
+
+		WriteTrailer(w, response.Trailer)
+
+		return nil
+	}
+
 	err := WriteBody(w, response.Body)
 	if err != nil {
 		return err
 	}
 
+	WriteTrailer(w, response.Trailer)
+
 	return nil
 }
 
@@ -308,8 +952,8 @@ func WriteHeader(w http.ResponseWriter, header http.Header, statusCode int) {
 		header = make(http.Header)
 	}
 
-	for key := range header {
-		for _, value := range header.Values(key) {
+	for key, values := range header {
+		for _, value := range values {
 			w.Header().Add(key, value)
 		}
 	}
@@ -321,6 +965,24 @@ func WriteHeader(w http.ResponseWriter, header http.Header, statusCode int) {
 	w.WriteHeader(statusCode)
 }
 
+// DeclareTrailer announces trailer keys in the Trailer header, it must be
+// called before WriteHeader, otherwise a server may send the body with
+// Content-Length and drop the trailer.
+func DeclareTrailer(w http.ResponseWriter, trailer http.Header) {
+	for _, key := range sortedKeys(trailer) {
+		w.Header().Add("Trailer", key)
+	}
+}
+
+// WriteTrailer sends trailer after the body, see DeclareTrailer.
+func WriteTrailer(w http.ResponseWriter, trailer http.Header) {
+	for key, values := range trailer {
+		for _, value := range values {
+			w.Header().Add(http.TrailerPrefix+key, value)
+		}
+	}
+}
+
 func WriteBody(w http.ResponseWriter, body Body) error {
 	if body == nil {
 		body = RawBody{}
@@ -331,6 +993,10 @@ func WriteBody(w http.ResponseWriter, body Body) error {
 		return fmt.Errorf("get response body bytes, unexpected error: %w", err)
 	}
 
+	if len(bytes) == 0 {
+		return nil
+	}
+
 	_, err = w.Write(bytes)
 	if err != nil {
 		return fmt.Errorf("write response body, unexpected error: %w", err)
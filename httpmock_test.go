@@ -992,9 +992,7 @@ func do(req request, expectedResponse Response) func(client *http.Client) error
 
 		tr := &testReporterMock{}
 
-		compareStatusCode(tr, resp.StatusCode, expectedResponse.StatusCode)
-		CompareBody(tr, resp.Body, expectedResponse.Body)
-		CompareHeader(tr, resp.Header, expectedResponse.Header)
+		AssertResponse(tr, resp, expectedResponse)
 
 		errs := make([]error, 0, len(tr.errorfCalls))
 
@@ -1118,12 +1116,6 @@ func (tm *testReporterMock) Cleanup(f func()) {
 	tm.t.Cleanup(f)
 }
 
-func compareStatusCode(t TestReporter, actual, expected int) {
-	if actual != expected {
-		t.Errorf("wrong response status code, expected %d, actual %d", expected, actual)
-	}
-}
-
 type bodyTest struct {
 	Name          string
 	Body          Body
@@ -1167,6 +1159,13 @@ func Test_Body(t *testing.T) {
 			Body:          JSONBody(jsonValue{Name: "amidman"}),
 			ExpectedBytes: []byte(`{"name":"amidman"}`),
 		},
+		{
+			Name: "dynamic body",
+			Body: DynamicBody(func() ([]byte, error) {
+				return []byte("dynamic"), nil
+			}),
+			ExpectedBytes: []byte("dynamic"),
+		},
 	}
 
 	for _, tst := range tests {
@@ -1174,6 +1173,31 @@ func Test_Body(t *testing.T) {
 	}
 }
 
+func Test_JSONBody_Cached(t *testing.T) {
+	value := map[string]int{"count": 1}
+
+	body := JSONBody(value)
+
+	first, _ := body.Bytes()
+
+	value["count"] = 2
+
+	second, _ := body.Bytes()
+
+	if string(first) != `{"count":1}` || string(second) != string(first) {
+		t.Errorf("json body not cached, first %s, second %s", first, second)
+	}
+
+	dynamic := DynamicBody(func() ([]byte, error) {
+		return json.Marshal(value)
+	})
+
+	bytes, _ := dynamic.Bytes()
+	if string(bytes) != `{"count":2}` {
+		t.Errorf("dynamic body not regenerated, actual %s", bytes)
+	}
+}
+
 type errorWriteResponseRecorder struct {
 	err error
 }
@@ -1295,3 +1319,103 @@ func Test_nilTestReporter(t *testing.T) {
 	n.Errorf("string", "", 1)
 	n.Fatalf("string", 1, "")
 }
+
+type helperTestReporter struct {
+	*testReporterMock
+	helperCalls int
+}
+
+func (h *helperTestReporter) Helper() {
+	h.helperCalls++
+}
+
+func Test_CompareStatusCode(t *testing.T) {
+	tr := &helperTestReporter{testReporterMock: &testReporterMock{t: t}}
+
+	CompareStatusCode(tr, http.StatusOK, http.StatusOK)
+	CompareStatusCode(tr, http.StatusNotFound, http.StatusOK)
+	CompareHeader(tr, http.Header{}, http.Header{})
+
+	expected := []testReporterCall{
+		{format: "wrong response status code, expected %d, actual %d", args: []any{http.StatusOK, http.StatusNotFound}},
+	}
+
+	if !reflect.DeepEqual(tr.errorfCalls, expected) {
+		t.Errorf("wrong errorf calls, expected %v, actual %v", expected, tr.errorfCalls)
+	}
+
+	if tr.helperCalls != 3 {
+		t.Errorf("wrong Helper calls count, expected 3, actual %d", tr.helperCalls)
+	}
+}
+
+func Test_AssertResponse(t *testing.T) {
+	transport := NewTransport(t,
+		StaticCalls(Call{
+			Input: Input{Method: http.MethodGet},
+			Response: Resp(http.StatusCreated).
+				Text("created").
+				Header("X-Path", "{{request.path}}").
+				Response(),
+		}),
+		nil,
+	)
+
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	AssertResponse(t, resp, Resp(http.StatusCreated).Text("created").Header("X-Path", "{{request.path}}").Response())
+
+	resp, err = client.Get("http://localhost/orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	AssertResponse(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "wrong response status code, expected %d, actual %d", args: []any{http.StatusOK, http.StatusCreated}},
+				{format: "wrong header values by key %s, expect [%s], actual [%s]", args: []any{"X-Path", "/items", "/orders"}},
+				{format: "body not equal, expected %s actual %s", args: []any{"", "created"}},
+			},
+			nil,
+		)(t),
+		resp,
+		Response{Header: http.Header{"X-Path": {"/items"}}},
+	)
+}
+
+func Test_Call_Labels(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "2 call [step=checkout tenant=acme], wrong r.Method, expected %s, actual %s", args: []any{http.MethodPost, http.MethodGet}},
+			},
+			nil,
+		)(t),
+		SequenceCalls(
+			Call{Input: Input{Method: http.MethodGet}, Labels: map[string]string{"step": "cart"}},
+			Call{Input: Input{Method: http.MethodPost}, Labels: map[string]string{"tenant": "acme", "step": "checkout"}},
+		),
+		nil,
+	)
+
+	client := &http.Client{Transport: transport}
+
+	for range 2 {
+		resp, err := client.Get("http://localhost/cart")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+}
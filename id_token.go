@@ -0,0 +1,79 @@
+package httpmock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// IDTokenAudience verifies the audience of the Google-style identity token
+// sent as the Authorization bearer token, as required by IAP and Cloud Run.
+// The token is decoded without signature verification. An empty audience
+// expects the base url of the request, the mock server url, e.g.
+// https://127.0.0.1:41234.
+func IDTokenAudience(audience string) RequestVerifier {
+	return func(t TestReporter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			t.Errorf("id token, Authorization bearer token not presented")
+
+			return
+		}
+
+		audiences, err := idTokenAudiences(token)
+		if err != nil {
+			t.Errorf("id token, malformed token, %s", err)
+
+			return
+		}
+
+		expected := audience
+		if expected == "" {
+			expected = requestBaseURL(r)
+		}
+
+		if !slices.Contains(audiences, expected) {
+			t.Errorf("id token, wrong audience, expected %s, actual %s", expected, strings.Join(audiences, ","))
+		}
+	}
+}
+
+// idTokenAudiences decodes the aud claim, a string or an array of strings.
+func idTokenAudiences(token string) ([]string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("expected header.payload.signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var claims struct {
+		Audience json.RawMessage `json:"aud"`
+	}
+
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, err
+	}
+
+	var audience string
+
+	if json.Unmarshal(claims.Audience, &audience) == nil {
+		return []string{audience}, nil
+	}
+
+	var audiences []string
+
+	err = json.Unmarshal(claims.Audience, &audiences)
+	if err != nil {
+		return nil, errors.New("aud claim is neither a string nor an array of strings")
+	}
+
+	return audiences, nil
+}
@@ -0,0 +1,61 @@
+package httpmock
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+)
+
+func idToken(claims string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	return encode([]byte(`{"alg":"RS256"}`)) + "." + encode([]byte(claims)) + "." + encode([]byte("signature"))
+}
+
+func Test_IDTokenAudience(t *testing.T) {
+	server := NewServer(t,
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}}),
+		nil,
+		WithRequestVerifier(IDTokenAudience("")),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/items", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+idToken(`{"aud":["other","`+server.URL+`"]}`))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_IDTokenAudience_Wrong(t *testing.T) {
+	verify := IDTokenAudience("https://billing.run.app")
+
+	for _, tc := range []struct {
+		name          string
+		authorization string
+		expected      testReporterCall
+	}{
+		{
+			name:          "wrong audience",
+			authorization: "Bearer " + idToken(`{"aud":"https://orders.run.app"}`),
+			expected: testReporterCall{
+				format: "id token, wrong audience, expected %s, actual %s",
+				args:   []any{"https://billing.run.app", "https://orders.run.app"},
+			},
+		},
+		{
+			name:     "no token",
+			expected: testReporterCall{format: "id token, Authorization bearer token not presented"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodGet, "https://billing.run.app/", http.NoBody)
+			r.Header.Set("Authorization", tc.authorization)
+
+			verify(ExpectFailureTestReporter([]testReporterCall{tc.expected}, nil)(t), r)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package httpmock
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// IdempotencyKeyHeader is the header checked by IdempotencyKey.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeys struct {
+	mu          sync.Mutex
+	byOperation map[string]string
+	byKey       map[string]string
+}
+
+// IdempotencyKey checks POST and PATCH requests carry the Idempotency-Key
+// header, retries of an operation reuse its key and distinct operations use
+// distinct keys. Requests with equal method, path and body are retries of
+// one operation, calls with the "operation" label are identified by it
+// instead. handleCall serves the calls, nil means HandleCallCompareInput.
+func IdempotencyKey(handleCall HandleCall) HandleCall {
+	if handleCall == nil {
+		handleCall = HandleCallCompareInput
+	}
+
+	keys := &idempotencyKeys{
+		byOperation: make(map[string]string),
+		byKey:       make(map[string]string),
+	}
+
+	return func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+			keys.compare(t, r, call)
+		}
+
+		handleCall(t, w, r, call)
+	}
+}
+
+func (i *idempotencyKeys) compare(t TestReporter, r *http.Request, call Call) {
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		t.Errorf("header %s not presented", IdempotencyKeyHeader)
+
+		return
+	}
+
+	operation := call.Labels["operation"]
+	if operation == "" {
+		operation = requestFingerprint(r)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if expected, ok := i.byOperation[operation]; ok && expected != key {
+		t.Errorf("retried %s %s uses new %s, expected %s, actual %s", r.Method, r.URL.Path, IdempotencyKeyHeader, expected, key)
+
+		return
+	}
+
+	if previous, ok := i.byKey[key]; ok && previous != operation {
+		t.Errorf("%s %s reused by distinct operation %s %s", IdempotencyKeyHeader, key, r.Method, r.URL.Path)
+
+		return
+	}
+
+	i.byOperation[operation] = key
+	i.byKey[key] = operation
+}
+
+// requestFingerprint hashes method, path and body, the body is kept
+// readable.
+func requestFingerprint(r *http.Request) string {
+	hash := sha256.New()
+
+	_, _ = io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+
+	if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), failingReader{err: err}))
+
+		hash.Write(body)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
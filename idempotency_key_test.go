@@ -0,0 +1,71 @@
+package httpmock
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_IdempotencyKey(t *testing.T) {
+	payment := Call{Input: Input{Method: http.MethodPost, Body: RawBody(`{"amount":10}`)}}
+	refund := Call{Input: Input{Method: http.MethodPost, Body: RawBody(`{"amount":5}`)}}
+
+	post := func(transport http.RoundTripper, key, body string) {
+		req, err := http.NewRequest(http.MethodPost, "http://localhost/payments", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	t.Run("retry reuses key", func(t *testing.T) {
+		transport := NewTransport(t, SequenceCalls(payment, payment, refund), IdempotencyKey(nil))
+
+		post(transport, "a", `{"amount":10}`)
+		post(transport, "a", `{"amount":10}`)
+		post(transport, "b", `{"amount":5}`)
+	})
+
+	t.Run("violations", func(t *testing.T) {
+		transport := NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "1 call, header %s not presented", args: []any{IdempotencyKeyHeader}},
+					{format: "3 call, retried %s %s uses new %s, expected %s, actual %s", args: []any{http.MethodPost, "/payments", IdempotencyKeyHeader, "a", "b"}},
+					{format: "4 call, %s %s reused by distinct operation %s %s", args: []any{IdempotencyKeyHeader, "a", http.MethodPost, "/payments"}},
+				},
+				nil,
+			)(t),
+			SequenceCalls(payment, payment, payment, refund),
+			IdempotencyKey(nil),
+		)
+
+		post(transport, "", `{"amount":10}`)
+		post(transport, "a", `{"amount":10}`)
+		post(transport, "b", `{"amount":10}`)
+		post(transport, "a", `{"amount":5}`)
+	})
+
+	t.Run("operation label", func(t *testing.T) {
+		charge := Call{Input: Input{Method: http.MethodPost, Body: RawBody(`{"amount":10}`)}}
+
+		first, second := charge, charge
+		first.Labels = map[string]string{"operation": "first charge"}
+		second.Labels = map[string]string{"operation": "second charge"}
+
+		transport := NewTransport(t, SequenceCalls(first, second), IdempotencyKey(nil))
+
+		post(transport, "a", `{"amount":10}`)
+		post(transport, "b", `{"amount":10}`)
+	})
+}
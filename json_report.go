@@ -0,0 +1,141 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WithJSONReport appends a JSON line describing mismatches and unfulfilled
+// calls to the file at path at Cleanup, when the transport reported any.
+// Transports of the whole test suite may share the file, lines are written
+// whole, so CI can aggregate them.
+//
+//	{"test":"Test_Client","time":"...","handled":2,"unfulfilled":true,"failures":[{"call":1,"message":"wrong r.Method, expected GET, actual POST"}]}
+func WithJSONReport(path string) Option {
+	return func(o *options) {
+		o.jsonReport = path
+	}
+}
+
+// ReportFailure is a reported error, Call is 0 for errors not bound to a
+// call.
+type ReportFailure struct {
+	Call    int    `json:"call,omitempty"`
+	Labels  string `json:"labels,omitempty"`
+	Message string `json:"message"`
+	Fatal   bool   `json:"fatal,omitempty"`
+}
+
+// Report is a line of the WithJSONReport file.
+type Report struct {
+	Test        string          `json:"test,omitempty"`
+	Time        time.Time       `json:"time"`
+	Handled     int64           `json:"handled"`
+	Unfulfilled bool            `json:"unfulfilled"`
+	Failures    []ReportFailure `json:"failures"`
+}
+
+// jsonReportMu serializes writes of transports sharing the report file.
+var jsonReportMu sync.Mutex
+
+type jsonReport struct {
+	path string
+
+	mu     sync.Mutex
+	report Report
+}
+
+func (j *jsonReport) add(message string, fatal bool) {
+	failure := ReportFailure{Message: message, Fatal: fatal}
+
+	if match := callPrefixPattern.FindStringSubmatch(message); match != nil {
+		failure.Call, _ = strconv.Atoi(match[1])
+		failure.Labels = match[2]
+		failure.Message = match[3]
+	}
+
+	j.mu.Lock()
+	j.report.Failures = append(j.report.Failures, failure)
+	j.mu.Unlock()
+}
+
+func (j *jsonReport) unfulfilled() {
+	j.mu.Lock()
+	j.report.Unfulfilled = true
+	j.mu.Unlock()
+}
+
+func (j *jsonReport) write(t TestReporter, handled int64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if len(j.report.Failures) == 0 && !j.report.Unfulfilled {
+		return
+	}
+
+	if named, ok := t.(interface{ Name() string }); ok {
+		j.report.Test = named.Name()
+	}
+
+	j.report.Time = time.Now()
+	j.report.Handled = handled
+
+	data, err := json.Marshal(j.report)
+	if err != nil {
+		t.Errorf("json report, marshal, %s", err)
+
+		return
+	}
+
+	err = appendLine(j.path, data)
+	if err != nil {
+		t.Errorf("json report, %s", err)
+	}
+}
+
+func appendLine(path string, data []byte) error {
+	jsonReportMu.Lock()
+	defer jsonReportMu.Unlock()
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open %s, %w", path, err)
+	}
+
+	_, err = file.Write(append(data, '\n'))
+	if err != nil {
+		file.Close()
+
+		return fmt.Errorf("write %s, %w", path, err)
+	}
+
+	return file.Close()
+}
+
+// reportReporter records every reported error into the report.
+type reportReporter struct {
+	TestReporter
+	report *jsonReport
+}
+
+func (r reportReporter) Errorf(format string, args ...any) {
+	if h, ok := r.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
+	r.report.add(fmt.Sprintf(format, args...), false)
+	r.TestReporter.Errorf(format, args...)
+}
+
+func (r reportReporter) Fatalf(format string, args ...any) {
+	if h, ok := r.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
+	r.report.add(fmt.Sprintf(format, args...), true)
+	r.TestReporter.Fatalf(format, args...)
+}
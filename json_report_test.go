@@ -0,0 +1,89 @@
+package httpmock
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_WithJSONReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.jsonl")
+
+	t.Run("failed", func(t *testing.T) {
+		reporter := &testReporterMock{t: t}
+
+		transport := NewTransport(reporter,
+			SequenceCalls(
+				Call{Input: Input{Method: http.MethodGet}},
+				Call{Input: Input{Method: http.MethodGet}},
+			),
+			nil,
+			WithJSONReport(path),
+		)
+
+		resp, err := (&http.Client{Transport: transport}).Post("http://localhost/items", "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+
+	t.Run("passed", func(t *testing.T) {
+		transport := NewTransport(t,
+			SequenceCalls(Call{Input: Input{Method: http.MethodGet}}),
+			nil,
+			WithJSONReport(path),
+		)
+
+		resp, err := (&http.Client{Transport: transport}).Get("http://localhost/items")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var reports []Report
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var report Report
+
+		err := json.Unmarshal(scanner.Bytes(), &report)
+		if err != nil {
+			t.Fatalf("unmarshal report line, %s", err)
+		}
+
+		reports = append(reports, report)
+	}
+
+	if len(reports) != 1 {
+		t.Fatalf("wrong report lines count, expected 1, actual %d", len(reports))
+	}
+
+	report := reports[0]
+
+	if report.Handled != 1 || !report.Unfulfilled {
+		t.Errorf("wrong report calls, expected handled 1 and unfulfilled, actual %d, %t", report.Handled, report.Unfulfilled)
+	}
+
+	expectedFailures := []ReportFailure{
+		{Call: 1, Message: "wrong r.Method, expected GET, actual POST"},
+		{Message: "assert handler calls, not all calls were handled"},
+	}
+
+	if !reflect.DeepEqual(report.Failures, expectedFailures) {
+		t.Errorf("wrong report failures, expected %+v, actual %+v", expectedFailures, report.Failures)
+	}
+}
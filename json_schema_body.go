@@ -0,0 +1,596 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+type jsonSchemaBody struct {
+	schema []byte
+	loaded *loadedJSONSchema
+}
+
+// loadedJSONSchema is the schema unmarshaled once, with pattern and
+// patternProperties regexps compiled, on the first compared body.
+type loadedJSONSchema struct {
+	once     sync.Once
+	root     any
+	patterns map[string]jsonSchemaPattern
+	err      error
+}
+
+type jsonSchemaPattern struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// JSONSchemaBody validates the request body against a JSON Schema instead of
+// comparing it with a concrete value, every violation is reported separately.
+//
+// Supported keywords: type, enum, const, properties, required,
+// additionalProperties, patternProperties, minProperties, maxProperties,
+// items, minItems, maxItems, uniqueItems, minLength, maxLength, pattern,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf,
+// allOf, anyOf, oneOf, not and local $ref. A $ref which resolves back to
+// itself for the same value, e.g. {"$ref":"#"}, is reported as a violation,
+// recursive schemas descending into properties or items are supported.
+func JSONSchemaBody(schema []byte) Body {
+	return jsonSchemaBody{schema: schema, loaded: &loadedJSONSchema{}}
+}
+
+func (j jsonSchemaBody) Bytes() ([]byte, error) {
+	return j.schema, nil
+}
+
+func (j jsonSchemaBody) CompareBody(t TestReporter, body []byte) {
+	loaded := j.loaded
+
+	loaded.once.Do(func() {
+		loaded.load(j.schema)
+	})
+
+	if loaded.err != nil {
+		t.Errorf("unmarshal json schema, %s", loaded.err)
+
+		return
+	}
+
+	var value any
+
+	err := json.Unmarshal(body, &value)
+	if err != nil {
+		t.Errorf("unmarshal body as json, %s, body %s", err, string(body))
+
+		return
+	}
+
+	v := jsonSchemaValidator{
+		root:     loaded.root,
+		patterns: loaded.patterns,
+		refs:     make(map[jsonSchemaRef]bool),
+	}
+
+	for _, violation := range v.validate(loaded.root, "#", value) {
+		t.Errorf("json schema violation at %s, %s", violation.path, violation.message)
+	}
+}
+
+func (l *loadedJSONSchema) load(schema []byte) {
+	l.err = json.Unmarshal(schema, &l.root)
+	if l.err != nil {
+		return
+	}
+
+	l.patterns = make(map[string]jsonSchemaPattern)
+
+	l.compilePatterns(l.root)
+}
+
+// compilePatterns compiles pattern values and patternProperties keys of
+// every subschema.
+func (l *loadedJSONSchema) compilePatterns(schema any) {
+	switch schema := schema.(type) {
+	case map[string]any:
+		if pattern, ok := schema["pattern"].(string); ok {
+			l.compile(pattern)
+		}
+
+		if patternProperties, ok := schema["patternProperties"].(map[string]any); ok {
+			for pattern := range patternProperties {
+				l.compile(pattern)
+			}
+		}
+
+		for _, subschema := range schema {
+			l.compilePatterns(subschema)
+		}
+	case []any:
+		for _, subschema := range schema {
+			l.compilePatterns(subschema)
+		}
+	}
+}
+
+func (l *loadedJSONSchema) compile(pattern string) {
+	if _, ok := l.patterns[pattern]; ok {
+		return
+	}
+
+	re, err := regexp.Compile(pattern)
+
+	l.patterns[pattern] = jsonSchemaPattern{re: re, err: err}
+}
+
+type jsonSchemaViolation struct {
+	path    string
+	message string
+}
+
+// jsonSchemaRef is a $ref being resolved for the value at the path, a ref
+// resolved again for the same path is a cycle.
+type jsonSchemaRef struct {
+	ref  string
+	path string
+}
+
+type jsonSchemaValidator struct {
+	root     any
+	patterns map[string]jsonSchemaPattern
+	refs     map[jsonSchemaRef]bool
+}
+
+func (v jsonSchemaValidator) validate(schema any, path string, value any) []jsonSchemaViolation {
+	switch schema := schema.(type) {
+	case bool:
+		if !schema {
+			return []jsonSchemaViolation{{path: path, message: "value is not allowed"}}
+		}
+
+		return nil
+	case map[string]any:
+		return v.validateObjectSchema(schema, path, value)
+	default:
+		return []jsonSchemaViolation{{path: path, message: fmt.Sprintf("invalid schema %v", schema)}}
+	}
+}
+
+func (v jsonSchemaValidator) validateObjectSchema(schema map[string]any, path string, value any) []jsonSchemaViolation {
+	if ref, ok := schema["$ref"].(string); ok {
+		key := jsonSchemaRef{ref: ref, path: path}
+		if v.refs[key] {
+			return []jsonSchemaViolation{{path: path, message: fmt.Sprintf("$ref %s cycle, it resolves to itself for the same value", ref)}}
+		}
+
+		resolved, err := v.resolveRef(ref)
+		if err != nil {
+			return []jsonSchemaViolation{{path: path, message: err.Error()}}
+		}
+
+		v.refs[key] = true
+		defer delete(v.refs, key)
+
+		return v.validate(resolved, path, value)
+	}
+
+	var violations []jsonSchemaViolation
+
+	add := func(format string, args ...any) {
+		violations = append(violations, jsonSchemaViolation{path: path, message: fmt.Sprintf(format, args...)})
+	}
+
+	if types, ok := schema["type"]; ok && !jsonSchemaTypeMatches(types, value) {
+		add("wrong type, expected %s, actual %s", jsonSchemaTypesString(types), jsonTypeOf(value))
+
+		return violations
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slicesContainsDeep(enum, value) {
+		add("value %s is not one of enum %s", jsonString(value), jsonString(enum))
+	}
+
+	if constValue, ok := schema["const"]; ok && !reflect.DeepEqual(constValue, value) {
+		add("wrong const value, expected %s, actual %s", jsonString(constValue), jsonString(value))
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		violations = append(violations, v.validateObject(schema, path, value)...)
+	case []any:
+		violations = append(violations, v.validateArray(schema, path, value)...)
+	case string:
+		violations = append(violations, v.validateString(schema, path, value)...)
+	case float64:
+		violations = append(violations, validateJSONNumber(schema, path, value)...)
+	}
+
+	violations = append(violations, v.validateCombinators(schema, path, value)...)
+
+	return violations
+}
+
+func (v jsonSchemaValidator) validateObject(schema map[string]any, path string, value map[string]any) []jsonSchemaViolation {
+	var violations []jsonSchemaViolation
+
+	add := func(path, format string, args ...any) {
+		violations = append(violations, jsonSchemaViolation{path: path, message: fmt.Sprintf(format, args...)})
+	}
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			name, _ := name.(string)
+
+			if _, ok := value[name]; !ok {
+				add(path, "missing required property %s", name)
+			}
+		}
+	}
+
+	if minProperties, ok := schema["minProperties"].(float64); ok && float64(len(value)) < minProperties {
+		add(path, "too few properties, expected at least %s, actual %d", formatJSONNumber(minProperties), len(value))
+	}
+
+	if maxProperties, ok := schema["maxProperties"].(float64); ok && float64(len(value)) > maxProperties {
+		add(path, "too many properties, expected at most %s, actual %d", formatJSONNumber(maxProperties), len(value))
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	patternProperties, _ := schema["patternProperties"].(map[string]any)
+	additionalProperties, hasAdditionalProperties := schema["additionalProperties"]
+
+	for _, name := range sortedKeys(value) {
+		propertyPath := path + "/" + escapeJSONPointer(name)
+		matched := false
+
+		if propertySchema, ok := properties[name]; ok {
+			matched = true
+
+			violations = append(violations, v.validate(propertySchema, propertyPath, value[name])...)
+		}
+
+		for _, pattern := range sortedKeys(patternProperties) {
+			re, err := v.pattern(pattern)
+			if err != nil {
+				add(path, "compile pattern property %s, %s", pattern, err)
+
+				continue
+			}
+
+			if re.MatchString(name) {
+				matched = true
+
+				violations = append(violations, v.validate(patternProperties[pattern], propertyPath, value[name])...)
+			}
+		}
+
+		if matched || !hasAdditionalProperties {
+			continue
+		}
+
+		if allowed, ok := additionalProperties.(bool); ok {
+			if !allowed {
+				add(path, "additional property %s is not allowed", name)
+			}
+
+			continue
+		}
+
+		violations = append(violations, v.validate(additionalProperties, propertyPath, value[name])...)
+	}
+
+	return violations
+}
+
+func (v jsonSchemaValidator) validateArray(schema map[string]any, path string, value []any) []jsonSchemaViolation {
+	var violations []jsonSchemaViolation
+
+	add := func(format string, args ...any) {
+		violations = append(violations, jsonSchemaViolation{path: path, message: fmt.Sprintf(format, args...)})
+	}
+
+	if minItems, ok := schema["minItems"].(float64); ok && float64(len(value)) < minItems {
+		add("too few items, expected at least %s, actual %d", formatJSONNumber(minItems), len(value))
+	}
+
+	if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(value)) > maxItems {
+		add("too many items, expected at most %s, actual %d", formatJSONNumber(maxItems), len(value))
+	}
+
+	if unique, ok := schema["uniqueItems"].(bool); ok && unique {
+		for i := range value {
+			for j := i + 1; j < len(value); j++ {
+				if reflect.DeepEqual(value[i], value[j]) {
+					add("items %d and %d are equal, expected unique items", i, j)
+				}
+			}
+		}
+	}
+
+	switch items := schema["items"].(type) {
+	case []any:
+		for i, itemSchema := range items {
+			if i >= len(value) {
+				break
+			}
+
+			violations = append(violations, v.validate(itemSchema, path+"/"+strconv.Itoa(i), value[i])...)
+		}
+	case nil:
+	default:
+		for i, item := range value {
+			violations = append(violations, v.validate(items, path+"/"+strconv.Itoa(i), item)...)
+		}
+	}
+
+	return violations
+}
+
+func (v jsonSchemaValidator) validateString(schema map[string]any, path string, value string) []jsonSchemaViolation {
+	var violations []jsonSchemaViolation
+
+	add := func(format string, args ...any) {
+		violations = append(violations, jsonSchemaViolation{path: path, message: fmt.Sprintf(format, args...)})
+	}
+
+	length := utf8.RuneCountInString(value)
+
+	if minLength, ok := schema["minLength"].(float64); ok && float64(length) < minLength {
+		add("string is too short, expected at least %s characters, actual %d", formatJSONNumber(minLength), length)
+	}
+
+	if maxLength, ok := schema["maxLength"].(float64); ok && float64(length) > maxLength {
+		add("string is too long, expected at most %s characters, actual %d", formatJSONNumber(maxLength), length)
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := v.pattern(pattern)
+
+		switch {
+		case err != nil:
+			add("compile pattern %s, %s", pattern, err)
+		case !re.MatchString(value):
+			add("string %q does not match pattern %s", value, pattern)
+		}
+	}
+
+	return violations
+}
+
+// pattern returns the regexp compiled when the schema was loaded.
+func (v jsonSchemaValidator) pattern(pattern string) (*regexp.Regexp, error) {
+	compiled, ok := v.patterns[pattern]
+	if !ok {
+		return regexp.Compile(pattern)
+	}
+
+	return compiled.re, compiled.err
+}
+
+func validateJSONNumber(schema map[string]any, path string, value float64) []jsonSchemaViolation {
+	var violations []jsonSchemaViolation
+
+	add := func(format string, args ...any) {
+		violations = append(violations, jsonSchemaViolation{path: path, message: fmt.Sprintf(format, args...)})
+	}
+
+	formatted := formatJSONNumber(value)
+
+	if minimum, ok := schema["minimum"].(float64); ok && value < minimum {
+		add("%s is less than minimum %s", formatted, formatJSONNumber(minimum))
+	}
+
+	if maximum, ok := schema["maximum"].(float64); ok && value > maximum {
+		add("%s is greater than maximum %s", formatted, formatJSONNumber(maximum))
+	}
+
+	if minimum, ok := schema["exclusiveMinimum"].(float64); ok && value <= minimum {
+		add("%s is less than or equal to exclusive minimum %s", formatted, formatJSONNumber(minimum))
+	}
+
+	if maximum, ok := schema["exclusiveMaximum"].(float64); ok && value >= maximum {
+		add("%s is greater than or equal to exclusive maximum %s", formatted, formatJSONNumber(maximum))
+	}
+
+	if multipleOf, ok := schema["multipleOf"].(float64); ok && multipleOf > 0 {
+		quotient := value / multipleOf
+
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			add("%s is not a multiple of %s", formatted, formatJSONNumber(multipleOf))
+		}
+	}
+
+	return violations
+}
+
+func (v jsonSchemaValidator) validateCombinators(schema map[string]any, path string, value any) []jsonSchemaViolation {
+	var violations []jsonSchemaViolation
+
+	add := func(format string, args ...any) {
+		violations = append(violations, jsonSchemaViolation{path: path, message: fmt.Sprintf(format, args...)})
+	}
+
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, subschema := range allOf {
+			violations = append(violations, v.validate(subschema, path, value)...)
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok && v.countValid(anyOf, path, value) == 0 {
+		add("value does not match any schema from anyOf")
+	}
+
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		valid := v.countValid(oneOf, path, value)
+		if valid != 1 {
+			add("value must match exactly one schema from oneOf, matched %d", valid)
+		}
+	}
+
+	if not, ok := schema["not"]; ok && len(v.validate(not, path, value)) == 0 {
+		add("value must not match schema from not")
+	}
+
+	return violations
+}
+
+func (v jsonSchemaValidator) countValid(schemas []any, path string, value any) int {
+	valid := 0
+
+	for _, subschema := range schemas {
+		if len(v.validate(subschema, path, value)) == 0 {
+			valid++
+		}
+	}
+
+	return valid
+}
+
+func (v jsonSchemaValidator) resolveRef(ref string) (any, error) {
+	if ref == "#" {
+		return v.root, nil
+	}
+
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %s, only local references are supported", ref)
+	}
+
+	current := v.root
+
+	for _, token := range strings.Split(pointer, "/") {
+		token = unescapeJSONPointer(token)
+
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("resolve $ref %s, %s is not an object", ref, token)
+		}
+
+		current, ok = object[token]
+		if !ok {
+			return nil, fmt.Errorf("resolve $ref %s, %s not found", ref, token)
+		}
+	}
+
+	return current, nil
+}
+
+func jsonSchemaTypeMatches(types any, value any) bool {
+	switch types := types.(type) {
+	case string:
+		return jsonTypeMatches(types, value)
+	case []any:
+		for _, tp := range types {
+			tp, _ := tp.(string)
+
+			if jsonTypeMatches(tp, value) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return true
+	}
+}
+
+func jsonTypeMatches(tp string, value any) bool {
+	actual := jsonTypeOf(value)
+
+	if tp == "number" && actual == "integer" {
+		return true
+	}
+
+	return tp == actual
+}
+
+func jsonSchemaTypesString(types any) string {
+	switch types := types.(type) {
+	case []any:
+		names := make([]string, 0, len(types))
+
+		for _, tp := range types {
+			names = append(names, fmt.Sprint(tp))
+		}
+
+		return strings.Join(names, "|")
+	default:
+		return fmt.Sprint(types)
+	}
+}
+
+func jsonTypeOf(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer"
+		}
+
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func jsonString(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
+
+func formatJSONNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func slicesContainsDeep(values []any, value any) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+var jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func escapeJSONPointer(token string) string {
+	return jsonPointerEscaper.Replace(token)
+}
+
+func unescapeJSONPointer(token string) string {
+	return jsonPointerUnescaper.Replace(token)
+}
@@ -0,0 +1,248 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const userJSONSchema = `{
+	"type": "object",
+	"required": ["id", "name", "tags"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+		"role": {"enum": ["admin", "user"]},
+		"tags": {
+			"type": "array",
+			"maxItems": 2,
+			"uniqueItems": true,
+			"items": {"$ref": "#/$defs/tag"}
+		}
+	},
+	"$defs": {
+		"tag": {"type": "string"}
+	}
+}`
+
+type jsonSchemaBodyTest struct {
+	Name         string
+	Schema       string
+	Body         string
+	TestReporter func(t *testing.T) TestReporter
+}
+
+func (j *jsonSchemaBodyTest) Test(t *testing.T) {
+	tr := j.TestReporter(t)
+
+	CompareBody(tr, strings.NewReader(j.Body), JSONSchemaBody([]byte(j.Schema)))
+}
+
+func Test_JSONSchemaBody(t *testing.T) {
+	tests := []*jsonSchemaBodyTest{
+		{
+			Name:         "valid body",
+			Schema:       userJSONSchema,
+			Body:         `{"id":1,"name":"amidman","role":"admin","tags":["a","b"]}`,
+			TestReporter: ExpectSuccessTestReporter,
+		},
+		{
+			Name:   "every violation reported separately",
+			Schema: userJSONSchema,
+			Body:   `{"id":0,"name":"A","role":"guest","tags":["a","a",1],"extra":true}`,
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#", "additional property extra is not allowed"},
+					},
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#/id", "0 is less than minimum 1"},
+					},
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#/name", "string is too short, expected at least 2 characters, actual 1"},
+					},
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#/name", `string "A" does not match pattern ^[a-z]+$`},
+					},
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#/role", `value "guest" is not one of enum ["admin","user"]`},
+					},
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#/tags", "too many items, expected at most 2, actual 3"},
+					},
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#/tags", "items 0 and 1 are equal, expected unique items"},
+					},
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#/tags/2", "wrong type, expected string, actual integer"},
+					},
+				},
+				nil,
+			),
+		},
+		{
+			Name:   "missing required property",
+			Schema: userJSONSchema,
+			Body:   `{"id":2,"name":"dima"}`,
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#", "missing required property tags"},
+					},
+				},
+				nil,
+			),
+		},
+		{
+			Name:   "combinators",
+			Schema: `{"oneOf":[{"type":"integer"},{"type":"number","multipleOf":0.5}],"not":{"const":3}}`,
+			Body:   `3`,
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#", "value must match exactly one schema from oneOf, matched 2"},
+					},
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#", "value must not match schema from not"},
+					},
+				},
+				nil,
+			),
+		},
+		{
+			Name:   "self reference",
+			Schema: `{"$ref":"#"}`,
+			Body:   `{"id":1}`,
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#", "$ref # cycle, it resolves to itself for the same value"},
+					},
+				},
+				nil,
+			),
+		},
+		{
+			Name:   "reference cycle through allOf",
+			Schema: `{"$defs":{"a":{"allOf":[{"$ref":"#/$defs/b"}]},"b":{"$ref":"#/$defs/a"}},"$ref":"#/$defs/a"}`,
+			Body:   `1`,
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#", "$ref #/$defs/a cycle, it resolves to itself for the same value"},
+					},
+				},
+				nil,
+			),
+		},
+		{
+			Name:   "recursive schema",
+			Schema: `{"type":"object","properties":{"name":{"type":"string"},"children":{"type":"array","items":{"$ref":"#"}}}}`,
+			Body:   `{"name":"root","children":[{"name":"leaf","children":[{"name":1}]}]}`,
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#/children/0/children/0/name", "wrong type, expected string, actual integer"},
+					},
+				},
+				nil,
+			),
+		},
+		{
+			Name:   "invalid pattern",
+			Schema: `{"properties":{"a":{"pattern":"("},"b":{"pattern":"("}}}`,
+			Body:   `{"a":"x","b":"y"}`,
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#/a", "compile pattern (, error parsing regexp: missing closing ): `(`"},
+					},
+					{
+						format: "json schema violation at %s, %s",
+						args:   []any{"#/b", "compile pattern (, error parsing regexp: missing closing ): `(`"},
+					},
+				},
+				nil,
+			),
+		},
+		{
+			Name:   "body is not json",
+			Schema: `{"type":"object"}`,
+			Body:   `Hello World!`,
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "unmarshal body as json, %s, body %s",
+						args: []any{
+							jsonSyntaxError(t, "Hello World!"),
+							"Hello World!",
+						},
+					},
+				},
+				nil,
+			),
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.Name, tst.Test)
+	}
+}
+
+func Test_JSONSchemaBody_Transport(t *testing.T) {
+	runTransportTests(t,
+		&transportTest{
+			Name:         "json schema input body",
+			TestReporter: ExpectSuccessTestReporter,
+			Calls: SequenceCalls(
+				Call{
+					Input: Input{
+						Method: http.MethodPost,
+						Body:   JSONSchemaBody([]byte(userJSONSchema)),
+					},
+					Response: Response{
+						StatusCode: http.StatusCreated,
+					},
+				},
+			),
+			Execute: do(
+				request{
+					method: http.MethodPost,
+					target: "/users",
+					body:   strings.NewReader(`{"id":10,"name":"dima","tags":[]}`),
+				},
+				Response{
+					StatusCode: http.StatusCreated,
+				},
+			),
+		},
+	)
+}
+
+func jsonSyntaxError(t *testing.T, data string) error {
+	var value any
+
+	err := json.Unmarshal([]byte(data), &value)
+	if err == nil {
+		t.Fatalf("expected json syntax error for %s", data)
+	}
+
+	return err
+}
@@ -0,0 +1,155 @@
+package httpmock
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// transferSteps is the count of slices the body is sent in over
+// Call.BodyTransfer.
+const transferSteps = 10
+
+// latencyWriter buffers the response of a server call and sends it when the
+// call is handled: the header after firstByte, the body in slices spread
+// over transfer.
+type latencyWriter struct {
+	http.ResponseWriter
+	ctx       context.Context
+	firstByte time.Duration
+	transfer  time.Duration
+
+	statusCode int
+	body       bytes.Buffer
+}
+
+func newLatencyWriter(w http.ResponseWriter, r *http.Request, call Call) *latencyWriter {
+	return &latencyWriter{
+		ResponseWriter: w,
+		ctx:            r.Context(),
+		firstByte:      call.FirstByte,
+		transfer:       call.BodyTransfer,
+	}
+}
+
+func (w *latencyWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
+
+func (w *latencyWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+
+	return w.body.Write(p)
+}
+
+// Flush is deferred to finish, the body is sent only after the call is
+// handled.
+func (w *latencyWriter) Flush() {}
+
+func (w *latencyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *latencyWriter) finish() {
+	if !sleepContext(w.ctx, w.firstByte) {
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(cmp.Or(w.statusCode, http.StatusOK))
+	w.flush()
+
+	body := w.body.Bytes()
+	if w.transfer <= 0 || len(body) == 0 {
+		_, _ = w.ResponseWriter.Write(body)
+
+		return
+	}
+
+	step := w.transfer / transferSteps
+	size := (len(body) + transferSteps - 1) / transferSteps
+
+	for len(body) > 0 {
+		if !sleepContext(w.ctx, step) {
+			return
+		}
+
+		n := min(size, len(body))
+
+		_, err := w.ResponseWriter.Write(body[:n])
+		if err != nil {
+			return
+		}
+
+		w.flush()
+
+		body = body[n:]
+	}
+}
+
+func (w *latencyWriter) flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// transferBody lets the client read the response body of a transport call
+// no faster than spread evenly over transfer.
+type transferBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	size     int64
+	transfer time.Duration
+
+	once  sync.Once
+	start time.Time
+	read  int64
+}
+
+func newTransferBody(ctx context.Context, body io.ReadCloser, size int64, transfer time.Duration) io.ReadCloser {
+	if transfer <= 0 || size <= 0 {
+		return body
+	}
+
+	return &transferBody{
+		ReadCloser: body,
+		ctx:        ctx,
+		size:       size,
+		transfer:   transfer,
+	}
+}
+
+func (b *transferBody) Read(p []byte) (int, error) {
+	b.once.Do(func() { b.start = time.Now() })
+
+	for b.read < b.size {
+		allowed := b.allowed() - b.read
+		if allowed > 0 {
+			n, err := b.ReadCloser.Read(p[:min(int64(len(p)), allowed)])
+			b.read += int64(n)
+
+			return n, err
+		}
+
+		if !sleepContext(b.ctx, b.transfer/transferSteps) {
+			return 0, b.ctx.Err()
+		}
+	}
+
+	return b.ReadCloser.Read(p)
+}
+
+// allowed is the count of bytes the client may have read by now.
+func (b *transferBody) allowed() int64 {
+	elapsed := time.Since(b.start)
+	if elapsed >= b.transfer {
+		return b.size
+	}
+
+	return b.size * int64(elapsed/(b.transfer/transferSteps)) / transferSteps
+}
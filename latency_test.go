@@ -0,0 +1,96 @@
+package httpmock
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func latencyCall(firstByte, transfer time.Duration) Call {
+	return Call{
+		Input:        Input{Method: http.MethodGet},
+		Response:     Response{Body: RawBody(strings.Repeat("x", 100))},
+		FirstByte:    firstByte,
+		BodyTransfer: transfer,
+	}
+}
+
+func readTimed(t *testing.T, resp *http.Response) ([]byte, time.Duration) {
+	t.Helper()
+
+	defer resp.Body.Close()
+
+	start := time.Now()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return body, time.Since(start)
+}
+
+func Test_Latency_Transport(t *testing.T) {
+	client := &http.Client{
+		Transport: NewTransport(t, SequenceCalls(latencyCall(40*time.Millisecond, 60*time.Millisecond)), nil),
+	}
+
+	start := time.Now()
+
+	resp, err := client.Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("first byte not delayed, elapsed %s", elapsed)
+	}
+
+	body, elapsed := readTimed(t, resp)
+
+	if len(body) != 100 {
+		t.Errorf("wrong body length, expected 100, actual %d", len(body))
+	}
+
+	if elapsed < 60*time.Millisecond {
+		t.Errorf("body transfer not delayed, elapsed %s", elapsed)
+	}
+}
+
+func Test_Latency_ServerFirstByte(t *testing.T) {
+	server := NewServer(t, SequenceCalls(latencyCall(100*time.Millisecond, 0)), nil)
+
+	client := &http.Client{Transport: &http.Transport{ResponseHeaderTimeout: 20 * time.Millisecond}}
+
+	_, err := client.Get(server.URL)
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expect response header timeout, actual %v", err)
+	}
+}
+
+func Test_Latency_ServerBodyTransfer(t *testing.T) {
+	server := NewServer(t, SequenceCalls(latencyCall(0, 100*time.Millisecond)), nil)
+
+	client := &http.Client{Transport: &http.Transport{ResponseHeaderTimeout: 50 * time.Millisecond}}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, elapsed := readTimed(t, resp)
+
+	if len(body) != 100 {
+		t.Errorf("wrong body length, expected 100, actual %d", len(body))
+	}
+
+	if elapsed < 50*time.Millisecond {
+		t.Errorf("body transfer not spread, elapsed %s", elapsed)
+	}
+}
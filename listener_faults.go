@@ -0,0 +1,172 @@
+package httpmock
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// ConnFault is a fault injected into an accepted connection.
+type ConnFault int
+
+const (
+	// ConnServe serves the connection as usual.
+	ConnServe ConnFault = iota
+	// ConnReset closes the connection with RST right after accept.
+	ConnReset
+	// ConnStall keeps the connection open without reading or writing until
+	// the client gives up or the server is closed, like a server hanging in
+	// the handshake.
+	ConnStall
+)
+
+// ListenerFaults wraps the listener of NewServer to exercise dialer
+// timeouts and connection pools of clients.
+type ListenerFaults struct {
+	// AcceptDelay delays every accepted connection before it is served.
+	AcceptDelay time.Duration
+	// Conns returns the fault of the n-th accepted connection starting
+	// from 1, all connections are served when nil.
+	Conns func(n int) ConnFault
+	// MaxConns caps concurrent connections, further connections wait in the
+	// accept backlog until an open one is closed.
+	MaxConns int
+}
+
+// FaultFirst injects the fault into the first count connections.
+func FaultFirst(count int, fault ConnFault) func(n int) ConnFault {
+	return func(n int) ConnFault {
+		if n <= count {
+			return fault
+		}
+
+		return ConnServe
+	}
+}
+
+// WithListenerFaults injects the faults into connections accepted by
+// NewServer.
+func WithListenerFaults(faults ListenerFaults) Option {
+	return func(o *options) {
+		o.listenerFaults = &faults
+	}
+}
+
+type faultyListener struct {
+	net.Listener
+
+	faults ListenerFaults
+	slots  chan struct{}
+	closed chan struct{}
+	once   sync.Once
+
+	mu       sync.Mutex
+	accepted int
+	stalled  []net.Conn
+}
+
+func newFaultyListener(listener net.Listener, faults ListenerFaults) *faultyListener {
+	l := &faultyListener{
+		Listener: listener,
+		faults:   faults,
+		closed:   make(chan struct{}),
+	}
+
+	if faults.MaxConns > 0 {
+		l.slots = make(chan struct{}, faults.MaxConns)
+	}
+
+	return l
+}
+
+func (l *faultyListener) Accept() (net.Conn, error) {
+	for {
+		if l.slots != nil {
+			select {
+			case l.slots <- struct{}{}:
+			case <-l.closed:
+				return nil, net.ErrClosed
+			}
+		}
+
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			l.release()
+
+			return nil, err
+		}
+
+		if l.faults.AcceptDelay > 0 {
+			time.Sleep(l.faults.AcceptDelay)
+		}
+
+		switch l.fault() {
+		case ConnReset:
+			if tcpConn, ok := conn.(*net.TCPConn); ok {
+				_ = tcpConn.SetLinger(0)
+			}
+
+			conn.Close()
+			l.release()
+		case ConnStall:
+			l.mu.Lock()
+			l.stalled = append(l.stalled, conn)
+			l.mu.Unlock()
+
+			l.release()
+		default:
+			return &releasingConn{Conn: conn, release: l.release}, nil
+		}
+	}
+}
+
+func (l *faultyListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+
+	l.mu.Lock()
+
+	for _, conn := range l.stalled {
+		conn.Close()
+	}
+
+	l.stalled = nil
+
+	l.mu.Unlock()
+
+	return l.Listener.Close()
+}
+
+func (l *faultyListener) fault() ConnFault {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.accepted++
+
+	if l.faults.Conns == nil {
+		return ConnServe
+	}
+
+	return l.faults.Conns(l.accepted)
+}
+
+func (l *faultyListener) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// releasingConn frees the connection slot once closed.
+type releasingConn struct {
+	net.Conn
+
+	once    sync.Once
+	release func()
+}
+
+func (c *releasingConn) Close() error {
+	err := c.Conn.Close()
+
+	c.once.Do(c.release)
+
+	return err
+}
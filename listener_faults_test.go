@@ -0,0 +1,135 @@
+package httpmock
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func faultsServer(t *testing.T, faults ListenerFaults, calls ...Call) *Server {
+	return NewServer(t, StaticCalls(calls...), nil, WithListenerFaults(faults))
+}
+
+func Test_ListenerFaults_Reset(t *testing.T) {
+	server := faultsServer(t,
+		ListenerFaults{Conns: FaultFirst(1, ConnReset)},
+		Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("ok")}},
+	)
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	_, err := client.Get(server.URL)
+	if err == nil {
+		t.Fatal("expect error on reset connection")
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("second connection, unexpected error: %s", err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_ListenerFaults_Stall(t *testing.T) {
+	server := faultsServer(t,
+		ListenerFaults{Conns: FaultFirst(1, ConnStall)},
+		Call{Input: Input{Method: http.MethodGet}},
+	)
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DisableKeepAlives:     true,
+			ResponseHeaderTimeout: 50 * time.Millisecond,
+		},
+	}
+
+	_, err := client.Get(server.URL)
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expect timeout on stalled connection, actual %v", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("second connection, unexpected error: %s", err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_ListenerFaults_AcceptDelay(t *testing.T) {
+	server := faultsServer(t,
+		ListenerFaults{AcceptDelay: 30 * time.Millisecond},
+		Call{Input: Input{Method: http.MethodGet}},
+	)
+
+	start := time.Now()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("accept not delayed, elapsed %s", elapsed)
+	}
+}
+
+func Test_ListenerFaults_MaxConns(t *testing.T) {
+	var (
+		active    atomic.Int64
+		maxActive atomic.Int64
+	)
+
+	server := NewServer(t,
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}}),
+		func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+			current := active.Add(1)
+			defer active.Add(-1)
+
+			for {
+				observed := maxActive.Load()
+				if current <= observed || maxActive.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+
+			time.Sleep(20 * time.Millisecond)
+
+			HandleCallCompareInput(t, w, r, call)
+		},
+		WithListenerFaults(ListenerFaults{MaxConns: 1}),
+	)
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	done := make(chan error, 3)
+
+	for range 3 {
+		go func() {
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			done <- err
+		}()
+	}
+
+	for range 3 {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if maxActive.Load() != 1 {
+		t.Errorf("wrong max concurrent connections, expected 1, actual %d", maxActive.Load())
+	}
+}
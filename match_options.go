@@ -0,0 +1,115 @@
+package httpmock
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// matchOptions relax CompareInput for a transport, they reach it through
+// the request context.
+type matchOptions struct {
+	queryArrays         bool
+	caseInsensitivePath bool
+	trailingSlash       bool
+	rawQuery            bool
+}
+
+type matchOptionsContextKey struct{}
+
+// WithQueryArrays treats a=1&a=2, a[]=1&a[]=2 and a=1,2 as equal query
+// values, keys lose the [] suffix and values are split by commas on both
+// sides of the comparison.
+func WithQueryArrays() Option {
+	return func(o *options) {
+		o.match.queryArrays = true
+	}
+}
+
+// WithCaseInsensitivePath compares url paths ignoring case.
+func WithCaseInsensitivePath() Option {
+	return func(o *options) {
+		o.match.caseInsensitivePath = true
+	}
+}
+
+// WithTrailingSlash treats /users and /users/ as equal url paths.
+func WithTrailingSlash() Option {
+	return func(o *options) {
+		o.match.trailingSlash = true
+	}
+}
+
+// WithRawQuery compares url.RawQuery of the request with the expected one
+// byte for byte instead of decoded query values, for APIs signing the query
+// in its exact order and encoding. Input.QueryMatch is compared as usual.
+func WithRawQuery() Option {
+	return func(o *options) {
+		o.match.rawQuery = true
+	}
+}
+
+func (m matchOptions) attach(r *http.Request) *http.Request {
+	if m == (matchOptions{}) {
+		return r
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), matchOptionsContextKey{}, m))
+}
+
+func matchOptionsFrom(ctx context.Context) matchOptions {
+	m, _ := ctx.Value(matchOptionsContextKey{}).(matchOptions)
+
+	return m
+}
+
+func (m matchOptions) query(query url.Values) url.Values {
+	if !m.queryArrays {
+		return query
+	}
+
+	return normalizeQueryArrays(query)
+}
+
+// comparePath matches the request path against the expected path or path
+// glob, the reason describes a glob mismatch.
+func (m matchOptions) comparePath(requestPath, inputPath string) (string, bool) {
+	if m.trailingSlash {
+		requestPath = trimTrailingSlash(requestPath)
+		inputPath = trimTrailingSlash(inputPath)
+	}
+
+	if m.caseInsensitivePath {
+		requestPath = strings.ToLower(requestPath)
+		inputPath = strings.ToLower(inputPath)
+	}
+
+	if isPathGlob(inputPath) {
+		return matchPathGlob(inputPath, requestPath)
+	}
+
+	return "", requestPath == inputPath
+}
+
+func trimTrailingSlash(path string) string {
+	if len(path) > 1 {
+		return strings.TrimSuffix(path, "/")
+	}
+
+	return path
+}
+
+func normalizeQueryArrays(query url.Values) url.Values {
+	normalized := make(url.Values, len(query))
+
+	for key, values := range query {
+		key = strings.TrimSuffix(key, "[]")
+
+		for _, value := range values {
+			normalized[key] = append(normalized[key], strings.Split(value, ",")...)
+		}
+	}
+
+	return normalized
+}
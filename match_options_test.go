@@ -0,0 +1,140 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_WithQueryArrays(t *testing.T) {
+	call := Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("http://localhost/items?id=1&id=2")}}
+
+	t.Run("equivalent encodings", func(t *testing.T) {
+		transport := NewTransport(t, StaticCalls(call), nil, WithQueryArrays())
+
+		client := &http.Client{Transport: transport}
+
+		for _, target := range []string{
+			"http://localhost/items?id=1&id=2",
+			"http://localhost/items?id[]=1&id[]=2",
+			"http://localhost/items?id%5B%5D=1&id%5B%5D=2",
+			"http://localhost/items?id=1,2",
+			"http://localhost/items?id=1%2C2",
+		} {
+			resp, err := client.Get(target)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp.Body.Close()
+		}
+	})
+
+	t.Run("without option", func(t *testing.T) {
+		transport := NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "1 call, wrong url query values by key %s, expect [%s], actual [%s]", args: []any{"id", "1,2", ""}},
+				},
+				nil,
+			)(t),
+			SequenceCalls(call),
+			nil,
+		)
+
+		resp, err := (&http.Client{Transport: transport}).Get("http://localhost/items?id[]=1&id[]=2")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+}
+
+func Test_WithCaseInsensitivePath(t *testing.T) {
+	transport := NewTransport(t,
+		StaticCalls(Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("http://localhost/Users/42")}}),
+		nil,
+		WithCaseInsensitivePath(),
+	)
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://localhost/users/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_WithTrailingSlash(t *testing.T) {
+	t.Run("equal", func(t *testing.T) {
+		transport := NewTransport(t,
+			StaticCalls(
+				Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("http://localhost/users")}},
+			),
+			nil,
+			WithTrailingSlash(),
+		)
+
+		client := &http.Client{Transport: transport}
+
+		for _, target := range []string{"http://localhost/users", "http://localhost/users/"} {
+			resp, err := client.Get(target)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp.Body.Close()
+		}
+	})
+
+	t.Run("root is kept", func(t *testing.T) {
+		transport := NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "1 call, wrong url.Path, expected %s, actual %s", args: []any{"/", "/users/"}},
+				},
+				nil,
+			)(t),
+			SequenceCalls(Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("http://localhost/")}}),
+			nil,
+			WithTrailingSlash(),
+		)
+
+		resp, err := (&http.Client{Transport: transport}).Get("http://localhost/users/")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+}
+
+func Test_WithRawQuery(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "2 call, wrong url.RawQuery, expected %s, actual %s", args: []any{"b=2&a=x%20y", "a=x%20y&b=2"}},
+				{format: "3 call, wrong url.RawQuery, expected %s, actual %s", args: []any{"b=2&a=x%20y", "b=2&a=x+y"}},
+			},
+			nil,
+		)(t),
+		StaticCalls(Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("http://localhost/items?b=2&a=x%20y")}}),
+		nil,
+		WithRawQuery(),
+	)
+
+	client := &http.Client{Transport: transport}
+
+	for _, target := range []string{
+		"http://localhost/items?b=2&a=x%20y",
+		"http://localhost/items?a=x%20y&b=2",
+		"http://localhost/items?b=2&a=x+y",
+	} {
+		resp, err := client.Get(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,22 @@
+package httpmock
+
+import "strings"
+
+// AnyMethod as Input.Method matches every request method.
+const AnyMethod = "*"
+
+// methodMatch matches the request method against the expected method, a set
+// of methods separated by | or AnyMethod.
+func methodMatch(requestMethod, inputMethod string) bool {
+	if inputMethod == AnyMethod || requestMethod == inputMethod {
+		return true
+	}
+
+	for _, method := range strings.Split(inputMethod, "|") {
+		if method == requestMethod {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_CompareMethod_Set(t *testing.T) {
+	CompareMethod(t, http.MethodHead, "GET|HEAD")
+	CompareMethod(t, http.MethodGet, "GET|HEAD")
+	CompareMethod(t, http.MethodDelete, AnyMethod)
+
+	CompareMethod(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "wrong r.Method, expected %s, actual %s", args: []any{"GET|HEAD", http.MethodPost}},
+			},
+			nil,
+		)(t),
+		http.MethodPost,
+		"GET|HEAD",
+	)
+}
+
+func Test_ParseWireMockMappings_AnyMethod(t *testing.T) {
+	calls, err := ParseWireMockMappings([]byte(`{"request": {"method": "ANY", "url": "/health"}, "response": {"status": 204}}`))
+	if err != nil {
+		t.Fatalf("parse wiremock mapping, unexpected error: %s", err)
+	}
+
+	if calls[0].Input.Method != AnyMethod {
+		t.Fatalf("wrong method, expected %s, actual %s", AnyMethod, calls[0].Input.Method)
+	}
+
+	transport := NewTransport(t, StaticCalls(calls...), nil)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		req, err := http.NewRequest(method, "http://localhost/health", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		CompareStatusCode(t, resp.StatusCode, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,228 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// MutationExpect is how a client must handle a mutated response.
+type MutationExpect int
+
+const (
+	// MutationNoPanic accepts any result but a panic, the client may fail
+	// on the response or ignore the change.
+	MutationNoPanic MutationExpect = iota
+	// MutationSuccess requires the client to handle the response, e.g. one
+	// with unknown fields added.
+	MutationSuccess
+	// MutationError requires the client to return an error, e.g. on a
+	// flipped status class.
+	MutationError
+)
+
+// Mutation is a perturbed response, see Mutations.
+type Mutation struct {
+	Name     string
+	Response Response
+	Expect   MutationExpect
+}
+
+// Mutations perturbs the template systematically: every JSON field is
+// dropped and retyped, the first element stands for arrays, unknown fields
+// are added to every object and the status code is flipped to 4xx and 5xx.
+func Mutations(template Response) ([]Mutation, error) {
+	statusCode := template.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	var mutations []Mutation
+
+	if statusCode < 400 {
+		for _, flipped := range []int{http.StatusBadRequest, http.StatusInternalServerError} {
+			response := template
+			response.StatusCode = flipped
+
+			mutations = append(mutations, Mutation{
+				Name:     "status " + strconv.Itoa(flipped),
+				Response: response,
+				Expect:   MutationError,
+			})
+		}
+	}
+
+	if template.Body == nil {
+		return mutations, nil
+	}
+
+	body, err := template.Body.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("mutate response body, %w", err)
+	}
+
+	var value any
+
+	if json.Unmarshal(body, &value) != nil {
+		return mutations, nil
+	}
+
+	for _, jsonMutation := range mutateJSON(value, "") {
+		data, err := json.Marshal(jsonMutation.value)
+		if err != nil {
+			return nil, fmt.Errorf("mutation %s, %w", jsonMutation.name, err)
+		}
+
+		response := template
+		response.Body = RawBody(data)
+
+		mutations = append(mutations, Mutation{
+			Name:     jsonMutation.name,
+			Response: response,
+			Expect:   jsonMutation.expect,
+		})
+	}
+
+	return mutations, nil
+}
+
+type jsonMutation struct {
+	name   string
+	value  any
+	expect MutationExpect
+}
+
+// mutateJSON returns copies of value with a single change each.
+func mutateJSON(value any, path string) []jsonMutation {
+	var mutations []jsonMutation
+
+	switch value := value.(type) {
+	case map[string]any:
+		added := make(map[string]any, len(value)+1)
+		for key, field := range value {
+			added[key] = field
+		}
+
+		added["httpmock_unknown_field"] = "unknown"
+
+		mutations = append(mutations, jsonMutation{name: "add unknown field to " + jsonPathName(path), value: added, expect: MutationSuccess})
+
+		for _, key := range sortedKeys(value) {
+			fieldPath := path + "." + key
+
+			dropped := make(map[string]any, len(value))
+			for other, field := range value {
+				if other != key {
+					dropped[other] = field
+				}
+			}
+
+			mutations = append(mutations, jsonMutation{name: "drop " + fieldPath, value: dropped})
+
+			for _, nested := range mutateJSON(value[key], fieldPath) {
+				replaced := make(map[string]any, len(value))
+				for other, field := range value {
+					replaced[other] = field
+				}
+
+				replaced[key] = nested.value
+				nested.value = replaced
+
+				mutations = append(mutations, nested)
+			}
+		}
+	case []any:
+		if len(value) == 0 {
+			break
+		}
+
+		for _, nested := range mutateJSON(value[0], path+"[0]") {
+			replaced := append([]any{nested.value}, value[1:]...)
+			nested.value = replaced
+
+			mutations = append(mutations, nested)
+		}
+	}
+
+	if path != "" {
+		mutations = append(mutations, jsonMutation{name: "retype " + path, value: retypeJSON(value)})
+	}
+
+	return mutations
+}
+
+func retypeJSON(value any) any {
+	switch value.(type) {
+	case string:
+		return 1
+	case float64:
+		return "1"
+	case bool:
+		return "true"
+	case nil:
+		return []any{}
+	case []any:
+		return map[string]any{}
+	default:
+		return []any{}
+	}
+}
+
+func jsonPathName(path string) string {
+	if path == "" {
+		return "root"
+	}
+
+	return path
+}
+
+// CheckMutations serves every mutation of the template to do and reports
+// the mutations the client did not handle as expected, see MutationExpect.
+// do returns the error the client returned for the response.
+func CheckMutations(t TestReporter, template Response, do func(client *http.Client) error) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	mutations, err := Mutations(template)
+	if err != nil {
+		t.Fatalf("check mutations, %s", err)
+
+		return
+	}
+
+	for _, mutation := range mutations {
+		checkMutation(t, mutation, do)
+	}
+}
+
+func checkMutation(t TestReporter, mutation Mutation, do func(client *http.Client) error) {
+	transport := NewTransport(t, StaticCalls(Call{Response: mutation.Response}), func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		err := WriteResponse(w, call.Response)
+		if err != nil {
+			t.Errorf(err.Error())
+		}
+	})
+
+	var err error
+
+	panicked := func() (recovered any) {
+		defer func() {
+			recovered = recover()
+		}()
+
+		err = do(&http.Client{Transport: transport})
+
+		return nil
+	}()
+
+	switch {
+	case panicked != nil:
+		t.Errorf("mutation %s, client panicked, %v", mutation.Name, panicked)
+	case mutation.Expect == MutationSuccess && err != nil:
+		t.Errorf("mutation %s, client failed on a compatible response, %s", mutation.Name, err)
+	case mutation.Expect == MutationError && err == nil:
+		t.Errorf("mutation %s, client accepted the response", mutation.Name)
+	}
+}
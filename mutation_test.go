@@ -0,0 +1,92 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+var mutationTemplate = Response{Body: RawBody(`{"id":1,"name":"apple","tags":["red"]}`)}
+
+func Test_Mutations(t *testing.T) {
+	mutations, err := Mutations(mutationTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, len(mutations))
+	for i, mutation := range mutations {
+		names[i] = mutation.Name
+	}
+
+	expected := []string{
+		"status 400",
+		"status 500",
+		"add unknown field to root",
+		"drop .id",
+		"retype .id",
+		"drop .name",
+		"retype .name",
+		"drop .tags",
+		"retype .tags[0]",
+		"retype .tags",
+	}
+
+	if !slices.Equal(names, expected) {
+		t.Fatalf("wrong mutations,\nexpected %v,\nactual %v", expected, names)
+	}
+
+	body, _ := mutations[4].Response.Body.Bytes()
+	if string(body) != `{"id":"1","name":"apple","tags":["red"]}` {
+		t.Errorf("wrong retype .id body, %s", body)
+	}
+}
+
+func Test_CheckMutations(t *testing.T) {
+	reporter := &testReporterMock{t: t}
+
+	CheckMutations(reporter, mutationTemplate, func(client *http.Client) error {
+		resp, err := client.Get("http://localhost/items/1")
+		if err != nil {
+			return err
+		}
+
+		defer resp.Body.Close()
+
+		var item map[string]any
+
+		err = json.NewDecoder(resp.Body).Decode(&item)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := item["httpmock_unknown_field"]; ok {
+			return errors.New("unknown field")
+		}
+
+		_ = item["name"].(string)
+
+		return nil
+	})
+
+	var failures []string
+
+	for _, call := range reporter.errorfCalls {
+		failures = append(failures, fmt.Sprintf(call.format, call.args...))
+	}
+
+	expected := []string{
+		"mutation status 400, client accepted the response",
+		"mutation status 500, client accepted the response",
+		"mutation add unknown field to root, client failed on a compatible response, unknown field",
+		"mutation drop .name, client panicked, interface conversion: interface {} is nil, not string",
+		"mutation retype .name, client panicked, interface conversion: interface {} is float64, not string",
+	}
+
+	if !slices.Equal(failures, expected) {
+		t.Errorf("wrong failures,\nexpected %v,\nactual %v", expected, failures)
+	}
+}
@@ -0,0 +1,21 @@
+package httpmock
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// StatusNoCallsLeft is the status code of the response RoundTrip returns
+// when no expected calls are left, so clients get a well-formed response
+// instead of a failure masked by a nil body. Servers answer such requests
+// with 501 Not Implemented. Both responses describe the request in a plain
+// text body.
+const StatusNoCallsLeft = http.StatusTeapot
+
+func writeNoCallsLeft(w http.ResponseWriter, statusCode int, calledTimes int64, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(statusCode)
+
+	_, _ = fmt.Fprintf(w, "httpmock: no expected calls left, call %d, %s %s\n", calledTimes, r.Method, r.URL)
+}
@@ -0,0 +1,63 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func Test_NoCallsLeft_Response(t *testing.T) {
+	client := &http.Client{
+		Transport: NewTransport(
+			ExpectFailureTestReporter(nil, []testReporterCall{{format: "no expected calls left"}})(t),
+			SequenceCalls(),
+			nil,
+		),
+	}
+
+	resp, err := client.Get("http://localhost/items?id=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != StatusNoCallsLeft {
+		t.Errorf("wrong status code, expected %d, actual %d", StatusNoCallsLeft, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expected = "httpmock: no expected calls left, call 1, GET http://localhost/items?id=1\n"
+	if string(body) != expected {
+		t.Errorf("wrong body, expected %q, actual %q", expected, body)
+	}
+}
+
+func Test_NoCallsLeft_ServerResponse(t *testing.T) {
+	server := NewServer(
+		ExpectFailureTestReporter([]testReporterCall{{format: "1 call, no expected calls left"}}, nil)(t),
+		SequenceCalls(),
+		nil,
+	)
+
+	resp, err := http.Get(server.URL + "/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expected = "httpmock: no expected calls left, call 1, GET /items\n"
+	if resp.StatusCode != http.StatusNotImplemented || string(body) != expected {
+		t.Errorf("wrong response, expected 501 %q, actual %d %q", expected, resp.StatusCode, body)
+	}
+}
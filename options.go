@@ -0,0 +1,66 @@
+package httpmock
+
+import (
+	"crypto/tls"
+	"net"
+	"regexp"
+	"time"
+)
+
+// Option configures transports and servers.
+type Option func(*options)
+
+type options struct {
+	sessions         *SessionStore
+	listener         net.Listener
+	addr             string
+	listenerFaults   *ListenerFaults
+	concurrency      *Concurrency
+	timeline         *Timeline
+	timings          *Timings
+	maxBodySize      int64
+	shardedCalls     bool
+	bodyCloseCheck   bool
+	requestBodyCheck bool
+	jsonBodyCheck    bool
+	cookieJar        bool
+	clientTimeout    time.Duration
+	transcript       bool
+	jsonReport       string
+	match            matchOptions
+	clock            func() time.Time
+	generator        *Generator
+	history          RecordSink
+	tlsConfig        *tls.Config
+	userAgent        *regexp.Regexp
+	headerKeysCheck  bool
+	verifiers        []RequestVerifier
+	http10           bool
+	callErrors       bool
+	delayPlacement   DelayPlacement
+	gracePeriod      time.Duration
+}
+
+// WithSessions attaches a session from the store to every request, see
+// SessionStore.
+func WithSessions(store *SessionStore) Option {
+	return func(o *options) {
+		o.sessions = store
+	}
+}
+
+// WithListener makes NewServer serve on the listener instead of a random
+// loopback port, the listener is closed with the server.
+func WithListener(listener net.Listener) Option {
+	return func(o *options) {
+		o.listener = listener
+	}
+}
+
+// WithAddr makes NewServer listen on the tcp address, e.g. 127.0.0.1:18080,
+// for code with hardcoded base urls.
+func WithAddr(addr string) Option {
+	return func(o *options) {
+		o.addr = addr
+	}
+}
@@ -0,0 +1,81 @@
+package httpmock
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// OversizedBody is a response body of filler bytes generated while it is
+// written, so clients can be tested against bodies larger than they accept
+// without holding them in memory, see ServeOversizedBody.
+type OversizedBody struct {
+	// Size is the count of body bytes, decompressed bytes for Gzip.
+	Size int64
+	// ContentLength is the declared length: zero declares the actual length
+	// of the sent body, -1 declares none and sends the body chunked, other
+	// values lie. A larger length ends the body early for the client,
+	// a smaller one cuts the body at that length.
+	ContentLength int64
+	// Gzip sends the body gzip compressed with Content-Encoding: gzip, a
+	// compression bomb, a few kilobytes on the wire per megabyte of Size.
+	// The compressed length is not known in advance, so zero ContentLength
+	// sends the body chunked.
+	Gzip bool
+	// Filler is the repeated byte, zero by default.
+	Filler byte
+}
+
+// ServeOversizedBody compares the input and writes the oversized body with
+// status code and headers of call.Response. The body is written until the
+// client stops reading, so the server sees no error from a client limiting
+// the body size. NewTransport buffers the body, bodies larger than memory
+// need NewServer.
+func ServeOversizedBody(body OversizedBody) HandleCall {
+	return func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		CompareInput(t, r, call.Input)
+
+		contentLength := body.ContentLength
+		if contentLength == 0 && !body.Gzip {
+			contentLength = body.Size
+		}
+
+		if contentLength > 0 {
+			w.Header().Set("Content-Length", strconv.FormatInt(contentLength, 10))
+		}
+
+		if body.Gzip {
+			w.Header().Set("Content-Encoding", "gzip")
+		}
+
+		WriteHeader(w, call.Response.Header, call.Response.StatusCode)
+
+		filler := io.LimitReader(fillerReader(body.Filler), body.Size)
+
+		if !body.Gzip {
+			_, _ = io.Copy(w, filler)
+
+			return
+		}
+
+		gz, _ := gzip.NewWriterLevel(w, gzip.BestCompression)
+
+		_, err := io.Copy(gz, filler)
+		if err != nil {
+			return
+		}
+
+		_ = gz.Close()
+	}
+}
+
+type fillerReader byte
+
+func (f fillerReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(f)
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,88 @@
+package httpmock
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func oversizedServer(t *testing.T, body OversizedBody) *Server {
+	return NewServer(t, StaticCalls(Call{Input: Input{Method: http.MethodGet}}), ServeOversizedBody(body))
+}
+
+func Test_ServeOversizedBody(t *testing.T) {
+	const size = 64 << 20
+
+	server := oversizedServer(t, OversizedBody{Size: size, Filler: 'a'})
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.ContentLength != size {
+		t.Errorf("wrong content length, expected %d, actual %d", size, resp.ContentLength)
+	}
+
+	head := make([]byte, 4)
+
+	_, err = io.ReadFull(resp.Body, head)
+	if err != nil || string(head) != "aaaa" {
+		t.Errorf("wrong body head, expected aaaa, actual %q, %v", head, err)
+	}
+}
+
+func Test_ServeOversizedBody_LyingContentLength(t *testing.T) {
+	server := oversizedServer(t, OversizedBody{Size: 1024, ContentLength: 4096})
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expect unexpected EOF, actual %v", err)
+	}
+}
+
+func Test_ServeOversizedBody_Gzip(t *testing.T) {
+	const size = 16 << 20
+
+	server := oversizedServer(t, OversizedBody{Size: size, Gzip: true})
+
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	counter := &countingBody{ReadCloser: resp.Body}
+
+	gz, err := gzip.NewReader(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decompressed, err := io.Copy(io.Discard, gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decompressed != size {
+		t.Errorf("wrong decompressed size, expected %d, actual %d", size, decompressed)
+	}
+
+	if counter.read*100 > size {
+		t.Errorf("body is not a bomb, %d bytes on the wire for %d decompressed", counter.read, size)
+	}
+}
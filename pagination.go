@@ -0,0 +1,80 @@
+package httpmock
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Paginate splits items into pages of perPage items served as JSON by GET
+// calls of path in page order, page 1 is requested without query, later
+// pages with ?page=N. Every response has an RFC 8288 Link header with next,
+// prev, first and last links to the mock's own url, like GitHub pagination.
+//
+//	transport := httpmock.NewTransport(t, httpmock.SequenceCalls(httpmock.Paginate("/repos", repos, 30)...), nil)
+func Paginate[T any](path string, items []T, perPage int) []Call {
+	if perPage <= 0 {
+		perPage = len(items)
+	}
+
+	pages := 1
+	if perPage > 0 && len(items) > perPage {
+		pages = (len(items) + perPage - 1) / perPage
+	}
+
+	calls := make([]Call, 0, pages)
+
+	for page := 1; page <= pages; page++ {
+		start := min((page-1)*perPage, len(items))
+		end := min(start+perPage, len(items))
+
+		pageItems := items[start:end]
+		if pageItems == nil {
+			pageItems = []T{}
+		}
+
+		u := &url.URL{Path: path}
+		if page > 1 {
+			u.RawQuery = "page=" + strconv.Itoa(page)
+		}
+
+		header := http.Header{"Content-Type": {"application/json"}}
+
+		calls = append(calls, Call{
+			Input: Input{
+				Method: http.MethodGet,
+				URL:    u,
+			},
+			Response: Response{
+				StatusCode:     http.StatusOK,
+				Header:         header,
+				Body:           JSONBody(pageItems),
+				TemplateHeader: http.Header{"Link": {paginationLinks(path, page, pages, perPage)}},
+			},
+		})
+	}
+
+	return calls
+}
+
+func paginationLinks(path string, page, pages, perPage int) string {
+	link := func(target int, rel string) string {
+		return fmt.Sprintf(`<{{request.baseURL}}%s?page=%d&per_page=%d>; rel="%s"`, path, target, perPage, rel)
+	}
+
+	var links []string
+
+	if page < pages {
+		links = append(links, link(page+1, "next"))
+	}
+
+	if page > 1 {
+		links = append(links, link(page-1, "prev"))
+	}
+
+	links = append(links, link(1, "first"), link(pages, "last"))
+
+	return strings.Join(links, ", ")
+}
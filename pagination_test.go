@@ -0,0 +1,79 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>; rel="next"`)
+
+func Test_Paginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	server := NewServer(t, SequenceCalls(Paginate("/items", items, 2)...), nil)
+
+	var (
+		collected []int
+		links     []string
+	)
+
+	target := server.URL + "/items"
+
+	for target != "" {
+		resp, err := server.Client().Get(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var page []int
+
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+
+		if err != nil {
+			t.Fatalf("decode page, %s", err)
+		}
+
+		collected = append(collected, page...)
+		links = append(links, resp.Header.Get("Link"))
+
+		target = ""
+		if match := nextLinkPattern.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			target = match[1]
+		}
+	}
+
+	if !slices.Equal(collected, items) {
+		t.Errorf("wrong collected items, expected %v, actual %v", items, collected)
+	}
+
+	expectedLast := `<` + server.URL + `/items?page=2&per_page=2>; rel="prev", ` +
+		`<` + server.URL + `/items?page=1&per_page=2>; rel="first", ` +
+		`<` + server.URL + `/items?page=3&per_page=2>; rel="last"`
+
+	if len(links) != 3 || links[2] != expectedLast {
+		t.Errorf("wrong last page link, expected %s, actual %v", expectedLast, links)
+	}
+}
+
+func Test_Paginate_Empty(t *testing.T) {
+	calls := Paginate[string]("/items", nil, 10)
+
+	transport := NewTransport(t, SequenceCalls(calls...), nil)
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	expectedLink := `<http://localhost/items?page=1&per_page=10>; rel="first", <http://localhost/items?page=1&per_page=10>; rel="last"`
+
+	if resp.Header.Get("Link") != expectedLink {
+		t.Errorf("wrong link, expected %s, actual %s", expectedLink, resp.Header.Get("Link"))
+	}
+}
@@ -0,0 +1,199 @@
+package httpmock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// isPathGlob reports whether the expected url path is a glob: * matches
+// one segment or a part of it, ** matches any number of segments and
+// {name} matches one segment captured as a path variable,
+// e.g. /v1/*/items/** or /users/{id}.
+func isPathGlob(path string) bool {
+	return strings.ContainsAny(path, "*{")
+}
+
+func isPathVar(pattern string) bool {
+	return len(pattern) > 2 && pattern[0] == '{' && pattern[len(pattern)-1] == '}'
+}
+
+type pathVarsContextKey struct{}
+
+// withPathVars captures path variables of the expected url path, templates
+// read them as {{request.pathVars.name}}. The path is matched with the
+// transport match options like the comparison, captured values keep their
+// case.
+func withPathVars(r *http.Request, inputURL *url.URL) *http.Request {
+	if inputURL == nil || !strings.Contains(inputURL.Path, "{") {
+		return r
+	}
+
+	match := matchOptionsFrom(r.Context())
+
+	inputPath, requestPath := inputURL.Path, r.URL.Path
+
+	if match.trailingSlash {
+		inputPath = trimTrailingSlash(inputPath)
+		requestPath = trimTrailingSlash(requestPath)
+	}
+
+	vars := make(map[string]string)
+
+	if !matchGlobSegments(splitPath(inputPath), splitPath(requestPath), 0, 0, match.caseInsensitivePath, &globMismatches{}, vars) {
+		return r
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), pathVarsContextKey{}, vars))
+}
+
+func pathVars(r *http.Request) map[string]string {
+	vars, _ := r.Context().Value(pathVarsContextKey{}).(map[string]string)
+
+	return vars
+}
+
+type globMismatch struct {
+	pattern int
+	segment int
+	set     bool
+}
+
+// globMismatches keeps the furthest segment mismatch and the furthest
+// missing segment, a mismatch describes the failure better.
+type globMismatches struct {
+	mismatch globMismatch
+	missing  globMismatch
+}
+
+func (g *globMismatches) record(pi, si int, segments []string) {
+	current := &g.mismatch
+	if si >= len(segments) {
+		current = &g.missing
+	}
+
+	if !current.set || si > current.segment || (si == current.segment && pi > current.pattern) {
+		*current = globMismatch{pattern: pi, segment: si, set: true}
+	}
+}
+
+// matchPathGlob matches the path against the glob, on mismatch it describes
+// the furthest segment the path could not pass.
+func matchPathGlob(glob, path string) (string, bool) {
+	patterns := splitPath(glob)
+	segments := splitPath(path)
+
+	var mismatches globMismatches
+
+	if matchGlobSegments(patterns, segments, 0, 0, false, &mismatches, nil) {
+		return "", true
+	}
+
+	furthest := mismatches.mismatch
+	if !furthest.set {
+		furthest = mismatches.missing
+	}
+
+	switch {
+	case furthest.segment >= len(segments):
+		return fmt.Sprintf("segment %d missing, expected %s", furthest.segment+1, patterns[furthest.pattern]), false
+	case furthest.pattern >= len(patterns):
+		return fmt.Sprintf("unexpected segment %d %s", furthest.segment+1, segments[furthest.segment]), false
+	default:
+		return fmt.Sprintf(
+			"segment %d %s does not match %s",
+			furthest.segment+1,
+			segments[furthest.segment],
+			patterns[furthest.pattern],
+		), false
+	}
+}
+
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+// matchGlobSegments matches segments from si against patterns from pi,
+// fold ignores the case of segments which are not path variables, path
+// variables are captured into vars when it is not nil.
+func matchGlobSegments(patterns, segments []string, pi, si int, fold bool, mismatches *globMismatches, vars map[string]string) bool {
+	for pi < len(patterns) {
+		if patterns[pi] == "**" {
+			for next := si; next <= len(segments); next++ {
+				if matchGlobSegments(patterns, segments, pi+1, next, fold, mismatches, vars) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if si >= len(segments) || !matchSegment(patterns[pi], segments[si], fold, vars) {
+			break
+		}
+
+		pi++
+		si++
+	}
+
+	if pi == len(patterns) && si == len(segments) {
+		return true
+	}
+
+	mismatches.record(pi, si, segments)
+
+	return false
+}
+
+func matchSegment(pattern, segment string, fold bool, vars map[string]string) bool {
+	if !isPathVar(pattern) {
+		if fold {
+			return matchWildcard(strings.ToLower(pattern), strings.ToLower(segment))
+		}
+
+		return matchWildcard(pattern, segment)
+	}
+
+	if segment == "" {
+		return false
+	}
+
+	if vars != nil {
+		vars[pattern[1:len(pattern)-1]] = segment
+	}
+
+	return true
+}
+
+// matchWildcard matches a segment against a pattern where * matches any
+// run of characters.
+func matchWildcard(pattern, segment string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == segment
+	}
+
+	if !strings.HasPrefix(segment, parts[0]) {
+		return false
+	}
+
+	segment = segment[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(segment, part)
+		if index < 0 {
+			return false
+		}
+
+		segment = segment[index+len(part):]
+	}
+
+	return strings.HasSuffix(segment, parts[len(parts)-1])
+}
@@ -0,0 +1,100 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func Test_matchPathGlob(t *testing.T) {
+	tests := []struct {
+		glob, path string
+		reason     string
+		ok         bool
+	}{
+		{glob: "/v1/*/items", path: "/v1/42/items", ok: true},
+		{glob: "/v1/*/items/**", path: "/v1/42/items", ok: true},
+		{glob: "/v1/*/items/**", path: "/v1/42/items/7/tags", ok: true},
+		{glob: "/**/tags", path: "/v1/42/items/7/tags", ok: true},
+		{glob: "/files/report-*.csv", path: "/files/report-2024.csv", ok: true},
+		{glob: "/v1/*/items/**", path: "/v1/42/users/7", reason: "segment 3 users does not match items"},
+		{glob: "/v1/*/items", path: "/v1/42", reason: "segment 3 missing, expected items"},
+		{glob: "/v1/*", path: "/v1/42/items", reason: "unexpected segment 3 items"},
+		{glob: "/files/report-*.csv", path: "/files/report-2024.json", reason: "segment 2 report-2024.json does not match report-*.csv"},
+		{glob: "/**/tags", path: "/v1/42/labels", reason: "segment 3 labels does not match tags"},
+	}
+
+	for _, test := range tests {
+		reason, ok := matchPathGlob(test.glob, test.path)
+		if ok != test.ok || reason != test.reason {
+			t.Errorf("match %s against %s, expected %t %q, actual %t %q", test.path, test.glob, test.ok, test.reason, ok, reason)
+		}
+	}
+}
+
+func Test_CompareURL_Glob(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{
+					format: "2 call, wrong url.Path, expected %s, actual %s, %s",
+					args:   []any{"/v1/*/items/**", "/v1/42/users", "segment 3 users does not match items"},
+				},
+			},
+			nil,
+		)(t),
+		StaticCalls(Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("http://localhost/v1/*/items/**")}}),
+		nil,
+	)
+
+	client := &http.Client{Transport: transport}
+
+	for _, target := range []string{"http://localhost/v1/42/items/7", "http://localhost/v1/42/users"} {
+		resp, err := client.Get(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+}
+
+func Test_PathVars_MatchOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		option Option
+		path   string
+	}{
+		{name: "case insensitive path", option: WithCaseInsensitivePath(), path: "/users/AbC"},
+		{name: "trailing slash", option: WithTrailingSlash(), path: "/Users/AbC/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{
+				Transport: NewTransport(t,
+					SequenceCalls(Call{
+						Input: Input{Method: http.MethodGet, URL: mustParseURL("/Users/{id}")},
+						Response: Response{
+							Body: TemplateBody("id={{request.pathVars.id}}"),
+						},
+					}),
+					nil,
+					tt.option,
+				),
+			}
+
+			resp, err := client.Get("http://localhost" + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != "id=AbC" {
+				t.Errorf("wrong captured path variable, expected id=AbC, actual %s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,591 @@
+// Package registry serves a minimal Docker registry HTTP API v2 for tests of
+// tooling which pulls and pushes images:
+//
+//	server := registry.NewServer(t, registry.Config{TokenAuth: true})
+//	ref := server.Host() + "/library/app:latest"
+//
+// Supported are manifests and blobs by tag or digest, chunked and monolithic
+// blob uploads, cross repository mounts, tag listing and the bearer token
+// auth flow with per repository pull and push scopes. Requests which are not
+// part of the api are reported to the TestReporter.
+package registry
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/amidgo/httpmock"
+)
+
+// Config configures the registry.
+type Config struct {
+	// TokenAuth enables the bearer token flow, /v2/ requests without a token
+	// granting the repository scope get 401 with WWW-Authenticate pointing
+	// to the /token endpoint of the server.
+	TokenAuth bool
+	// Username and Password are required by the /token endpoint as basic
+	// auth when set, anonymous tokens are issued otherwise.
+	Username string
+	Password string
+}
+
+// Manifest is a stored manifest.
+type Manifest struct {
+	MediaType string
+	Digest    string
+	Data      []byte
+}
+
+type repository struct {
+	manifests map[string]Manifest
+	tags      map[string]string
+	blobs     map[string]bool
+}
+
+// Server is the registry, it is closed at Cleanup.
+type Server struct {
+	*httptest.Server
+
+	t      httpmock.TestReporter
+	config Config
+
+	mu           sync.Mutex
+	repositories map[string]*repository
+	blobs        map[string][]byte
+	uploads      map[string][]byte
+	tokens       map[string][]string
+}
+
+func NewServer(t httpmock.TestReporter, config Config) *Server {
+	s := &Server{
+		t:            t,
+		config:       config,
+		repositories: make(map[string]*repository),
+		blobs:        make(map[string][]byte),
+		uploads:      make(map[string][]byte),
+		tokens:       make(map[string][]string),
+	}
+
+	s.Server = httptest.NewServer(s)
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Host returns host:port of the server to use in image references.
+func (s *Server) Host() string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+// PutBlob stores the blob in the repository and returns its digest.
+func (s *Server) PutBlob(name string, data []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.putBlob(name, data)
+}
+
+// PutManifest stores the manifest under the tag and returns its digest.
+func (s *Server) PutManifest(name, tag, mediaType string, data []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.putManifest(name, tag, mediaType, data)
+}
+
+// Manifest returns the manifest by tag or digest.
+func (s *Server) Manifest(name, reference string) (Manifest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.manifest(name, reference)
+}
+
+// Blob returns the blob by digest.
+func (s *Server) Blob(digest string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.blobs[digest]
+
+	return data, ok
+}
+
+// Tags returns sorted tags of the repository.
+func (s *Server) Tags(name string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tagList(name)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		s.token(w, r)
+
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/v2/")
+	if !ok {
+		s.unexpected(w, r)
+
+		return
+	}
+
+	name, kind, rest := splitPath(path)
+
+	if !s.authorized(w, r, name) {
+		return
+	}
+
+	switch {
+	case path == "" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		writeJSON(w, http.StatusOK, struct{}{})
+	case kind == "manifests" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		s.getManifest(w, r, name, rest)
+	case kind == "manifests" && r.Method == http.MethodPut:
+		s.uploadManifest(w, r, name, rest)
+	case kind == "manifests" && r.Method == http.MethodDelete:
+		s.deleteManifest(w, name, rest)
+	case kind == "blobs/uploads" && r.Method == http.MethodPost:
+		s.startUpload(w, r, name)
+	case kind == "blobs/uploads" && r.Method == http.MethodPatch:
+		s.patchUpload(w, r, name, rest)
+	case kind == "blobs/uploads" && r.Method == http.MethodPut:
+		s.finishUpload(w, r, name, rest)
+	case kind == "blobs" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		s.getBlob(w, r, name, rest)
+	case kind == "tags/list" && r.Method == http.MethodGet:
+		if s.repositories[name] == nil {
+			writeError(w, http.StatusNotFound, "NAME_UNKNOWN", "repository name not known to registry")
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"name": name, "tags": s.tagList(name)})
+	default:
+		s.unexpected(w, r)
+	}
+}
+
+// splitPath splits name/kind/rest, names may contain slashes.
+func splitPath(path string) (name, kind, rest string) {
+	for _, kind := range []string{"/tags/list", "/manifests/", "/blobs/uploads", "/blobs/"} {
+		i := strings.LastIndex(path, kind)
+		if i < 0 {
+			continue
+		}
+
+		return path[:i], strings.Trim(kind, "/"), strings.TrimPrefix(path[i+len(kind):], "/")
+	}
+
+	return "", "", ""
+}
+
+func (s *Server) getManifest(w http.ResponseWriter, r *http.Request, name, reference string) {
+	manifest, ok := s.manifest(name, reference)
+	if !ok {
+		writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", "manifest unknown")
+
+		return
+	}
+
+	w.Header().Set("Content-Type", manifest.MediaType)
+	w.Header().Set("Docker-Content-Digest", manifest.Digest)
+	w.Header().Set("Content-Length", strconv.Itoa(len(manifest.Data)))
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(manifest.Data)
+	}
+}
+
+func (s *Server) uploadManifest(w http.ResponseWriter, r *http.Request, name, reference string) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("registry put manifest %s:%s, read body from request, %s", name, reference, err)
+
+		return
+	}
+
+	var manifest struct {
+		MediaType string       `json:"mediaType"`
+		Config    descriptor   `json:"config"`
+		Layers    []descriptor `json:"layers"`
+	}
+
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "MANIFEST_INVALID", err.Error())
+
+		return
+	}
+
+	repo := s.repository(name)
+
+	for _, blob := range append([]descriptor{manifest.Config}, manifest.Layers...) {
+		if blob.Digest != "" && !repo.blobs[blob.Digest] {
+			writeError(w, http.StatusBadRequest, "MANIFEST_BLOB_UNKNOWN", "blob unknown to registry "+blob.Digest)
+
+			return
+		}
+	}
+
+	mediaType := r.Header.Get("Content-Type")
+	if mediaType == "" {
+		mediaType = manifest.MediaType
+	}
+
+	tag := reference
+
+	if strings.HasPrefix(reference, "sha256:") {
+		if sha256Digest(data) != reference {
+			writeError(w, http.StatusBadRequest, "DIGEST_INVALID", "provided digest did not match uploaded content")
+
+			return
+		}
+
+		tag = ""
+	}
+
+	digest := s.putManifest(name, tag, mediaType, data)
+
+	w.Header().Set("Location", "/v2/"+name+"/manifests/"+digest)
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.WriteHeader(http.StatusCreated)
+}
+
+type descriptor struct {
+	Digest string `json:"digest"`
+}
+
+func (s *Server) deleteManifest(w http.ResponseWriter, name, reference string) {
+	manifest, ok := s.manifest(name, reference)
+	if !ok {
+		writeError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", "manifest unknown")
+
+		return
+	}
+
+	repo := s.repositories[name]
+
+	delete(repo.manifests, manifest.Digest)
+
+	for tag, digest := range repo.tags {
+		if digest == manifest.Digest {
+			delete(repo.tags, tag)
+		}
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) startUpload(w http.ResponseWriter, r *http.Request, name string) {
+	query := r.URL.Query()
+
+	if mount := query.Get("mount"); mount != "" {
+		from := s.repositories[query.Get("from")]
+
+		if from != nil && from.blobs[mount] {
+			s.repository(name).blobs[mount] = true
+
+			w.Header().Set("Location", "/v2/"+name+"/blobs/"+mount)
+			w.Header().Set("Docker-Content-Digest", mount)
+			w.WriteHeader(http.StatusCreated)
+
+			return
+		}
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("registry start upload %s, read body from request, %s", name, err)
+
+		return
+	}
+
+	if digest := query.Get("digest"); digest != "" {
+		s.commitBlob(w, name, digest, data)
+
+		return
+	}
+
+	id := newUploadID()
+	s.uploads[id] = data
+
+	writeUploadStatus(w, name, id, len(data))
+}
+
+func (s *Server) patchUpload(w http.ResponseWriter, r *http.Request, name, id string) {
+	data, ok := s.uploads[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "BLOB_UPLOAD_UNKNOWN", "blob upload unknown to registry")
+
+		return
+	}
+
+	chunk, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("registry patch upload %s, read body from request, %s", name, err)
+
+		return
+	}
+
+	s.uploads[id] = append(data, chunk...)
+
+	writeUploadStatus(w, name, id, len(s.uploads[id]))
+}
+
+func (s *Server) finishUpload(w http.ResponseWriter, r *http.Request, name, id string) {
+	data, ok := s.uploads[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "BLOB_UPLOAD_UNKNOWN", "blob upload unknown to registry")
+
+		return
+	}
+
+	chunk, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("registry finish upload %s, read body from request, %s", name, err)
+
+		return
+	}
+
+	delete(s.uploads, id)
+
+	s.commitBlob(w, name, r.URL.Query().Get("digest"), append(data, chunk...))
+}
+
+func (s *Server) commitBlob(w http.ResponseWriter, name, digest string, data []byte) {
+	if actual := sha256Digest(data); digest != actual {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", "provided digest did not match uploaded content")
+
+		return
+	}
+
+	s.putBlob(name, data)
+
+	w.Header().Set("Location", "/v2/"+name+"/blobs/"+digest)
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) getBlob(w http.ResponseWriter, r *http.Request, name, digest string) {
+	repo := s.repositories[name]
+	if repo == nil || !repo.blobs[digest] {
+		writeError(w, http.StatusNotFound, "BLOB_UNKNOWN", "blob unknown to registry")
+
+		return
+	}
+
+	data := s.blobs[digest]
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(data)
+	}
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if s.config.Username != "" {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != s.config.Username || password != s.config.Password {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "authentication required")
+
+			return
+		}
+	}
+
+	token := "registry-token-" + strconv.Itoa(len(s.tokens)+1)
+	s.tokens[token] = r.URL.Query()["scope"]
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"token":        token,
+		"access_token": token,
+		"expires_in":   300,
+	})
+}
+
+func (s *Server) authorized(w http.ResponseWriter, r *http.Request, name string) bool {
+	if !s.config.TokenAuth {
+		return true
+	}
+
+	action := "pull"
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		action = "pull,push"
+	}
+
+	challenge := fmt.Sprintf(`Bearer realm="%s/token",service="%s"`, s.URL, s.Host())
+	if name != "" {
+		challenge += fmt.Sprintf(`,scope="repository:%s:%s"`, name, action)
+	}
+
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	scopes, ok := s.tokens[token]
+	if ok && (name == "" || granted(scopes, name, action)) {
+		return true
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge)
+	writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "authentication required")
+
+	return false
+}
+
+func granted(scopes []string, name, action string) bool {
+	for _, required := range strings.Split(action, ",") {
+		ok := slices.ContainsFunc(scopes, func(scope string) bool {
+			parts := strings.Split(scope, ":")
+			if len(parts) != 3 || parts[0] != "repository" || parts[1] != name {
+				return false
+			}
+
+			actions := strings.Split(parts[2], ",")
+
+			return slices.Contains(actions, required) || slices.Contains(actions, "*")
+		})
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Server) unexpected(w http.ResponseWriter, r *http.Request) {
+	s.t.Errorf("registry unexpected request %s %s", r.Method, r.URL.RequestURI())
+
+	writeError(w, http.StatusNotFound, "UNSUPPORTED", "the operation is unsupported")
+}
+
+func (s *Server) repository(name string) *repository {
+	repo, ok := s.repositories[name]
+	if !ok {
+		repo = &repository{
+			manifests: make(map[string]Manifest),
+			tags:      make(map[string]string),
+			blobs:     make(map[string]bool),
+		}
+
+		s.repositories[name] = repo
+	}
+
+	return repo
+}
+
+func (s *Server) putBlob(name string, data []byte) string {
+	digest := sha256Digest(data)
+
+	s.blobs[digest] = data
+	s.repository(name).blobs[digest] = true
+
+	return digest
+}
+
+func (s *Server) putManifest(name, tag, mediaType string, data []byte) string {
+	repo := s.repository(name)
+
+	digest := sha256Digest(data)
+
+	repo.manifests[digest] = Manifest{
+		MediaType: mediaType,
+		Digest:    digest,
+		Data:      data,
+	}
+
+	if tag != "" {
+		repo.tags[tag] = digest
+	}
+
+	return digest
+}
+
+func (s *Server) manifest(name, reference string) (Manifest, bool) {
+	repo, ok := s.repositories[name]
+	if !ok {
+		return Manifest{}, false
+	}
+
+	if digest, ok := repo.tags[reference]; ok {
+		reference = digest
+	}
+
+	manifest, ok := repo.manifests[reference]
+
+	return manifest, ok
+}
+
+func (s *Server) tagList(name string) []string {
+	repo, ok := s.repositories[name]
+	if !ok {
+		return nil
+	}
+
+	tags := make([]string, 0, len(repo.tags))
+
+	for tag := range repo.tags {
+		tags = append(tags, tag)
+	}
+
+	slices.Sort(tags)
+
+	return tags
+}
+
+func writeUploadStatus(w http.ResponseWriter, name, id string, size int) {
+	w.Header().Set("Location", "/v2/"+name+"/blobs/uploads/"+id)
+	w.Header().Set("Docker-Upload-UUID", id)
+	w.Header().Set("Range", "0-"+strconv.Itoa(max(size-1, 0)))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	writeJSON(w, statusCode, map[string]any{
+		"errors": []map[string]string{{"code": code, "message": message}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value any) {
+	data, _ := json.Marshal(value)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	_, _ = w.Write(data)
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newUploadID() string {
+	var id [16]byte
+
+	_, _ = rand.Read(id[:])
+
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,220 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type testReporterMock struct {
+	errors []string
+}
+
+func (tm *testReporterMock) Errorf(format string, args ...any) {
+	tm.errors = append(tm.errors, fmt.Sprintf(format, args...))
+}
+
+func (tm *testReporterMock) Fatalf(format string, args ...any) {}
+
+func (tm *testReporterMock) Cleanup(f func()) {}
+
+type client struct {
+	t      *testing.T
+	server *Server
+	token  string
+}
+
+func (c *client) do(method, path string, header http.Header, body string) (*http.Response, string) {
+	c.t.Helper()
+
+	target := path
+	if !strings.HasPrefix(path, "http") {
+		target = c.server.URL + path
+	}
+
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	return resp, string(respBody)
+}
+
+// authorize follows the WWW-Authenticate challenge like docker clients do.
+func (c *client) authorize(challenge, username, password string) {
+	c.t.Helper()
+
+	params := make(map[string]string)
+
+	for _, part := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), `",`) {
+		key, value, _ := strings.Cut(part, "=")
+		params[key] = strings.Trim(value, `"`)
+	}
+
+	query := url.Values{"service": {params["service"]}, "scope": {params["scope"]}}
+
+	req, err := http.NewRequest(http.MethodGet, params["realm"]+"?"+query.Encode(), http.NoBody)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	req.SetBasicAuth(username, password)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		Token string `json:"token"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	c.token = token.Token
+}
+
+func Test_Server_PushPull(t *testing.T) {
+	server := NewServer(t, Config{TokenAuth: true, Username: "user", Password: "secret"})
+
+	c := &client{t: t, server: server}
+
+	resp, _ := c.do(http.MethodPost, "/v2/library/app/blobs/uploads/", nil, "")
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("wrong status code without token, expected %d, actual %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	if expected := `,scope="repository:library/app:pull,push"`; !strings.HasSuffix(challenge, expected) {
+		t.Fatalf("wrong challenge %s", challenge)
+	}
+
+	c.authorize(challenge, "user", "secret")
+
+	layer := "layer data"
+	layerDigest := sha256Digest([]byte(layer))
+
+	resp, _ = c.do(http.MethodPost, "/v2/library/app/blobs/uploads/", nil, "")
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("wrong start upload status code %d", resp.StatusCode)
+	}
+
+	location := resp.Header.Get("Location")
+
+	resp, _ = c.do(http.MethodPatch, location, nil, layer[:5])
+	if resp.Header.Get("Range") != "0-4" {
+		t.Errorf("wrong Range after patch, actual %s", resp.Header.Get("Range"))
+	}
+
+	resp, _ = c.do(http.MethodPut, resp.Header.Get("Location")+"?digest="+layerDigest, nil, layer[5:])
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("Docker-Content-Digest") != layerDigest {
+		t.Fatalf("wrong finish upload response %d %s", resp.StatusCode, resp.Header.Get("Docker-Content-Digest"))
+	}
+
+	config := `{"architecture":"amd64"}`
+	configDigest := sha256Digest([]byte(config))
+
+	resp, _ = c.do(http.MethodPost, "/v2/library/app/blobs/uploads/?digest="+configDigest, nil, config)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("wrong monolithic upload status code %d", resp.StatusCode)
+	}
+
+	manifest := fmt.Sprintf(
+		`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"digest":%q},"layers":[{"digest":%q}]}`,
+		configDigest, layerDigest,
+	)
+
+	resp, _ = c.do(http.MethodPut, "/v2/library/app/manifests/latest",
+		http.Header{"Content-Type": {"application/vnd.oci.image.manifest.v1+json"}},
+		manifest,
+	)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("wrong put manifest status code %d", resp.StatusCode)
+	}
+
+	manifestDigest := resp.Header.Get("Docker-Content-Digest")
+
+	resp, body := c.do(http.MethodGet, "/v2/library/app/manifests/latest", nil, "")
+	if body != manifest || resp.Header.Get("Docker-Content-Digest") != manifestDigest {
+		t.Errorf("wrong pulled manifest %s", body)
+	}
+
+	_, body = c.do(http.MethodGet, "/v2/library/app/blobs/"+layerDigest, nil, "")
+	if body != layer {
+		t.Errorf("wrong pulled layer %s", body)
+	}
+
+	_, body = c.do(http.MethodGet, "/v2/library/app/tags/list", nil, "")
+	if body != `{"name":"library/app","tags":["latest"]}` {
+		t.Errorf("wrong tags %s", body)
+	}
+
+	resp, _ = c.do(http.MethodGet, "/v2/other/manifests/latest", nil, "")
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("token granted access to another repository, status code %d", resp.StatusCode)
+	}
+}
+
+func Test_Server_Seeded(t *testing.T) {
+	server := NewServer(t, Config{})
+
+	digest := server.PutBlob("app", []byte("layer"))
+	server.PutManifest("app", "v1", "application/vnd.oci.image.manifest.v1+json", []byte(`{}`))
+
+	c := &client{t: t, server: server}
+
+	resp, _ := c.do(http.MethodHead, "/v2/app/manifests/v1", nil, "")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/vnd.oci.image.manifest.v1+json" {
+		t.Errorf("wrong head manifest response %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	resp, _ = c.do(http.MethodPost, "/v2/copy/blobs/uploads/?mount="+digest+"&from=app", nil, "")
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("wrong mount status code %d", resp.StatusCode)
+	}
+
+	resp, body := c.do(http.MethodGet, "/v2/app/manifests/v2", nil, "")
+	if resp.StatusCode != http.StatusNotFound || !strings.Contains(body, "MANIFEST_UNKNOWN") {
+		t.Errorf("wrong missing manifest response %d %s", resp.StatusCode, body)
+	}
+}
+
+func Test_Server_UnexpectedRequest(t *testing.T) {
+	tr := &testReporterMock{}
+
+	server := NewServer(tr, Config{})
+	defer server.Close()
+
+	c := &client{t: t, server: server}
+
+	c.do(http.MethodGet, "/v2/_catalog", nil, "")
+
+	if expected := []string{"registry unexpected request GET /v2/_catalog"}; !slices.Equal(tr.errors, expected) {
+		t.Errorf("wrong reported errors, expected %v, actual %v", expected, tr.errors)
+	}
+}
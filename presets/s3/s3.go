@@ -0,0 +1,679 @@
+// Package s3 serves a minimal S3 compatible object store for tests of code
+// using the AWS SDK or minio-go, clients must use path-style addressing:
+//
+//	server := s3.NewServer(t, []string{"bucket"})
+//
+//	client := s3sdk.NewFromConfig(cfg, func(o *s3sdk.Options) {
+//		o.BaseEndpoint = aws.String(server.URL)
+//		o.UsePathStyle = true
+//	})
+//
+// Supported operations are CreateBucket, HeadBucket, GetBucketLocation,
+// ListBuckets, PutObject, GetObject, HeadObject, DeleteObject,
+// ListObjectsV2 and multipart uploads. Signatures are not verified, streaming
+// aws-chunked payloads are decoded. Other requests are reported to the
+// TestReporter.
+//
+// The store is served on httpmock.NewServer, so the options apply to it,
+// e.g. httpmock.WithUserAgent or httpmock.WithTranscript.
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amidgo/httpmock"
+)
+
+const xmlns = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// Object is a stored object.
+type Object struct {
+	Data         []byte
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+type multipartUpload struct {
+	bucket string
+	key    string
+	parts  map[int][]byte
+}
+
+// Server is the object store, it is an http.Handler and serves itself on
+// a started httpmock.Server closed at Cleanup.
+type Server struct {
+	*httpmock.Server
+
+	reporter httpmock.TestReporter
+
+	mu       sync.Mutex
+	buckets  map[string]map[string]Object
+	uploads  map[string]*multipartUpload
+	uploadID int
+}
+
+// NewServer starts the store with the buckets created, requests are served
+// any number of times.
+func NewServer(t httpmock.TestReporter, buckets []string, opts ...httpmock.Option) *Server {
+	s := &Server{
+		reporter: t,
+		buckets:  make(map[string]map[string]Object),
+		uploads:  make(map[string]*multipartUpload),
+	}
+
+	for _, bucket := range buckets {
+		s.buckets[bucket] = make(map[string]Object)
+	}
+
+	s.Server = httpmock.NewServer(t,
+		httpmock.CallsFunc(0, func(int, *http.Request) (httpmock.Call, bool) {
+			return httpmock.Call{}, true
+		}),
+		func(t httpmock.TestReporter, w http.ResponseWriter, r *http.Request, _ httpmock.Call) {
+			s.serve(t, w, r)
+		},
+		opts...,
+	)
+
+	return s
+}
+
+// PutObject stores the object, use it to seed the bucket.
+func (s *Server) PutObject(bucket, key string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.buckets[bucket] == nil {
+		s.buckets[bucket] = make(map[string]Object)
+	}
+
+	s.buckets[bucket][key] = newObject(data, "", etag(data))
+}
+
+// Object returns the stored object.
+func (s *Server) Object(bucket, key string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object, ok := s.buckets[bucket][key]
+
+	return object, ok
+}
+
+// Keys returns sorted keys of the bucket.
+func (s *Server) Keys(bucket string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return sortedKeys(s.buckets[bucket])
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.serve(s.reporter, w, r)
+}
+
+// serve reads the payload before taking the lock, so slow uploads do not
+// block other requests.
+func (s *Server) serve(t httpmock.TestReporter, w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	query := r.URL.Query()
+
+	var payload []byte
+
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		var err error
+
+		payload, err = readPayload(r)
+		if err != nil {
+			t.Errorf("s3 %s %s, read body from request, %s", r.Method, r.URL.RequestURI(), err)
+			writeError(w, http.StatusBadRequest, "IncompleteBody", r.URL.Path)
+
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case bucket == "" && r.Method == http.MethodGet:
+		s.listBuckets(w)
+	case key == "":
+		s.serveBucket(t, w, r, bucket, query)
+	default:
+		s.serveObject(t, w, r, bucket, key, query, payload)
+	}
+}
+
+func (s *Server) serveBucket(t httpmock.TestReporter, w http.ResponseWriter, r *http.Request, bucket string, query map[string][]string) {
+	switch r.Method {
+	case http.MethodPut:
+		if s.buckets[bucket] != nil {
+			writeError(w, http.StatusConflict, "BucketAlreadyOwnedByYou", bucket)
+
+			return
+		}
+
+		s.buckets[bucket] = make(map[string]Object)
+
+		w.Header().Set("Location", "/"+bucket)
+		w.WriteHeader(http.StatusOK)
+
+		return
+	}
+
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", bucket)
+
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet && has(query, "location"):
+		writeXML(w, http.StatusOK, locationConstraint{Xmlns: xmlns})
+	case r.Method == http.MethodGet && first(query, "list-type") == "2":
+		s.listObjectsV2(w, bucket, objects, query)
+	default:
+		s.unexpected(t, w, r)
+	}
+}
+
+func (s *Server) serveObject(
+	t httpmock.TestReporter,
+	w http.ResponseWriter,
+	r *http.Request,
+	bucket, key string,
+	query map[string][]string,
+	payload []byte,
+) {
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", bucket)
+
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && has(query, "uploads"):
+		s.createMultipartUpload(w, bucket, key)
+	case r.Method == http.MethodPut && has(query, "uploadId"):
+		s.uploadPart(w, query, payload)
+	case r.Method == http.MethodPost && has(query, "uploadId"):
+		s.completeMultipartUpload(t, w, objects, bucket, key, query, payload)
+	case r.Method == http.MethodDelete && has(query, "uploadId"):
+		s.abortMultipartUpload(w, query)
+	case r.Method == http.MethodPut:
+		s.putObject(w, r, objects, key, payload)
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		s.getObject(w, r, objects, key)
+	case r.Method == http.MethodDelete:
+		delete(objects, key)
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.unexpected(t, w, r)
+	}
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request, objects map[string]Object, key string, data []byte) {
+	object := newObject(data, r.Header.Get("Content-Type"), etag(data))
+	objects[key] = object
+
+	w.Header().Set("ETag", object.ETag)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request, objects map[string]Object, key string) {
+	object, ok := objects[key]
+	if !ok {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		writeError(w, http.StatusNotFound, "NoSuchKey", key)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", object.ContentType)
+	w.Header().Set("ETag", object.ETag)
+	w.Header().Set("Last-Modified", object.LastModified.UTC().Format(http.TimeFormat))
+	w.Header().Set("Content-Length", strconv.Itoa(len(object.Data)))
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(object.Data)
+	}
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name         `xml:"ListBucketResult"`
+	Xmlns                 string           `xml:"xmlns,attr"`
+	Name                  string           `xml:"Name"`
+	Prefix                string           `xml:"Prefix"`
+	Delimiter             string           `xml:"Delimiter,omitempty"`
+	MaxKeys               int              `xml:"MaxKeys"`
+	KeyCount              int              `xml:"KeyCount"`
+	IsTruncated           bool             `xml:"IsTruncated"`
+	ContinuationToken     string           `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string           `xml:"NextContinuationToken,omitempty"`
+	StartAfter            string           `xml:"StartAfter,omitempty"`
+	Contents              []objectContents `xml:"Contents"`
+	CommonPrefixes        []commonPrefix   `xml:"CommonPrefixes"`
+}
+
+type objectContents struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int    `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+func (s *Server) listObjectsV2(w http.ResponseWriter, bucket string, objects map[string]Object, query map[string][]string) {
+	result := listBucketResult{
+		Xmlns:             xmlns,
+		Name:              bucket,
+		Prefix:            first(query, "prefix"),
+		Delimiter:         first(query, "delimiter"),
+		MaxKeys:           1000,
+		ContinuationToken: first(query, "continuation-token"),
+		StartAfter:        first(query, "start-after"),
+	}
+
+	if maxKeys := first(query, "max-keys"); maxKeys != "" {
+		value, err := strconv.Atoi(maxKeys)
+		if err != nil || value < 0 {
+			writeError(w, http.StatusBadRequest, "InvalidArgument", "max-keys")
+
+			return
+		}
+
+		result.MaxKeys = value
+	}
+
+	after := result.StartAfter
+
+	if result.ContinuationToken != "" {
+		token, err := base64.RawURLEncoding.DecodeString(result.ContinuationToken)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidArgument", "continuation-token")
+
+			return
+		}
+
+		after = string(token)
+	}
+
+	var last string
+
+	seenPrefixes := make(map[string]bool)
+
+	for _, key := range sortedKeys(objects) {
+		if key <= after || !strings.HasPrefix(key, result.Prefix) {
+			continue
+		}
+
+		entry := key
+
+		if result.Delimiter != "" {
+			rest := strings.TrimPrefix(key, result.Prefix)
+			if i := strings.Index(rest, result.Delimiter); i >= 0 {
+				entry = result.Prefix + rest[:i+len(result.Delimiter)]
+
+				if seenPrefixes[entry] {
+					continue
+				}
+			}
+		}
+
+		if result.KeyCount == result.MaxKeys {
+			result.IsTruncated = true
+
+			break
+		}
+
+		result.KeyCount++
+
+		if entry != key {
+			seenPrefixes[entry] = true
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: entry})
+			// every key under the prefix sorts before it followed by 0xff
+			last = entry + "\xff"
+
+			continue
+		}
+
+		object := objects[key]
+
+		result.Contents = append(result.Contents, objectContents{
+			Key:          key,
+			LastModified: object.LastModified.UTC().Format(time.RFC3339),
+			ETag:         object.ETag,
+			Size:         len(object.Data),
+			StorageClass: "STANDARD",
+		})
+		last = key
+	}
+
+	if result.IsTruncated {
+		result.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(last))
+	}
+
+	writeXML(w, http.StatusOK, result)
+}
+
+type listAllMyBucketsResult struct {
+	XMLName xml.Name `xml:"ListAllMyBucketsResult"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Buckets []bucket `xml:"Buckets>Bucket"`
+}
+
+type bucket struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+func (s *Server) listBuckets(w http.ResponseWriter) {
+	result := listAllMyBucketsResult{Xmlns: xmlns}
+
+	for _, name := range sortedKeys(s.buckets) {
+		result.Buckets = append(result.Buckets, bucket{Name: name, CreationDate: time.Now().UTC().Format(time.RFC3339)})
+	}
+
+	writeXML(w, http.StatusOK, result)
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+func (s *Server) createMultipartUpload(w http.ResponseWriter, bucket, key string) {
+	s.uploadID++
+
+	uploadID := strconv.Itoa(s.uploadID)
+
+	s.uploads[uploadID] = &multipartUpload{
+		bucket: bucket,
+		key:    key,
+		parts:  make(map[int][]byte),
+	}
+
+	writeXML(w, http.StatusOK, initiateMultipartUploadResult{
+		Xmlns:    xmlns,
+		Bucket:   bucket,
+		Key:      key,
+		UploadID: uploadID,
+	})
+}
+
+func (s *Server) uploadPart(w http.ResponseWriter, query map[string][]string, data []byte) {
+	upload, ok := s.uploads[first(query, "uploadId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", first(query, "uploadId"))
+
+		return
+	}
+
+	partNumber, err := strconv.Atoi(first(query, "partNumber"))
+	if err != nil || partNumber < 1 {
+		writeError(w, http.StatusBadRequest, "InvalidArgument", "partNumber")
+
+		return
+	}
+
+	upload.parts[partNumber] = data
+
+	w.Header().Set("ETag", etag(data))
+	w.WriteHeader(http.StatusOK)
+}
+
+type completeMultipartUpload struct {
+	Parts []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Bucket  string   `xml:"Bucket"`
+	Key     string   `xml:"Key"`
+	ETag    string   `xml:"ETag"`
+}
+
+func (s *Server) completeMultipartUpload(
+	t httpmock.TestReporter,
+	w http.ResponseWriter,
+	objects map[string]Object,
+	bucket, key string,
+	query map[string][]string,
+	payload []byte,
+) {
+	uploadID := first(query, "uploadId")
+
+	upload, ok := s.uploads[uploadID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", uploadID)
+
+		return
+	}
+
+	var complete completeMultipartUpload
+
+	err := xml.Unmarshal(payload, &complete)
+	if err != nil {
+		t.Errorf("s3 complete multipart upload of %s, decode body, %s", key, err)
+		writeError(w, http.StatusBadRequest, "MalformedXML", key)
+
+		return
+	}
+
+	var (
+		data    []byte
+		digests []byte
+	)
+
+	for _, part := range complete.Parts {
+		partData, ok := upload.parts[part.PartNumber]
+		if !ok || etag(partData) != normalizeETag(part.ETag) {
+			writeError(w, http.StatusBadRequest, "InvalidPart", strconv.Itoa(part.PartNumber))
+
+			return
+		}
+
+		sum := md5.Sum(partData)
+
+		data = append(data, partData...)
+		digests = append(digests, sum[:]...)
+	}
+
+	sum := md5.Sum(digests)
+	object := newObject(data, "", fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(sum[:]), len(complete.Parts)))
+
+	objects[key] = object
+
+	delete(s.uploads, uploadID)
+
+	writeXML(w, http.StatusOK, completeMultipartUploadResult{
+		Xmlns:  xmlns,
+		Bucket: bucket,
+		Key:    key,
+		ETag:   object.ETag,
+	})
+}
+
+func (s *Server) abortMultipartUpload(w http.ResponseWriter, query map[string][]string) {
+	delete(s.uploads, first(query, "uploadId"))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) unexpected(t httpmock.TestReporter, w http.ResponseWriter, r *http.Request) {
+	t.Errorf("s3 unexpected request %s %s", r.Method, r.URL.RequestURI())
+
+	writeError(w, http.StatusNotImplemented, "NotImplemented", r.URL.Path)
+}
+
+type locationConstraint struct {
+	XMLName xml.Name `xml:"LocationConstraint"`
+	Xmlns   string   `xml:"xmlns,attr"`
+}
+
+type errorResponse struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string   `xml:"Code"`
+	Message  string   `xml:"Message"`
+	Resource string   `xml:"Resource"`
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code, resource string) {
+	writeXML(w, statusCode, errorResponse{
+		Code:     code,
+		Message:  code,
+		Resource: resource,
+	})
+}
+
+func writeXML(w http.ResponseWriter, statusCode int, value any) {
+	data, _ := xml.Marshal(value)
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(statusCode)
+
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(data)
+}
+
+// readPayload reads the request body decoding aws-chunked streaming
+// payloads, signatures and trailing checksums are skipped.
+func readPayload(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	streaming := strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") ||
+		strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked")
+	if !streaming {
+		return body, nil
+	}
+
+	return decodeAWSChunked(body)
+}
+
+func decodeAWSChunked(body []byte) ([]byte, error) {
+	var (
+		reader = bufio.NewReader(bytes.NewReader(body))
+		data   []byte
+	)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("read chunk header, %w", err)
+		}
+
+		sizeHex, _, _ := strings.Cut(strings.TrimRight(line, "\r\n"), ";")
+
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse chunk size %q, %w", sizeHex, err)
+		}
+
+		if size == 0 {
+			return data, nil
+		}
+
+		chunk := make([]byte, size)
+
+		_, err = io.ReadFull(reader, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("read chunk, %w", err)
+		}
+
+		data = append(data, chunk...)
+
+		_, err = reader.Discard(2)
+		if err != nil {
+			return nil, fmt.Errorf("read chunk end, %w", err)
+		}
+	}
+}
+
+func newObject(data []byte, contentType, etag string) Object {
+	if contentType == "" {
+		contentType = "binary/octet-stream"
+	}
+
+	return Object{
+		Data:         data,
+		ContentType:  contentType,
+		ETag:         etag,
+		LastModified: time.Now().Truncate(time.Second),
+	}
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func normalizeETag(etag string) string {
+	return `"` + strings.Trim(etag, `"`) + `"`
+}
+
+func has(query map[string][]string, key string) bool {
+	_, ok := query[key]
+
+	return ok
+}
+
+func first(query map[string][]string, key string) string {
+	if values := query[key]; len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
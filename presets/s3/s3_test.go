@@ -0,0 +1,288 @@
+package s3
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amidgo/httpmock"
+)
+
+func do(t *testing.T, method, target, body string, header http.Header) (*http.Response, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp, respBody
+}
+
+func Test_Server_Objects(t *testing.T) {
+	server := NewServer(t, []string{"bucket"})
+
+	resp, _ := do(t, http.MethodPut, server.URL+"/bucket/dir/hello.txt", "Hello World!", http.Header{"Content-Type": {"text/plain"}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("wrong put status code, expected %d, actual %d", http.StatusOK, resp.StatusCode)
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != `"ed076287532e86365e841e92bfc50d8c"` {
+		t.Errorf("wrong ETag, actual %s", etag)
+	}
+
+	resp, body := do(t, http.MethodGet, server.URL+"/bucket/dir/hello.txt", "", nil)
+	if string(body) != "Hello World!" || resp.Header.Get("Content-Type") != "text/plain" {
+		t.Errorf("wrong object, body %s, content type %s", body, resp.Header.Get("Content-Type"))
+	}
+
+	resp, body = do(t, http.MethodHead, server.URL+"/bucket/dir/hello.txt", "", nil)
+	if resp.StatusCode != http.StatusOK || resp.ContentLength != 12 || len(body) != 0 {
+		t.Errorf("wrong head response, status code %d, content length %d", resp.StatusCode, resp.ContentLength)
+	}
+
+	do(t, http.MethodDelete, server.URL+"/bucket/dir/hello.txt", "", nil)
+
+	resp, body = do(t, http.MethodGet, server.URL+"/bucket/dir/hello.txt", "", nil)
+	if resp.StatusCode != http.StatusNotFound || !strings.Contains(string(body), "<Code>NoSuchKey</Code>") {
+		t.Errorf("wrong missing object response, status code %d, body %s", resp.StatusCode, body)
+	}
+
+	resp, _ = do(t, http.MethodGet, server.URL+"/missing/key", "", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("wrong missing bucket status code, actual %d", resp.StatusCode)
+	}
+}
+
+func Test_Server_AWSChunkedPayload(t *testing.T) {
+	server := NewServer(t, []string{"bucket"})
+
+	body := "5;chunk-signature=abc\r\nHello\r\n7;chunk-signature=def\r\n World!\r\n0;chunk-signature=ghi\r\nx-amz-checksum-crc32:AAAAAA==\r\n\r\n"
+
+	do(t, http.MethodPut, server.URL+"/bucket/key", body, http.Header{
+		"X-Amz-Content-Sha256":         {"STREAMING-AWS4-HMAC-SHA256-PAYLOAD"},
+		"X-Amz-Decoded-Content-Length": {"12"},
+	})
+
+	object, ok := server.Object("bucket", "key")
+	if !ok || string(object.Data) != "Hello World!" {
+		t.Errorf("wrong decoded object, actual %q", object.Data)
+	}
+}
+
+func Test_Server_ListObjectsV2(t *testing.T) {
+	server := NewServer(t, []string{"bucket"})
+
+	for _, key := range []string{"a.txt", "dir/1.txt", "dir/2.txt", "dir/sub/3.txt", "other/4.txt", "z.txt"} {
+		server.PutObject("bucket", key, []byte(key))
+	}
+
+	list := func(query url.Values) listBucketResult {
+		query.Set("list-type", "2")
+
+		_, body := do(t, http.MethodGet, server.URL+"/bucket?"+query.Encode(), "", nil)
+
+		var result listBucketResult
+
+		err := xml.Unmarshal(body, &result)
+		if err != nil {
+			t.Fatalf("unmarshal list result, %s, body %s", err, body)
+		}
+
+		return result
+	}
+
+	entries := func(result listBucketResult) []string {
+		var entries []string
+
+		for _, content := range result.Contents {
+			entries = append(entries, content.Key)
+		}
+
+		for _, prefix := range result.CommonPrefixes {
+			entries = append(entries, prefix.Prefix+"*")
+		}
+
+		return entries
+	}
+
+	result := list(url.Values{"prefix": {"dir/"}})
+	if expected := []string{"dir/1.txt", "dir/2.txt", "dir/sub/3.txt"}; !slices.Equal(entries(result), expected) {
+		t.Errorf("wrong prefix listing, expected %v, actual %v", expected, entries(result))
+	}
+
+	result = list(url.Values{"delimiter": {"/"}})
+	if expected := []string{"a.txt", "z.txt", "dir/*", "other/*"}; !slices.Equal(entries(result), expected) {
+		t.Errorf("wrong delimiter listing, expected %v, actual %v", expected, entries(result))
+	}
+
+	var (
+		pages []string
+		token string
+	)
+
+	for {
+		query := url.Values{"delimiter": {"/"}, "max-keys": {"2"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		result = list(query)
+		pages = append(pages, strings.Join(entries(result), ","))
+
+		if !result.IsTruncated {
+			break
+		}
+
+		token = result.NextContinuationToken
+	}
+
+	if expected := []string{"a.txt,dir/*", "z.txt,other/*"}; !slices.Equal(pages, expected) {
+		t.Errorf("wrong pages, expected %v, actual %v", expected, pages)
+	}
+}
+
+func Test_Server_MultipartUpload(t *testing.T) {
+	server := NewServer(t, []string{"bucket"})
+
+	_, body := do(t, http.MethodPost, server.URL+"/bucket/big?uploads", "", nil)
+
+	var initiate initiateMultipartUploadResult
+
+	err := xml.Unmarshal(body, &initiate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := []string{"first ", "second"}
+
+	var complete strings.Builder
+
+	complete.WriteString("<CompleteMultipartUpload>")
+
+	for i, part := range parts {
+		partNumber := string(rune('1' + i))
+
+		resp, _ := do(t, http.MethodPut, server.URL+"/bucket/big?partNumber="+partNumber+"&uploadId="+initiate.UploadID, part, nil)
+
+		complete.WriteString("<Part><PartNumber>" + partNumber + "</PartNumber><ETag>" + resp.Header.Get("ETag") + "</ETag></Part>")
+	}
+
+	complete.WriteString("</CompleteMultipartUpload>")
+
+	resp, body := do(t, http.MethodPost, server.URL+"/bucket/big?uploadId="+initiate.UploadID, complete.String(), nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("wrong complete status code %d, body %s", resp.StatusCode, body)
+	}
+
+	object, _ := server.Object("bucket", "big")
+	if string(object.Data) != "first second" || !strings.HasSuffix(object.ETag, `-2"`) {
+		t.Errorf("wrong multipart object, data %s, etag %s", object.Data, object.ETag)
+	}
+}
+
+type testReporterMock struct {
+	errors []string
+}
+
+func (tm *testReporterMock) Errorf(format string, args ...any) {
+	tm.errors = append(tm.errors, fmt.Sprintf(format, args...))
+}
+
+func (tm *testReporterMock) Fatalf(format string, args ...any) {}
+
+func (tm *testReporterMock) Cleanup(f func()) {}
+
+func Test_Server_UnexpectedRequest(t *testing.T) {
+	tr := &testReporterMock{}
+
+	server := NewServer(tr, []string{"bucket"})
+	defer server.Close()
+
+	resp, _ := do(t, http.MethodPost, server.URL+"/bucket?delete", "", nil)
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("wrong status code, expected %d, actual %d", http.StatusNotImplemented, resp.StatusCode)
+	}
+
+	if expected := []string{"1 call, s3 unexpected request POST /bucket?delete"}; !slices.Equal(tr.errors, expected) {
+		t.Errorf("wrong reported errors, expected %v, actual %v", expected, tr.errors)
+	}
+}
+
+func Test_Server_Options(t *testing.T) {
+	tr := &testReporterMock{}
+
+	server := NewServer(tr, []string{"bucket"}, httpmock.WithUserAgent(`^aws-sdk-go`))
+	defer server.Close()
+
+	do(t, http.MethodGet, server.URL+"/bucket/key", "", http.Header{"User-Agent": {"curl/8.0"}})
+
+	if expected := []string{"1 call, User-Agent curl/8.0 does not match ^aws-sdk-go"}; !slices.Equal(tr.errors, expected) {
+		t.Errorf("wrong reported errors, expected %v, actual %v", expected, tr.errors)
+	}
+}
+
+func Test_Server_SlowUploadDoesNotBlock(t *testing.T) {
+	server := NewServer(t, []string{"bucket"})
+	server.PutObject("bucket", "ready", []byte("ready"))
+
+	body, upload := io.Pipe()
+	uploaded := make(chan error, 1)
+
+	go func() {
+		req, err := http.NewRequest(http.MethodPut, server.URL+"/bucket/slow", body)
+		if err != nil {
+			uploaded <- err
+
+			return
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		uploaded <- err
+	}()
+
+	_, err := upload.Write([]byte("first "))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, data := do(t, http.MethodGet, server.URL+"/bucket/ready", "", nil)
+	if resp.StatusCode != http.StatusOK || string(data) != "ready" {
+		t.Errorf("wrong object during upload, status code %d, body %s", resp.StatusCode, data)
+	}
+
+	_, _ = upload.Write([]byte("second"))
+	upload.Close()
+
+	if err := <-uploaded; err != nil {
+		t.Fatal(err)
+	}
+
+	if object, _ := server.Object("bucket", "slow"); string(object.Data) != "first second" {
+		t.Errorf("wrong uploaded object, actual %s", object.Data)
+	}
+}
@@ -0,0 +1,172 @@
+package httpmock
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PrettyColorEnv configures PrettyReporter colors: "always", "never" or
+// "auto", auto colors output unless NO_COLOR or CI is set.
+const PrettyColorEnv = "HTTPMOCK_COLOR"
+
+const (
+	colorReset = "\x1b[0m"
+	colorGreen = "\x1b[32m"
+	colorRed   = "\x1b[31m"
+	colorBold  = "\x1b[1m"
+)
+
+var (
+	callPrefixPattern   = regexp.MustCompile(`^(\d+) call(?: \[([^\]]*)\])?, (?s)(.*)$`)
+	expectActualPattern = regexp.MustCompile(`^(?s)(.*?),? expect(?:ed)? (.*?),? actual (.*)$`)
+)
+
+type prettyReporter struct {
+	TestReporter
+	color bool
+
+	mu       sync.Mutex
+	failures map[int][]string
+	labels   map[int]string
+	general  []string
+}
+
+// PrettyReporter buffers errors and reports them at Cleanup in one message
+// grouped by call, with expected and actual values on separate aligned
+// lines, green and red when colors are enabled, see PrettyColorEnv.
+//
+//	transport := httpmock.NewTransport(httpmock.PrettyReporter(t), calls, nil)
+func PrettyReporter(t TestReporter) TestReporter {
+	p := &prettyReporter{
+		TestReporter: t,
+		color:        prettyColor(),
+		failures:     make(map[int][]string),
+		labels:       make(map[int]string),
+	}
+
+	t.Cleanup(p.flush)
+
+	return p
+}
+
+func prettyColor() bool {
+	switch strings.ToLower(os.Getenv(PrettyColorEnv)) {
+	case "always", "1", "true":
+		return true
+	case "never", "0", "false":
+		return false
+	}
+
+	_, noColor := os.LookupEnv("NO_COLOR")
+	_, ci := os.LookupEnv("CI")
+
+	return !noColor && !ci
+}
+
+func (p *prettyReporter) Errorf(format string, args ...any) {
+	p.add(fmt.Sprintf(format, args...))
+}
+
+func (p *prettyReporter) Fatalf(format string, args ...any) {
+	p.add(fmt.Sprintf(format, args...))
+	p.flush()
+
+	p.TestReporter.Fatalf("httpmock fatal failure")
+}
+
+func (p *prettyReporter) add(message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	match := callPrefixPattern.FindStringSubmatch(message)
+	if match == nil {
+		p.general = append(p.general, message)
+
+		return
+	}
+
+	number, _ := strconv.Atoi(match[1])
+
+	p.failures[number] = append(p.failures[number], match[3])
+
+	if match[2] != "" {
+		p.labels[number] = match[2]
+	}
+}
+
+func (p *prettyReporter) flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.failures) == 0 && len(p.general) == 0 {
+		return
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("httpmock failures:")
+
+	numbers := make([]int, 0, len(p.failures))
+	for number := range p.failures {
+		numbers = append(numbers, number)
+	}
+
+	slices.Sort(numbers)
+
+	for _, number := range numbers {
+		header := fmt.Sprintf("%d call:", number)
+		if labels, ok := p.labels[number]; ok {
+			header = fmt.Sprintf("%d call [%s]:", number, labels)
+		}
+
+		fmt.Fprintf(&builder, "\n%s", p.paint(colorBold, header))
+
+		for _, message := range p.failures[number] {
+			p.writeMessage(&builder, message)
+		}
+	}
+
+	if len(p.general) > 0 {
+		fmt.Fprintf(&builder, "\n%s", p.paint(colorBold, "transport:"))
+
+		for _, message := range p.general {
+			p.writeMessage(&builder, message)
+		}
+	}
+
+	p.failures = make(map[int][]string)
+	p.labels = make(map[int]string)
+	p.general = nil
+
+	p.TestReporter.Errorf("%s", builder.String())
+}
+
+func (p *prettyReporter) writeMessage(builder *strings.Builder, message string) {
+	match := expectActualPattern.FindStringSubmatch(message)
+	if match == nil {
+		fmt.Fprintf(builder, "\n  %s", indent(message, "  "))
+
+		return
+	}
+
+	fmt.Fprintf(builder, "\n  %s", match[1])
+	fmt.Fprintf(builder, "\n    expected: %s", p.paint(colorGreen, indent(match[2], "              ")))
+	fmt.Fprintf(builder, "\n    actual:   %s", p.paint(colorRed, indent(match[3], "              ")))
+}
+
+func (p *prettyReporter) paint(color, text string) string {
+	if !p.color {
+		return text
+	}
+
+	return color + text + colorReset
+}
+
+func indent(text, prefix string) string {
+	return strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
@@ -0,0 +1,83 @@
+package httpmock
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_PrettyReporter(t *testing.T) {
+	t.Setenv(PrettyColorEnv, "never")
+
+	reporter := &testReporterMock{}
+
+	t.Run("failures", func(t *testing.T) {
+		reporter.t = t
+
+		transport := NewTransport(PrettyReporter(reporter),
+			SequenceCalls(
+				Call{Input: Input{Method: http.MethodGet}},
+				Call{Input: Input{Method: http.MethodPost, Body: RawBody("{\n\"a\": 1\n}")}},
+				Call{Input: Input{Method: http.MethodGet}},
+			),
+			nil,
+		)
+
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Post("http://localhost/items", "", strings.NewReader("{\n\"a\": 2\n}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		resp, err = client.Post("http://localhost/items", "", strings.NewReader("{\n\"a\": 2\n}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+
+	expected := "httpmock failures:" +
+		"\n1 call:" +
+		"\n  wrong r.Method" +
+		"\n    expected: GET" +
+		"\n    actual:   POST" +
+		"\n  body not equal" +
+		"\n    expected: " +
+		"\n    actual:   {\n              \"a\": 2\n              }" +
+		"\n2 call:" +
+		"\n  body not equal" +
+		"\n    expected: {\n              \"a\": 1\n              }" +
+		"\n    actual:   {\n              \"a\": 2\n              }" +
+		"\ntransport:" +
+		"\n  assert handler calls, not all calls were handled"
+
+	if len(reporter.errorfCalls) != 1 || reporter.errorfCalls[0].args[0] != expected {
+		t.Errorf("wrong pretty output,\nexpected %q,\nactual %v", expected, reporter.errorfCalls)
+	}
+}
+
+func Test_PrettyReporter_Color(t *testing.T) {
+	t.Setenv(PrettyColorEnv, "always")
+
+	reporter := &testReporterMock{}
+
+	t.Run("failures", func(t *testing.T) {
+		reporter.t = t
+
+		CompareMethod(PrettyReporter(reporter), http.MethodPost, http.MethodGet)
+	})
+
+	expected := "httpmock failures:" +
+		"\n" + colorBold + "transport:" + colorReset +
+		"\n  wrong r.Method" +
+		"\n    expected: " + colorGreen + "GET" + colorReset +
+		"\n    actual:   " + colorRed + "POST" + colorReset
+
+	if len(reporter.errorfCalls) != 1 || reporter.errorfCalls[0].args[0] != expected {
+		t.Errorf("wrong colored output,\nexpected %q,\nactual %v", expected, reporter.errorfCalls)
+	}
+}
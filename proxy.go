@@ -0,0 +1,331 @@
+package httpmock
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ProxyServer is a forward proxy mock. Clients send absolute-URI requests
+// for http targets and CONNECT for https targets, tunneled TLS is
+// terminated with certificates issued by the proxy CA, so every request is
+// matched against the calls with the target scheme and host in r.URL.
+// Input.URL.Host is compared when set.
+//
+// Proxy-Authorization of a CONNECT request is added to the tunneled
+// requests, so it can be matched with Input.Header. Tunneled requests get a
+// context cancelled when the tunnel or the proxy is closed, so Call.Delay
+// and Call.Timeout end at Cleanup.
+type ProxyServer struct {
+	*httptest.Server
+
+	transport *transport
+	ca        *tls.Certificate
+	caCert    *x509.Certificate
+
+	mu      sync.Mutex
+	certs   map[string]*tls.Certificate
+	tunnels map[net.Conn]context.CancelFunc
+	closed  bool
+	// tunneling counts hijacked CONNECT connections, the server does not
+	// wait for them.
+	tunneling sync.WaitGroup
+}
+
+func NewProxyServer(t TestReporter, calls Calls, handleCall HandleCall, opts ...Option) *ProxyServer {
+	if handleCall == nil {
+		handleCall = HandleCallCompareInput
+	}
+
+	ts := newTransport(t, calls, func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		compareProxyHost(t, r.URL, call.Input.URL)
+
+		handleCall(t, w, r, call)
+	}, opts)
+
+	t.Cleanup(ts.assert)
+	t.Cleanup(ts.webhooks.wait)
+
+	ca, caCert, err := newProxyCA()
+	if err != nil {
+		t.Fatalf("create proxy ca, %s", err)
+
+		return nil
+	}
+
+	proxy := &ProxyServer{
+		transport: ts,
+		ca:        ca,
+		caCert:    caCert,
+		certs:     make(map[string]*tls.Certificate),
+		tunnels:   make(map[net.Conn]context.CancelFunc),
+	}
+
+	proxy.Server = httptest.NewServer(http.HandlerFunc(proxy.serveProxy))
+
+	t.Cleanup(proxy.Close)
+	t.Cleanup(ts.awaitCalls)
+
+	return proxy
+}
+
+// CertPool returns the pool with the proxy CA, clients must trust it for
+// https targets.
+func (p *ProxyServer) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(p.caCert)
+
+	return pool
+}
+
+// Client returns a client sending requests through the proxy and trusting
+// the proxy CA.
+func (p *ProxyServer) Client() *http.Client {
+	proxyURL, _ := url.Parse(p.URL)
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(proxyURL),
+			TLSClientConfig: &tls.Config{RootCAs: p.CertPool()},
+		},
+	}
+}
+
+// Close cancels the requests of open tunnels, closes them and shuts down
+// the proxy, it waits for the tunnels to finish.
+func (p *ProxyServer) Close() {
+	p.mu.Lock()
+
+	p.closed = true
+
+	for conn, cancel := range p.tunnels {
+		cancel()
+		conn.Close()
+	}
+
+	p.mu.Unlock()
+
+	p.Server.Close()
+	p.tunneling.Wait()
+}
+
+func (p *ProxyServer) serveProxy(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.serveConnect(w, r)
+
+		return
+	}
+
+	if r.URL.Host == "" {
+		p.transport.t.Errorf("proxy, %s %s is not a proxy request, absolute uri expected", r.Method, r.RequestURI)
+
+		http.Error(w, "absolute uri expected", http.StatusBadRequest)
+
+		return
+	}
+
+	p.transport.ServeHTTP(w, r)
+}
+
+func (p *ProxyServer) serveConnect(w http.ResponseWriter, r *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+
+		return
+	}
+
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		p.transport.t.Errorf("proxy, hijack CONNECT %s, %s", r.Host, err)
+
+		return
+	}
+
+	defer conn.Close()
+
+	// requests read from the tunnel have no server context, they get the
+	// tunnel one, cancelled when the tunnel or the proxy is closed.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	if !p.openTunnel(conn, cancel) {
+		return
+	}
+
+	defer p.closeTunnel(conn)
+
+	_, err = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+	if err != nil {
+		return
+	}
+
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+
+	tlsConn := tls.Server(conn, &tls.Config{
+		NextProtos: []string{"http/1.1"},
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			name := hello.ServerName
+			if name == "" {
+				name = host
+			}
+
+			return p.certificate(name)
+		},
+	})
+
+	reader := bufio.NewReader(tlsConn)
+
+	for {
+		req, err := http.ReadRequest(reader)
+		if err != nil {
+			return
+		}
+
+		req = req.WithContext(ctx)
+		req.URL.Scheme = "https"
+		req.URL.Host = r.Host
+
+		if auth := r.Header.Values("Proxy-Authorization"); len(auth) > 0 {
+			req.Header["Proxy-Authorization"] = auth
+		}
+
+		w := newResponseWriter()
+
+		p.transport.ServeHTTP(w, req)
+
+		_, _ = io.Copy(io.Discard, req.Body)
+
+		err = w.result(req).Write(tlsConn)
+		if err != nil || req.Close {
+			return
+		}
+	}
+}
+
+// openTunnel tracks the tunnel connection, it reports false when the proxy
+// is closed.
+func (p *ProxyServer) openTunnel(conn net.Conn, cancel context.CancelFunc) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return false
+	}
+
+	p.tunnels[conn] = cancel
+	p.tunneling.Add(1)
+
+	return true
+}
+
+func (p *ProxyServer) closeTunnel(conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.tunnels, conn)
+	p.tunneling.Done()
+}
+
+func (p *ProxyServer) certificate(host string) (*tls.Certificate, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cert, ok := p.certs[host]; ok {
+		return cert, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, p.caCert, &key.PublicKey, p.ca.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("create certificate for %s, %w", host, err)
+	}
+
+	cert := &tls.Certificate{
+		Certificate: [][]byte{der, p.ca.Certificate[0]},
+		PrivateKey:  key,
+	}
+
+	p.certs[host] = cert
+
+	return cert, nil
+}
+
+func newProxyCA() (*tls.Certificate, *x509.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "httpmock proxy CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, cert, nil
+}
+
+func compareProxyHost(t TestReporter, requestURL, inputURL *url.URL) {
+	if inputURL == nil || inputURL.Host == "" {
+		return
+	}
+
+	if requestURL.Host != inputURL.Host {
+		t.Errorf("wrong url.Host, expected %s, actual %s", inputURL.Host, requestURL.Host)
+	}
+
+	if inputURL.Scheme != "" && requestURL.Scheme != inputURL.Scheme {
+		t.Errorf("wrong url.Scheme, expected %s, actual %s", inputURL.Scheme, requestURL.Scheme)
+	}
+}
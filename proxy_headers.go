@@ -0,0 +1,195 @@
+package httpmock
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ProxyHeaders matches proxy metadata of the request structurally, so
+// spacing, header line splitting, parameter case and quoting do not matter.
+// Nil fields are not compared.
+type ProxyHeaders struct {
+	// ForwardedFor is the X-Forwarded-For chain, client first.
+	ForwardedFor []string
+	// ForwardedProto is the X-Forwarded-Proto value.
+	ForwardedProto string
+	// Forwarded are the elements of the Forwarded header, RFC 7239.
+	Forwarded []ForwardedElement
+	// Via are the hops of the Via header, oldest first.
+	Via []ViaHop
+}
+
+// ForwardedElement is one proxy hop of the Forwarded header, values are
+// unquoted, e.g. For is "[2001:db8::1]:4711" for for="[2001:db8::1]:4711".
+type ForwardedElement struct {
+	For   string
+	By    string
+	Host  string
+	Proto string
+}
+
+// ViaHop is one hop of the Via header, Protocol omits the HTTP/ prefix and
+// comments are dropped, e.g. {"1.1", "proxy.local"} for HTTP/1.1 proxy.local
+// (squid).
+type ViaHop struct {
+	Protocol   string
+	ReceivedBy string
+}
+
+func CompareProxyHeaders(t TestReporter, header http.Header, input *ProxyHeaders) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	if input == nil {
+		return
+	}
+
+	if input.ForwardedFor != nil {
+		forwardedFor := parseForwardedFor(header.Values("X-Forwarded-For"))
+
+		if !slices.Equal(forwardedFor, input.ForwardedFor) {
+			t.Errorf("wrong X-Forwarded-For chain, expected %v, actual %v", input.ForwardedFor, forwardedFor)
+		}
+	}
+
+	if input.ForwardedProto != "" {
+		proto := strings.ToLower(strings.TrimSpace(header.Get("X-Forwarded-Proto")))
+
+		if proto != strings.ToLower(input.ForwardedProto) {
+			t.Errorf("wrong X-Forwarded-Proto, expected %s, actual %s", input.ForwardedProto, proto)
+		}
+	}
+
+	if input.Forwarded != nil {
+		forwarded := parseForwarded(header.Values("Forwarded"))
+
+		if !slices.Equal(forwarded, input.Forwarded) {
+			t.Errorf("wrong Forwarded elements, expected %+v, actual %+v", input.Forwarded, forwarded)
+		}
+	}
+
+	if input.Via != nil {
+		via := parseVia(header.Values("Via"))
+
+		if !slices.Equal(via, input.Via) {
+			t.Errorf("wrong Via hops, expected %+v, actual %+v", input.Via, via)
+		}
+	}
+}
+
+func parseForwardedFor(values []string) []string {
+	chain := []string{}
+
+	for _, value := range values {
+		for _, addr := range strings.Split(value, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				chain = append(chain, addr)
+			}
+		}
+	}
+
+	return chain
+}
+
+func parseForwarded(values []string) []ForwardedElement {
+	elements := []ForwardedElement{}
+
+	for _, value := range values {
+		for _, rawElement := range splitHeaderList(value, ',') {
+			if strings.TrimSpace(rawElement) == "" {
+				continue
+			}
+
+			var element ForwardedElement
+
+			for _, pair := range splitHeaderList(rawElement, ';') {
+				key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+				value = unquoteHeaderValue(strings.TrimSpace(value))
+
+				switch strings.ToLower(strings.TrimSpace(key)) {
+				case "for":
+					element.For = value
+				case "by":
+					element.By = value
+				case "host":
+					element.Host = value
+				case "proto":
+					element.Proto = strings.ToLower(value)
+				}
+			}
+
+			elements = append(elements, element)
+		}
+	}
+
+	return elements
+}
+
+func parseVia(values []string) []ViaHop {
+	hops := []ViaHop{}
+
+	for _, value := range values {
+		for _, rawHop := range splitHeaderList(value, ',') {
+			fields := strings.Fields(rawHop)
+			if len(fields) < 2 {
+				continue
+			}
+
+			hops = append(hops, ViaHop{
+				Protocol:   strings.TrimPrefix(strings.ToUpper(fields[0]), "HTTP/"),
+				ReceivedBy: fields[1],
+			})
+		}
+	}
+
+	return hops
+}
+
+// splitHeaderList splits the value by sep outside of quoted strings and
+// comments.
+func splitHeaderList(value string, sep byte) []string {
+	var (
+		parts    []string
+		start    int
+		quoted   bool
+		comments int
+	)
+
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '\\' && (quoted || comments > 0):
+			i++
+		case c == '"' && comments == 0:
+			quoted = !quoted
+		case c == '(' && !quoted:
+			comments++
+		case c == ')' && !quoted && comments > 0:
+			comments--
+		case c == sep && !quoted && comments == 0:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, value[start:])
+}
+
+func unquoteHeaderValue(value string) string {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value
+	}
+
+	var b strings.Builder
+
+	for i := 1; i < len(value)-1; i++ {
+		if value[i] == '\\' && i+1 < len(value)-1 {
+			i++
+		}
+
+		b.WriteByte(value[i])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_CompareProxyHeaders(t *testing.T) {
+	header := http.Header{
+		"X-Forwarded-For":   {"203.0.113.7, 10.0.0.1", "10.0.0.2"},
+		"X-Forwarded-Proto": {"HTTPS"},
+		"Forwarded":         {`For="[2001:db8::1]:4711";proto=HTTPS, for=10.0.0.1;by="proxy;1"`},
+		"Via":               {"1.0 fred, HTTP/1.1 proxy.local (squid, v4)", "1.1 edge"},
+	}
+
+	CompareProxyHeaders(t, header, &ProxyHeaders{
+		ForwardedFor:   []string{"203.0.113.7", "10.0.0.1", "10.0.0.2"},
+		ForwardedProto: "https",
+		Forwarded: []ForwardedElement{
+			{For: "[2001:db8::1]:4711", Proto: "https"},
+			{For: "10.0.0.1", By: "proxy;1"},
+		},
+		Via: []ViaHop{
+			{Protocol: "1.0", ReceivedBy: "fred"},
+			{Protocol: "1.1", ReceivedBy: "proxy.local"},
+			{Protocol: "1.1", ReceivedBy: "edge"},
+		},
+	})
+}
+
+func Test_CompareProxyHeaders_Wrong(t *testing.T) {
+	header := http.Header{
+		"X-Forwarded-For": {"10.0.0.1"},
+		"Via":             {"1.1 edge"},
+	}
+
+	CompareProxyHeaders(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{
+				format: "wrong X-Forwarded-For chain, expected %v, actual %v",
+				args:   []any{[]string{"203.0.113.7", "10.0.0.1"}, []string{"10.0.0.1"}},
+			},
+			{
+				format: "wrong X-Forwarded-Proto, expected %s, actual %s",
+				args:   []any{"https", ""},
+			},
+			{
+				format: "wrong Forwarded elements, expected %+v, actual %+v",
+				args:   []any{[]ForwardedElement{{For: "10.0.0.1"}}, []ForwardedElement{}},
+			},
+		},
+		nil,
+	)(t), header, &ProxyHeaders{
+		ForwardedFor:   []string{"203.0.113.7", "10.0.0.1"},
+		ForwardedProto: "https",
+		Forwarded:      []ForwardedElement{{For: "10.0.0.1"}},
+		Via:            []ViaHop{{Protocol: "1.1", ReceivedBy: "edge"}},
+	})
+}
@@ -0,0 +1,199 @@
+package httpmock
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func Test_NewProxyServer(t *testing.T) {
+	proxyAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+
+	proxy := NewProxyServer(t,
+		SequenceCalls(
+			Call{
+				Input: Input{
+					Method: http.MethodGet,
+					URL:    mustParseURL("http://plain.example.com/status"),
+					Header: http.Header{"Proxy-Authorization": {proxyAuth}},
+				},
+				Response: Response{Body: RawBody("plain")},
+			},
+			Call{
+				Input: Input{
+					Method: http.MethodGet,
+					URL:    mustParseURL("https://secure.example.com:443/users?id=1"),
+					Header: http.Header{"Proxy-Authorization": {proxyAuth}},
+				},
+				Response: Response{Body: TemplateBody("{{request.scheme}} {{request.query.id}}")},
+			},
+			Call{
+				Input: Input{
+					Method: http.MethodGet,
+					URL:    mustParseURL("https://secure.example.com:443/users?id=2"),
+				},
+				Response: Response{Body: RawBody("reused")},
+			},
+		),
+		nil,
+	)
+
+	client := proxy.Client()
+	client.Transport.(*http.Transport).Proxy = http.ProxyURL(&url.URL{
+		Scheme: "http",
+		User:   url.UserPassword("user", "secret"),
+		Host:   proxy.Listener.Addr().String(),
+	})
+
+	for _, tst := range []struct {
+		target       string
+		expectedBody string
+	}{
+		{"http://plain.example.com/status", "plain"},
+		{"https://secure.example.com/users?id=1", "https 1"},
+		{"https://secure.example.com/users?id=2", "reused"},
+	} {
+		resp, err := client.Get(tst.target)
+		if err != nil {
+			t.Fatalf("get %s, %s", tst.target, err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(body) != tst.expectedBody {
+			t.Errorf("get %s, wrong body, expected %s, actual %s", tst.target, tst.expectedBody, body)
+		}
+	}
+}
+
+func Test_NewProxyServer_WrongTarget(t *testing.T) {
+	tr := ExpectFailureTestReporter(
+		[]testReporterCall{
+			{
+				format: "1 call, wrong url.Host, expected %s, actual %s",
+				args:   []any{"api.example.com", "evil.example.com"},
+			},
+			{
+				format: "proxy, %s %s is not a proxy request, absolute uri expected",
+				args:   []any{http.MethodGet, "/direct"},
+			},
+		},
+		nil,
+	)(t)
+
+	proxy := NewProxyServer(tr,
+		SequenceCalls(
+			Call{
+				Input: Input{
+					Method: http.MethodGet,
+					URL:    mustParseURL("http://api.example.com/"),
+				},
+			},
+		),
+		nil,
+	)
+
+	resp, err := proxy.Client().Get("http://evil.example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	resp, err = http.Get(proxy.URL + "/direct")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("wrong status code of direct request, expected %d, actual %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+func Test_ProxyServer_CloseCancelsTunnels(t *testing.T) {
+	returned := make(chan struct{})
+
+	proxy := NewProxyServer(t,
+		SequenceCalls(
+			Call{Input: Input{Method: http.MethodGet, URL: mustParseURL("https://secure.example.com:443/wait")}},
+		),
+		func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+			<-r.Context().Done()
+			close(returned)
+		},
+	)
+
+	client := proxy.Client()
+	served := make(chan error, 1)
+
+	go func() {
+		resp, err := client.Get("https://secure.example.com/wait")
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		served <- err
+	}()
+
+	for proxy.transport.called() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	proxy.Close()
+
+	select {
+	case <-returned:
+	default:
+		t.Fatal("close returned before the tunneled call")
+	}
+
+	if err := <-served; err == nil {
+		t.Error("expect error of the call cancelled by close")
+	}
+}
+
+func Test_ProxyServer_CleanupCancelsTunneledDelay(t *testing.T) {
+	served := make(chan error, 1)
+	start := time.Now()
+
+	t.Run("delayed call", func(t *testing.T) {
+		proxy := NewProxyServer(t,
+			SequenceCalls(
+				Call{
+					Input: Input{Method: http.MethodGet, URL: mustParseURL("https://secure.example.com:443/slow")},
+					Delay: time.Hour,
+				},
+			),
+			nil,
+		)
+
+		client := proxy.Client()
+
+		go func() {
+			resp, err := client.Get("https://secure.example.com/slow")
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			served <- err
+		}()
+
+		for proxy.transport.called() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	if elapsed := time.Since(start); elapsed >= 5*time.Second {
+		t.Fatalf("cleanup waited for the delay, elapsed %s", elapsed)
+	}
+
+	if err := <-served; err == nil {
+		t.Error("expect error of the call cancelled at cleanup")
+	}
+}
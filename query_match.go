@@ -0,0 +1,159 @@
+package httpmock
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValueMatcher matches all values of a query key, String describes the
+// expected values in failure messages.
+type ValueMatcher interface {
+	Match(values []string) bool
+	String() string
+}
+
+// QueryMatch matches query values per key for values which can not be
+// compared exactly, like timestamps, signatures and cursors.
+//
+//	Input{QueryMatch: httpmock.QueryMatch{"page": httpmock.Any(), "since": httpmock.TimeWithin(time.Hour)}}
+type QueryMatch map[string]ValueMatcher
+
+type valueMatcher struct {
+	match       func(values []string) bool
+	description string
+}
+
+func (v valueMatcher) Match(values []string) bool {
+	return v.match(values)
+}
+
+func (v valueMatcher) String() string {
+	return v.description
+}
+
+// Any matches a present key with any values.
+func Any() ValueMatcher {
+	return valueMatcher{
+		match:       func(values []string) bool { return len(values) > 0 },
+		description: "any value",
+	}
+}
+
+// Absent matches a missing key.
+func Absent() ValueMatcher {
+	return valueMatcher{
+		match:       func(values []string) bool { return len(values) == 0 },
+		description: "no value",
+	}
+}
+
+// Equal matches exactly the values in order.
+func Equal(values ...string) ValueMatcher {
+	return valueMatcher{
+		match:       func(actual []string) bool { return slices.Equal(actual, values) },
+		description: "[" + strings.Join(values, ",") + "]",
+	}
+}
+
+// Regexp matches a present key whose every value matches expr, it panics
+// when expr is invalid.
+func Regexp(expr string) ValueMatcher {
+	re := regexp.MustCompile(expr)
+
+	return everyValue(re.MatchString, "values matching "+expr)
+}
+
+// Between matches a present key whose every value is a number in the
+// inclusive range.
+func Between(minimum, maximum float64) ValueMatcher {
+	return everyValue(
+		func(value string) bool {
+			number, err := strconv.ParseFloat(value, 64)
+
+			return err == nil && number >= minimum && number <= maximum
+		},
+		fmt.Sprintf("numbers between %g and %g", minimum, maximum),
+	)
+}
+
+// TimeWithin matches a present key whose every value is a time within d of
+// now, RFC 3339 times and unix seconds are accepted.
+func TimeWithin(d time.Duration) ValueMatcher {
+	return everyValue(
+		func(value string) bool {
+			tm, ok := parseQueryTime(value)
+			if !ok {
+				return false
+			}
+
+			return time.Since(tm).Abs() <= d
+		},
+		fmt.Sprintf("times within %s of now", d),
+	)
+}
+
+func everyValue(match func(value string) bool, description string) ValueMatcher {
+	return valueMatcher{
+		match: func(values []string) bool {
+			if len(values) == 0 {
+				return false
+			}
+
+			for _, value := range values {
+				if !match(value) {
+					return false
+				}
+			}
+
+			return true
+		},
+		description: description,
+	}
+}
+
+func parseQueryTime(value string) (time.Time, bool) {
+	tm, err := time.Parse(time.RFC3339Nano, value)
+	if err == nil {
+		return tm, true
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		return time.Unix(seconds, 0), true
+	}
+
+	return time.Time{}, false
+}
+
+// CompareQueryMatch reports keys whose request values do not match.
+func CompareQueryMatch(t TestReporter, requestQuery url.Values, match QueryMatch) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	keys := make([]string, 0, len(match))
+
+	for key := range match {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		values := requestQuery[key]
+
+		if !match[key].Match(values) {
+			t.Errorf(
+				"wrong url query values by key %s, expect %s, actual [%s]",
+				key,
+				match[key].String(),
+				strings.Join(values, ","),
+			)
+		}
+	}
+}
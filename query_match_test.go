@@ -0,0 +1,63 @@
+package httpmock
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_CompareQueryMatch(t *testing.T) {
+	now := time.Now()
+
+	match := QueryMatch{
+		"page":   Any(),
+		"since":  TimeWithin(time.Hour),
+		"until":  TimeWithin(time.Hour),
+		"limit":  Between(1, 100),
+		"cursor": Regexp(`^[a-f0-9]+$`),
+		"debug":  Absent(),
+		"sort":   Equal("name", "id"),
+	}
+
+	t.Run("matched", func(t *testing.T) {
+		query := url.Values{
+			"page":   {"7"},
+			"since":  {now.Add(-time.Minute).Format(time.RFC3339)},
+			"until":  {strconv.FormatInt(now.Unix(), 10)},
+			"limit":  {"50"},
+			"cursor": {"0af3"},
+			"sort":   {"name", "id"},
+		}
+
+		CompareQueryMatch(t, query, match)
+	})
+
+	t.Run("mismatched", func(t *testing.T) {
+		query := url.Values{
+			"since":  {now.Add(-2 * time.Hour).Format(time.RFC3339)},
+			"until":  {"yesterday"},
+			"limit":  {"50", "500"},
+			"cursor": {"XYZ"},
+			"debug":  {"1"},
+			"sort":   {"id", "name"},
+		}
+
+		CompareQueryMatch(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "wrong url query values by key %s, expect %s, actual [%s]", args: []any{"cursor", "values matching ^[a-f0-9]+$", "XYZ"}},
+					{format: "wrong url query values by key %s, expect %s, actual [%s]", args: []any{"debug", "no value", "1"}},
+					{format: "wrong url query values by key %s, expect %s, actual [%s]", args: []any{"limit", "numbers between 1 and 100", "50,500"}},
+					{format: "wrong url query values by key %s, expect %s, actual [%s]", args: []any{"page", "any value", ""}},
+					{format: "wrong url query values by key %s, expect %s, actual [%s]", args: []any{"since", "times within 1h0m0s of now", query.Get("since")}},
+					{format: "wrong url query values by key %s, expect %s, actual [%s]", args: []any{"sort", "[name,id]", "id,name"}},
+					{format: "wrong url query values by key %s, expect %s, actual [%s]", args: []any{"until", "times within 1h0m0s of now", "yesterday"}},
+				},
+				nil,
+			)(t),
+			query,
+			match,
+		)
+	})
+}
@@ -0,0 +1,83 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// readWithinBody reports the client closing the response body later than
+// within after the response was returned.
+type readWithinBody struct {
+	io.ReadCloser
+	t      TestReporter
+	start  time.Time
+	within time.Duration
+	once   sync.Once
+}
+
+func newReadWithinBody(t TestReporter, body io.ReadCloser, within time.Duration) io.ReadCloser {
+	return &readWithinBody{
+		ReadCloser: body,
+		t:          t,
+		start:      time.Now(),
+		within:     within,
+	}
+}
+
+func (b *readWithinBody) Close() error {
+	b.once.Do(func() {
+		compareReadWithin(b.t, time.Since(b.start), b.within)
+	})
+
+	return b.ReadCloser.Close()
+}
+
+func compareReadWithin(t TestReporter, elapsed, within time.Duration) {
+	if elapsed > within {
+		t.Errorf("response read in %s, expected within %s", elapsed, within)
+	}
+}
+
+// readWithinWriter records when the response write started, the server
+// measures until the handler wrote and flushed the whole response.
+type readWithinWriter struct {
+	http.ResponseWriter
+	start time.Time
+}
+
+func (w *readWithinWriter) started() {
+	if w.start.IsZero() {
+		w.start = time.Now()
+	}
+}
+
+func (w *readWithinWriter) WriteHeader(statusCode int) {
+	w.started()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *readWithinWriter) Write(p []byte) (int, error) {
+	w.started()
+
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *readWithinWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *readWithinWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *readWithinWriter) compare(t TestReporter, within time.Duration) {
+	w.Flush()
+
+	if !w.start.IsZero() {
+		compareReadWithin(t, time.Since(w.start), within)
+	}
+}
@@ -0,0 +1,79 @@
+package httpmock
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_Call_ReadWithin(t *testing.T) {
+	call := Call{
+		Input:      Input{Method: http.MethodGet},
+		Response:   Response{StatusCode: http.StatusOK, Body: RawBody("items")},
+		ReadWithin: 20 * time.Millisecond,
+	}
+
+	t.Run("read in time", func(t *testing.T) {
+		transport := NewTransport(t, SequenceCalls(call), nil)
+
+		resp, err := (&http.Client{Transport: transport}).Get("http://localhost/items")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		reporter := &testReporterMock{t: t}
+
+		transport := NewTransport(reporter, SequenceCalls(call), nil)
+
+		resp, err := (&http.Client{Transport: transport}).Get("http://localhost/items")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(40 * time.Millisecond)
+
+		resp.Body.Close()
+
+		if len(reporter.errorfCalls) != 1 || reporter.errorfCalls[0].format != "1 call, response read in %s, expected within %s" {
+			t.Fatalf("stalled read not reported, errorf calls %v", reporter.errorfCalls)
+		}
+	})
+}
+
+func Test_Server_ReadWithin(t *testing.T) {
+	reporter := &testReporterMock{}
+
+	t.Run("stalled", func(t *testing.T) {
+		reporter.t = t
+
+		server := NewServer(reporter,
+			SequenceCalls(Call{
+				Input:      Input{Method: http.MethodGet},
+				Response:   Response{StatusCode: http.StatusOK, Body: RawBody(bytes.Repeat([]byte("a"), 32<<20))},
+				ReadWithin: 50 * time.Millisecond,
+			}),
+			nil,
+		)
+
+		resp, err := server.Client().Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(150 * time.Millisecond)
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	})
+
+	if len(reporter.errorfCalls) != 1 || reporter.errorfCalls[0].format != "1 call, response read in %s, expected within %s" {
+		t.Fatalf("stalled read not reported, errorf calls %v", reporter.errorfCalls)
+	}
+}
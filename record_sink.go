@@ -0,0 +1,84 @@
+package httpmock
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RecordSinkFunc records exchanges with a func, e.g. into custom storage.
+type RecordSinkFunc func(exchange Exchange) error
+
+func (f RecordSinkFunc) Record(exchange Exchange) error {
+	return f(exchange)
+}
+
+// FileSink appends every exchange to the file as a JSON line, the file is
+// created on the first exchange. Cassette is the in-memory sink.
+type FileSink struct {
+	path string
+
+	mu sync.Mutex
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (f *FileSink) Record(exchange Exchange) error {
+	data, err := json.Marshal(exchange)
+	if err != nil {
+		return fmt.Errorf("marshal exchange, %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open file sink, %w", err)
+	}
+
+	_, err = file.Write(append(data, '\n'))
+	if err != nil {
+		file.Close()
+
+		return fmt.Errorf("write file sink, %w", err)
+	}
+
+	return file.Close()
+}
+
+// LoadFileSink reads exchanges written by FileSink into a cassette.
+func LoadFileSink(path string) (*Cassette, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file sink, %w", err)
+	}
+	defer file.Close()
+
+	cassette := &Cassette{}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		var exchange Exchange
+
+		err = json.Unmarshal(scanner.Bytes(), &exchange)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal file sink %s line %d, %w", path, line, err)
+		}
+
+		cassette.exchanges = append(cassette.exchanges, exchange)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("read file sink %s, %w", path, err)
+	}
+
+	return cassette, nil
+}
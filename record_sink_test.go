@@ -0,0 +1,50 @@
+package httpmock
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_FileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exchanges.jsonl")
+
+	sink := NewFileSink(path)
+
+	exchanges := []Exchange{
+		{
+			Request:  RecordedRequest{Method: "GET", URL: "http://localhost/items"},
+			Response: RecordedResponse{StatusCode: 200, Body: RecordedBody("items")},
+		},
+		{
+			Request:  RecordedRequest{Method: "POST", URL: "http://localhost/items", Body: RecordedBody{0xff, 0x00}},
+			Response: RecordedResponse{StatusCode: 201},
+		},
+	}
+
+	for _, exchange := range exchanges {
+		err := sink.Record(exchange)
+		if err != nil {
+			t.Fatalf("record exchange, unexpected error: %s", err)
+		}
+	}
+
+	cassette, err := LoadFileSink(path)
+	if err != nil {
+		t.Fatalf("load file sink, unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(cassette.Exchanges(), exchanges) {
+		t.Errorf("wrong loaded exchanges, expected %+v, actual %+v", exchanges, cassette.Exchanges())
+	}
+}
+
+func Test_RecordSinkFunc(t *testing.T) {
+	errSink := errors.New("sink is full")
+
+	err := RecordSinkFunc(func(Exchange) error { return errSink }).Record(Exchange{})
+	if !errors.Is(err, errSink) {
+		t.Errorf("wrong error, expected %s, actual %v", errSink, err)
+	}
+}
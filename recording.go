@@ -0,0 +1,92 @@
+package httpmock
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+)
+
+// RecordSink persists exchanges of recording transports and WithHistory,
+// Cassette, FileSink and RecordSinkFunc implement it. Implementations must
+// be safe for concurrent use.
+type RecordSink interface {
+	Record(exchange Exchange) error
+}
+
+type recordingTransport struct {
+	base http.RoundTripper
+	sink RecordSink
+}
+
+// NewRecordingTransport sends requests to base and records every exchange
+// into the sink, base defaults to http.DefaultTransport.
+func NewRecordingTransport(base http.RoundTripper, sink RecordSink) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &recordingTransport{
+		base: base,
+		sink: sink,
+	}
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+
+	requestBody, err := readAndRestore(&r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body, %w", err)
+	}
+
+	resp, err := rt.base.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := readAndRestore(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body, %w", err)
+	}
+
+	err = rt.sink.Record(
+		Exchange{
+			Request: RecordedRequest{
+				Method: r.Method,
+				URL:    r.URL.String(),
+				Header: cloneNonEmptyHeader(r.Header),
+				Body:   requestBody,
+			},
+			Response: RecordedResponse{
+				StatusCode: resp.StatusCode,
+				Header:     cloneNonEmptyHeader(resp.Header),
+				Body:       responseBody,
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("record exchange, %w", err)
+	}
+
+	return resp, nil
+}
+
+// NewRecordingHandler proxies every request to target and records the
+// exchanges into the sink.
+func NewRecordingHandler(target *url.URL, sink RecordSink) http.Handler {
+	return &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+		},
+		Transport: NewRecordingTransport(http.DefaultTransport, sink),
+	}
+}
+
+// NewRecordingServer starts a server proxying to target and recording every
+// exchange, for code which takes a base url instead of an http.Client.
+// The caller must Close the server.
+func NewRecordingServer(target *url.URL, sink RecordSink) *httptest.Server {
+	return httptest.NewServer(NewRecordingHandler(target, sink))
+}
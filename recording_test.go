@@ -0,0 +1,97 @@
+package httpmock
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func Test_NewRecordingServer(t *testing.T) {
+	upstream := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			w.Header().Set("X-Upstream-Host", r.Host)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(append([]byte("created "), body...))
+		}),
+	)
+	defer upstream.Close()
+
+	target, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cassette := &Cassette{}
+
+	server := NewRecordingServer(target, cassette)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/users?id=1", "text/plain", strings.NewReader("dima"))
+	if err != nil {
+		t.Fatalf("post through recording server, unexpected error: %s", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "created dima" {
+		t.Errorf("wrong proxied body, expected %s, actual %s", "created dima", body)
+	}
+
+	if host := resp.Header.Get("X-Upstream-Host"); host != target.Host {
+		t.Errorf("wrong upstream host, expected %s, actual %s", target.Host, host)
+	}
+
+	exchanges := cassette.Exchanges()
+	if len(exchanges) != 1 {
+		t.Fatalf("wrong recorded exchanges count, expected 1, actual %d", len(exchanges))
+	}
+
+	exchange := exchanges[0]
+
+	if exchange.Request.Method != http.MethodPost || exchange.Request.URL != upstream.URL+"/users?id=1" {
+		t.Errorf("wrong recorded request %s %s", exchange.Request.Method, exchange.Request.URL)
+	}
+
+	if string(exchange.Request.Body) != "dima" || string(exchange.Response.Body) != "created dima" {
+		t.Errorf("wrong recorded bodies, request %s, response %s", exchange.Request.Body, exchange.Response.Body)
+	}
+
+	if exchange.Response.StatusCode != http.StatusCreated {
+		t.Errorf("wrong recorded status code, expected %d, actual %d", http.StatusCreated, exchange.Response.StatusCode)
+	}
+}
+
+type recordSinkFunc func(exchange Exchange) error
+
+func (f recordSinkFunc) Record(exchange Exchange) error {
+	return f(exchange)
+}
+
+func Test_RecordingTransport_SinkError(t *testing.T) {
+	errSinkClosed := errors.New("sink closed")
+
+	client := &http.Client{
+		Transport: NewRecordingTransport(
+			NewHandlerTransport(http.NotFoundHandler()),
+			recordSinkFunc(func(Exchange) error { return errSinkClosed }),
+		),
+	}
+
+	err := doExpectError(
+		request{
+			method: http.MethodGet,
+			target: "http://localhost/",
+		},
+		errSinkClosed,
+	)(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
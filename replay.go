@@ -0,0 +1,220 @@
+package httpmock
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReplayRule adjusts a call converted from a recorded exchange, rules make
+// recorded fixtures survive volatile values like dates, request ids and
+// signatures.
+type ReplayRule func(call *Call)
+
+// IgnoreQuery excludes query keys from the url comparison.
+func IgnoreQuery(keys ...string) ReplayRule {
+	return func(call *Call) {
+		if call.Input.URL == nil {
+			return
+		}
+
+		u := *call.Input.URL
+		query := u.Query()
+
+		for _, key := range keys {
+			query.Del(key)
+		}
+
+		u.RawQuery = query.Encode()
+		call.Input.URL = &u
+	}
+}
+
+// IgnoreJSONFields removes fields addressed by JSON pointers ("/meta/ts")
+// from both recorded and actual request bodies before comparison, "*" token
+// addresses every array item ("/items/*/id").
+func IgnoreJSONFields(pointers ...string) ReplayRule {
+	return normalizeInputBody(func(body []byte) []byte {
+		return removeJSONFields(body, pointers)
+	})
+}
+
+// NormalizeBody replaces pattern matches with replacement in both recorded
+// and actual request bodies before comparison.
+func NormalizeBody(pattern *regexp.Regexp, replacement string) ReplayRule {
+	return normalizeInputBody(func(body []byte) []byte {
+		return pattern.ReplaceAll(body, []byte(replacement))
+	})
+}
+
+// ReplaceResponseHeader serves the header rendered from template, see
+// TemplateBody, instead of the recorded value.
+func ReplaceResponseHeader(key, template string) ReplayRule {
+	return func(call *Call) {
+		if call.Response.TemplateHeader == nil {
+			call.Response.TemplateHeader = make(http.Header)
+		}
+
+		call.Response.TemplateHeader.Set(key, template)
+	}
+}
+
+// ReplaceResponseBody replaces pattern matches in the served body with
+// template rendered for the request.
+func ReplaceResponseBody(pattern *regexp.Regexp, template string) ReplayRule {
+	return func(call *Call) {
+		call.Response.Body = replacingBody{
+			body:     call.Response.Body,
+			pattern:  pattern,
+			template: template,
+		}
+	}
+}
+
+func normalizeInputBody(normalize func([]byte) []byte) ReplayRule {
+	return func(call *Call) {
+		body, ok := call.Input.Body.(normalizedBody)
+		if !ok {
+			body = normalizedBody{body: call.Input.Body}
+		}
+
+		body.normalizers = append(slices.Clip(body.normalizers), normalize)
+		call.Input.Body = body
+	}
+}
+
+type normalizedBody struct {
+	body        Body
+	normalizers []func([]byte) []byte
+}
+
+func (n normalizedBody) Bytes() ([]byte, error) {
+	if n.body == nil {
+		return nil, nil
+	}
+
+	return n.body.Bytes()
+}
+
+func (n normalizedBody) CompareBody(t TestReporter, body []byte) {
+	expected, err := n.Bytes()
+	if err != nil {
+		t.Errorf("read input body, %s", err)
+
+		return
+	}
+
+	for _, normalize := range n.normalizers {
+		expected = normalize(expected)
+		body = normalize(body)
+	}
+
+	if !slices.Equal(expected, body) {
+		t.Errorf("normalized body not equal, expected %s actual %s", string(expected), string(body))
+	}
+}
+
+type replacingBody struct {
+	body     Body
+	pattern  *regexp.Regexp
+	template string
+}
+
+func (b replacingBody) Bytes() ([]byte, error) {
+	return b.RequestBytes(nil)
+}
+
+func (b replacingBody) RequestBytes(r *http.Request) ([]byte, error) {
+	var (
+		body []byte
+		err  error
+	)
+
+	switch inner := b.body.(type) {
+	case nil:
+	case RequestBody:
+		body, err = inner.RequestBytes(r)
+	default:
+		body, err = inner.Bytes()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	replacement, err := renderTemplate(b.template, &templateContext{request: r})
+	if err != nil {
+		return nil, err
+	}
+
+	return b.pattern.ReplaceAllLiteral(body, []byte(replacement)), nil
+}
+
+func removeJSONFields(body []byte, pointers []string) []byte {
+	var value any
+
+	err := json.Unmarshal(body, &value)
+	if err != nil {
+		return body
+	}
+
+	for _, pointer := range pointers {
+		value = removeJSONPointer(value, strings.Split(strings.TrimPrefix(pointer, "/"), "/"))
+	}
+
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+
+	return normalized
+}
+
+func removeJSONPointer(value any, tokens []string) any {
+	token := unescapeJSONPointer(tokens[0])
+
+	switch value := value.(type) {
+	case map[string]any:
+		if len(tokens) == 1 {
+			delete(value, token)
+
+			return value
+		}
+
+		if child, ok := value[token]; ok {
+			value[token] = removeJSONPointer(child, tokens[1:])
+		}
+
+		return value
+	case []any:
+		if token == "*" {
+			if len(tokens) == 1 {
+				return value[:0]
+			}
+
+			for i := range value {
+				value[i] = removeJSONPointer(value[i], tokens[1:])
+			}
+
+			return value
+		}
+
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index >= len(value) {
+			return value
+		}
+
+		if len(tokens) == 1 {
+			return slices.Delete(value, index, index+1)
+		}
+
+		value[index] = removeJSONPointer(value[index], tokens[1:])
+
+		return value
+	default:
+		return value
+	}
+}
@@ -0,0 +1,173 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test_Cassette_Calls_ReplayRules(t *testing.T) {
+	cassette := &Cassette{}
+
+	cassette.Record(
+		Exchange{
+			Request: RecordedRequest{
+				Method: http.MethodPost,
+				URL:    "http://api.example.com/orders?ts=1700000000&sig=abc&user=1",
+				Body:   RecordedBody(`{"id":"ord-1","items":[{"sku":"a","nonce":1}],"created":"2024-01-01T00:00:00Z"}`),
+			},
+			Response: RecordedResponse{
+				StatusCode: http.StatusCreated,
+				Header: http.Header{
+					"X-Request-Id": {"recorded-id"},
+					"Content-Type": {"application/json"},
+				},
+				Body: RecordedBody(`{"id":"ord-1","request":"recorded-id"}`),
+			},
+		},
+	)
+
+	calls, err := cassette.Calls(
+		IgnoreQuery("ts", "sig"),
+		IgnoreJSONFields("/id", "/items/*/nonce"),
+		NormalizeBody(regexp.MustCompile(`\d{4}-\d{2}-\d{2}T[\d:]+Z`), "<time>"),
+		ReplaceResponseHeader("X-Request-Id", "{{request.headers.X-Request-Id}}"),
+		ReplaceResponseBody(regexp.MustCompile(`recorded-id`), "{{request.headers.X-Request-Id}}"),
+	)
+	if err != nil {
+		t.Fatalf("convert cassette, unexpected error: %s", err)
+	}
+
+	requestHeader := make(http.Header)
+	requestHeader.Set("X-Request-Id", "run-2")
+
+	responseHeader := make(http.Header)
+	responseHeader.Set("X-Request-Id", "run-2")
+	responseHeader.Set("Content-Type", "application/json")
+
+	runTransportTests(t,
+		&transportTest{
+			Name:         "volatile fields ignored in matching and replaced in response",
+			TestReporter: ExpectSuccessTestReporter,
+			Calls:        SequenceCalls(calls...),
+			Execute: do(
+				request{
+					method: http.MethodPost,
+					target: "/orders?ts=1800000000&sig=zzz&user=1",
+					body:   strings.NewReader(`{"id":"ord-2","items":[{"sku":"a","nonce":7}],"created":"2025-05-05T10:11:12Z"}`),
+					header: requestHeader,
+				},
+				Response{
+					StatusCode: http.StatusCreated,
+					Header:     responseHeader,
+					Body:       RawBody(`{"id":"ord-1","request":"run-2"}`),
+				},
+			),
+		},
+	)
+
+	runTransportTests(t,
+		&transportTest{
+			Name: "non volatile fields still compared",
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "1 call, wrong url query values by key %s, expect [%s], actual [%s]",
+						args:   []any{"user", "1", "2"},
+					},
+					{
+						format: "1 call, normalized body not equal, expected %s actual %s",
+						args: []any{
+							`{"created":"<time>","items":[{"sku":"a"}]}`,
+							`{"created":"<time>","items":[{"sku":"b"}]}`,
+						},
+					},
+				},
+				nil,
+			),
+			Calls: SequenceCalls(calls...),
+			Execute: doUncheckedResponse(
+				request{
+					method: http.MethodPost,
+					target: "/orders?user=2",
+					body:   strings.NewReader(`{"id":"ord-3","items":[{"sku":"b","nonce":2}],"created":"2025-05-05T10:11:12Z"}`),
+					header: requestHeader,
+				},
+			),
+		},
+	)
+}
+
+func Test_Response_TemplateHeader(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/users/7", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := RenderResponse(r, Response{
+		Header:         http.Header{"Location": {"/old"}, "X-Static": {"1"}},
+		TemplateHeader: http.Header{"Location": {"{{request.path}}/profile"}},
+	})
+	if err != nil {
+		t.Fatalf("render response, unexpected error: %s", err)
+	}
+
+	if location := response.Header.Get("Location"); location != "/users/7/profile" {
+		t.Errorf("wrong Location, expected /users/7/profile, actual %s", location)
+	}
+
+	if static := response.Header.Get("X-Static"); static != "1" {
+		t.Errorf("wrong X-Static, expected 1, actual %s", static)
+	}
+
+	if response.TemplateHeader != nil {
+		t.Errorf("rendered response keeps template header %v", response.TemplateHeader)
+	}
+}
+
+func Test_Cassette_Calls_ServerReplayChangedLength(t *testing.T) {
+	cassette := &Cassette{}
+
+	cassette.Record(Exchange{
+		Request: RecordedRequest{Method: http.MethodGet, URL: "http://api.example.com/items/1"},
+		Response: RecordedResponse{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type":   {"application/json"},
+				"Content-Length": {"10"},
+				"Connection":     {"keep-alive"},
+			},
+			Body: RecordedBody(`{"id":"X"}`),
+		},
+	})
+
+	calls, err := cassette.Calls(ReplaceResponseBody(regexp.MustCompile("X"), "a-much-longer-value"))
+	if err != nil {
+		t.Fatalf("convert cassette, unexpected error: %s", err)
+	}
+
+	server := NewServer(t, SequenceCalls(calls...), nil)
+
+	resp, err := http.Get(server.URL + "/items/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read replayed body, unexpected error: %s", err)
+	}
+
+	const expected = `{"id":"a-much-longer-value"}`
+	if string(body) != expected {
+		t.Errorf("wrong body, expected %s, actual %s", expected, body)
+	}
+
+	if resp.ContentLength != int64(len(expected)) {
+		t.Errorf("wrong content length, expected %d, actual %d", len(expected), resp.ContentLength)
+	}
+}
@@ -0,0 +1,88 @@
+package httpmock
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// WithRequestBodyCheck makes NewTransport report requests with a body but
+// without GetBody, which http.Client cannot retry or redirect, and request
+// bodies shorter than their ContentLength, usually read before or reused
+// from a previous request without rewinding. GetBody must replay the bytes
+// the body yielded, as net/http retries requests with it.
+func WithRequestBodyCheck() Option {
+	return func(o *options) {
+		o.requestBodyCheck = true
+	}
+}
+
+type countingBody struct {
+	io.ReadCloser
+	read int64
+	data *bytes.Buffer
+}
+
+func (c *countingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.read += int64(n)
+
+	if c.data != nil {
+		c.data.Write(p[:n])
+	}
+
+	return n, err
+}
+
+// checkRequestBody returns a shallow copy of r counting read bytes, check
+// is called when the call is served.
+func checkRequestBody(t TestReporter, r *http.Request) (_ *http.Request, check func()) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return r, func() {}
+	}
+
+	if r.GetBody == nil {
+		t.Errorf("request body without GetBody, request cannot be retried")
+	}
+
+	body := &countingBody{ReadCloser: r.Body, data: new(bytes.Buffer)}
+
+	r = r.WithContext(r.Context())
+	r.Body = body
+
+	return r, func() {
+		_, _ = io.Copy(io.Discard, body)
+
+		if r.ContentLength > 0 && body.read != r.ContentLength {
+			t.Errorf("request body already read, expected %d bytes, actual %d", r.ContentLength, body.read)
+
+			return
+		}
+
+		if r.GetBody != nil {
+			compareGetBody(t, r.GetBody, body.data.Bytes())
+		}
+	}
+}
+
+func compareGetBody(t TestReporter, getBody func() (io.ReadCloser, error), sent []byte) {
+	replay, err := getBody()
+	if err != nil {
+		t.Errorf("request GetBody, %s", err)
+
+		return
+	}
+
+	defer replay.Close()
+
+	replayed, err := io.ReadAll(replay)
+	if err != nil {
+		withFailureKind(t, ErrBodyRead).Errorf("read request GetBody, %s", err)
+
+		return
+	}
+
+	if !bytes.Equal(replayed, sent) {
+		t.Errorf("request GetBody replays a different body, expected %s, actual %s", sent, replayed)
+	}
+}
@@ -0,0 +1,106 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_WithRequestBodyCheck(t *testing.T) {
+	t.Run("rewindable body", func(t *testing.T) {
+		transport := NewTransport(t,
+			SequenceCalls(Call{Input: Input{Method: http.MethodPost, Body: RawBody("data")}}),
+			nil,
+			WithRequestBodyCheck(),
+		)
+
+		resp, err := (&http.Client{Transport: transport}).Post("http://localhost/items", "", strings.NewReader("data"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+
+	t.Run("body without GetBody", func(t *testing.T) {
+		transport := NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "1 call, request body without GetBody, request cannot be retried"},
+				},
+				nil,
+			)(t),
+			SequenceCalls(Call{Input: Input{Method: http.MethodPost, Body: RawBody("data")}}),
+			nil,
+			WithRequestBodyCheck(),
+		)
+
+		body := io.NopCloser(strings.NewReader("data"))
+
+		resp, err := (&http.Client{Transport: transport}).Post("http://localhost/items", "", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+
+	t.Run("reused request body", func(t *testing.T) {
+		transport := NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "2 call, body not equal, expected %s actual %s", args: []any{"data", ""}},
+					{format: "2 call, request body already read, expected %d bytes, actual %d", args: []any{int64(4), int64(0)}},
+				},
+				nil,
+			)(t),
+			StaticCalls(Call{Input: Input{Method: http.MethodPost, Body: RawBody("data")}}),
+			nil,
+			WithRequestBodyCheck(),
+		)
+
+		req, err := http.NewRequest(http.MethodPost, "http://localhost/items", strings.NewReader("data"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for range 2 {
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp.Body.Close()
+		}
+	})
+	t.Run("broken GetBody", func(t *testing.T) {
+		transport := NewTransport(
+			ExpectFailureTestReporter(
+				[]testReporterCall{
+					{format: "1 call, request GetBody replays a different body, expected %s, actual %s", args: []any{[]byte("data"), []byte("dat")}},
+				},
+				nil,
+			)(t),
+			SequenceCalls(Call{Input: Input{Method: http.MethodPost, Body: RawBody("data")}}),
+			nil,
+			WithRequestBodyCheck(),
+		)
+
+		req, err := http.NewRequest(http.MethodPost, "http://localhost/items", strings.NewReader("data"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("dat")), nil
+		}
+
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+}
@@ -0,0 +1,15 @@
+package httpmock
+
+import "net/http"
+
+// RequestVerifier checks every request the transport serves before the
+// call is handled, e.g. its signature, and reports failures to t. It may
+// read the body when it restores r.Body.
+type RequestVerifier func(t TestReporter, r *http.Request)
+
+// WithRequestVerifier runs the verifiers on every request, see SigV4.
+func WithRequestVerifier(verifiers ...RequestVerifier) Option {
+	return func(o *options) {
+		o.verifiers = append(o.verifiers, verifiers...)
+	}
+}
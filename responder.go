@@ -0,0 +1,227 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNoResponderFound is returned by ResponderTransport for requests without
+// a registered responder.
+var ErrNoResponderFound = errors.New("no responder found")
+
+// Responder returns the response for the request like
+// jarcoal/httpmock responders do.
+type Responder func(r *http.Request) (*http.Response, error)
+
+// ResponderTransport keeps jarcoal/httpmock RegisterResponder semantics for
+// migrating suites on top of NewTransport: responders are registered by
+// method and url, served any number of times and counted. A url is matched
+// with its query first, then without it, urls without a scheme and host
+// match by path, methods are matched case-insensitively.
+//
+// Requests without a responder fail the test and return an error wrapping
+// ErrNoResponderFound unless RegisterNoResponder set one. The options apply
+// as for NewTransport, e.g. WithTranscript, and so do its Cleanup checks.
+type ResponderTransport struct {
+	t         TestReporter
+	transport http.RoundTripper
+
+	mu          sync.Mutex
+	responders  map[string]Responder
+	noResponder Responder
+	counts      map[string]int
+	total       int
+}
+
+func NewResponderTransport(t TestReporter, opts ...Option) *ResponderTransport {
+	rt := &ResponderTransport{
+		t:          t,
+		responders: make(map[string]Responder),
+		counts:     make(map[string]int),
+	}
+
+	rt.transport = NewTransport(t, CallsFunc(0, rt.call), RespondCall, opts...)
+
+	return rt
+}
+
+// RegisterResponder registers the responder for the method and url.
+func (rt *ResponderTransport) RegisterResponder(method, url string, responder Responder) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	key := responderKey(method, url)
+
+	rt.responders[key] = responder
+	rt.counts[key] = 0
+}
+
+// RegisterNoResponder registers the responder for requests without a
+// registered one, nil restores ErrNoResponderFound.
+func (rt *ResponderTransport) RegisterNoResponder(responder Responder) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.noResponder = responder
+}
+
+// GetCallCountInfo returns calls count per registered "METHOD url" key.
+func (rt *ResponderTransport) GetCallCountInfo() map[string]int {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	counts := make(map[string]int, len(rt.counts))
+	for key, count := range rt.counts {
+		counts[key] = count
+	}
+
+	return counts
+}
+
+// GetTotalCallCount returns the count of all requests.
+func (rt *ResponderTransport) GetTotalCallCount() int {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	return rt.total
+}
+
+// Reset removes responders and counters.
+func (rt *ResponderTransport) Reset() {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.responders = make(map[string]Responder)
+	rt.counts = make(map[string]int)
+	rt.noResponder = nil
+	rt.total = 0
+}
+
+func (rt *ResponderTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return rt.transport.RoundTrip(r)
+}
+
+// call runs the responder of the request and serves its response as the
+// call, responder errors are returned by RoundTrip.
+func (rt *ResponderTransport) call(calledTimes int, r *http.Request) (Call, bool) {
+	if r == nil {
+		return Call{}, false
+	}
+
+	responder := rt.responder(r)
+	if responder == nil {
+		err := fmt.Errorf("%w for %s %s", ErrNoResponderFound, r.Method, r.URL)
+
+		errorfTestReporterWithCallNumber(rt.t, int64(calledTimes)).Errorf("%s", err.Error())
+
+		return Call{DoError: err}, true
+	}
+
+	resp, err := responder(r)
+	if err != nil {
+		return Call{DoError: err}, true
+	}
+
+	return Call{
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			BodyReader: resp.Body,
+		},
+	}, true
+}
+
+func (rt *ResponderTransport) responder(r *http.Request) Responder {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.total++
+
+	withoutQuery := *r.URL
+	withoutQuery.RawQuery = ""
+
+	urls := []string{r.URL.String(), withoutQuery.String()}
+
+	if r.URL.RawQuery != "" {
+		urls = append(urls, r.URL.Path+"?"+r.URL.RawQuery)
+	}
+
+	urls = append(urls, r.URL.Path)
+
+	for _, url := range urls {
+		key := responderKey(r.Method, url)
+
+		if responder, ok := rt.responders[key]; ok {
+			rt.counts[key]++
+
+			return responder
+		}
+	}
+
+	return rt.noResponder
+}
+
+func responderKey(method, url string) string {
+	return strings.ToUpper(method) + " " + url
+}
+
+// Responders returns registered "METHOD url" keys sorted.
+func (rt *ResponderTransport) Responders() []string {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	keys := make([]string, 0, len(rt.responders))
+	for key := range rt.responders {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ResponseResponder serves the response like HandleCallCompareInput does,
+// templates are rendered with the request.
+func ResponseResponder(response Response) Responder {
+	return func(r *http.Request) (*http.Response, error) {
+		rendered, err := RenderResponse(r, response)
+		if err != nil {
+			return nil, err
+		}
+
+		w := newResponseWriter()
+
+		err = WriteResponse(w, rendered)
+		if err != nil {
+			return nil, err
+		}
+
+		return w.result(r), nil
+	}
+}
+
+// NewStringResponder responds with the status code and the body.
+func NewStringResponder(statusCode int, body string) Responder {
+	return ResponseResponder(Response{StatusCode: statusCode, Body: RawBody(body)})
+}
+
+// NewJSONResponder responds with the status code and the JSON encoded body.
+func NewJSONResponder(statusCode int, body any) (Responder, error) {
+	bytes, err := JSONBody(body).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return ResponseResponder(Resp(statusCode).Body(RawBody(bytes)).SetHeader("Content-Type", "application/json").Response()), nil
+}
+
+// NewErrorResponder fails every request with err.
+func NewErrorResponder(err error) Responder {
+	return func(*http.Request) (*http.Response, error) {
+		return nil, err
+	}
+}
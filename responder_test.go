@@ -0,0 +1,95 @@
+package httpmock
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_ResponderTransport(t *testing.T) {
+	rt := NewResponderTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "6 call, %s", args: []any{"no responder found for GET https://api.local/orders"}},
+			},
+			nil,
+		)(t),
+	)
+
+	rt.RegisterResponder(http.MethodGet, "https://api.local/users", NewStringResponder(http.StatusOK, "all"))
+	rt.RegisterResponder(http.MethodGet, "/users?role=admin", NewStringResponder(http.StatusOK, "admins"))
+
+	jsonResponder, err := NewJSONResponder(http.StatusCreated, map[string]int{"id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rt.RegisterResponder("post", "/users", jsonResponder)
+
+	client := &http.Client{Transport: rt}
+
+	tests := []struct {
+		Method       string
+		URL          string
+		ExpectedBody string
+	}{
+		{Method: http.MethodGet, URL: "https://api.local/users", ExpectedBody: "all"},
+		{Method: http.MethodGet, URL: "https://api.local/users?page=2", ExpectedBody: "all"},
+		{Method: http.MethodGet, URL: "https://other.local/users?role=admin", ExpectedBody: "admins"},
+		{Method: http.MethodPost, URL: "https://api.local/users", ExpectedBody: `{"id":1}`},
+		{Method: "Post", URL: "https://api.local/users", ExpectedBody: `{"id":1}`},
+	}
+
+	for _, tst := range tests {
+		req, err := http.NewRequest(tst.Method, tst.URL, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s, unexpected error: %s", tst.Method, tst.URL, err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(body) != tst.ExpectedBody {
+			t.Errorf("%s %s, wrong body, expected %s, actual %s", tst.Method, tst.URL, tst.ExpectedBody, body)
+		}
+	}
+
+	_, err = client.Get("https://api.local/orders")
+	if !errors.Is(err, ErrNoResponderFound) {
+		t.Errorf("wrong error, expected %s, actual %v", ErrNoResponderFound, err)
+	}
+
+	expectedCounts := map[string]int{
+		"GET https://api.local/users": 2,
+		"GET /users?role=admin":       1,
+		"POST /users":                 2,
+	}
+
+	if counts := rt.GetCallCountInfo(); !reflect.DeepEqual(counts, expectedCounts) {
+		t.Errorf("wrong call counts, expected %v, actual %v", expectedCounts, counts)
+	}
+
+	if total := rt.GetTotalCallCount(); total != 6 {
+		t.Errorf("wrong total call count, expected 6, actual %d", total)
+	}
+
+	rt.RegisterNoResponder(NewErrorResponder(io.ErrUnexpectedEOF))
+
+	_, err = client.Get("https://api.local/orders")
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("wrong no responder error, actual %v", err)
+	}
+
+	rt.Reset()
+
+	if keys := rt.Responders(); len(keys) != 0 {
+		t.Errorf("responders not reset, actual %v", keys)
+	}
+}
@@ -0,0 +1,67 @@
+package httpmock
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// WithBodyCloseCheck makes NewTransport report every response body the
+// client left unclosed at Cleanup, not only Response.BodyReader ones.
+// Unclosed bodies exhaust connection pools in production.
+func WithBodyCloseCheck() Option {
+	return func(o *options) {
+		o.bodyCloseCheck = true
+	}
+}
+
+func writeBodyReader(w http.ResponseWriter, body io.ReadCloser) error {
+	defer body.Close()
+
+	_, err := io.Copy(w, body)
+	if err != nil {
+		return fmt.Errorf("write response body reader, unexpected error: %w", err)
+	}
+
+	return nil
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	calledTimes int64
+	closed      atomic.Bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed.Store(true)
+
+	return b.ReadCloser.Close()
+}
+
+type responseBodies struct {
+	mu     sync.Mutex
+	bodies []*trackedBody
+}
+
+func (rb *responseBodies) track(calledTimes int64, body io.ReadCloser) io.ReadCloser {
+	tracked := &trackedBody{ReadCloser: body, calledTimes: calledTimes}
+
+	rb.mu.Lock()
+	rb.bodies = append(rb.bodies, tracked)
+	rb.mu.Unlock()
+
+	return tracked
+}
+
+func (rb *responseBodies) assert(t TestReporter) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	for _, body := range rb.bodies {
+		if !body.closed.Load() {
+			errorfTestReporterWithCallNumber(t, body.calledTimes).Errorf("response body not closed")
+		}
+	}
+}
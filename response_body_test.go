@@ -0,0 +1,97 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+
+	return nil
+}
+
+func Test_Response_BodyReader(t *testing.T) {
+	reader := &closeRecorder{Reader: strings.NewReader("streamed")}
+
+	transport := NewTransport(t,
+		SequenceCalls(Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{BodyReader: reader},
+		}),
+		nil,
+	)
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://localhost/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if string(body) != "streamed" {
+		t.Errorf("wrong body, expected streamed, actual %s", body)
+	}
+
+	if !reader.closed {
+		t.Errorf("body reader not closed")
+	}
+}
+
+func Test_Response_BodyReader_NotClosed(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "1 call, response body not closed"},
+			},
+			nil,
+		)(t),
+		SequenceCalls(Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{BodyReader: io.NopCloser(strings.NewReader("leak"))},
+		}),
+		nil,
+	)
+
+	_, err := (&http.Client{Transport: transport}).Get("http://localhost/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_WithBodyCloseCheck(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "2 call, response body not closed"},
+			},
+			nil,
+		)(t),
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}}),
+		nil,
+		WithBodyCloseCheck(),
+	)
+
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	_, err = client.Get("http://localhost/items")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,104 @@
+package httpmock
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ResponseBuilder assembles a Response step by step, every method returns
+// a new builder so partially built responses can be shared.
+type ResponseBuilder struct {
+	response Response
+}
+
+// Resp starts a response with the status code.
+//
+//	httpmock.Resp(http.StatusOK).JSON(user).Header("X-Request-Id", "{{request.headers.X-Request-Id}}").Response()
+func Resp(statusCode int) ResponseBuilder {
+	return ResponseBuilder{
+		response: Response{
+			StatusCode: statusCode,
+		},
+	}
+}
+
+// Body sets the response body.
+func (b ResponseBuilder) Body(body Body) ResponseBuilder {
+	b.response.Body = body
+
+	return b
+}
+
+// JSON sets the JSON encoded value as body and the application/json content type.
+func (b ResponseBuilder) JSON(value any) ResponseBuilder {
+	return b.Body(JSONBody(value)).SetHeader("Content-Type", "application/json")
+}
+
+// Text sets the text as body and the text/plain content type.
+func (b ResponseBuilder) Text(text string) ResponseBuilder {
+	return b.Body(RawBody(text)).SetHeader("Content-Type", "text/plain; charset=utf-8")
+}
+
+// Header adds the header value, values with {{...}} placeholders are
+// rendered per request, see TemplateBody.
+func (b ResponseBuilder) Header(key, value string) ResponseBuilder {
+	if strings.Contains(value, "{{") {
+		b.response.TemplateHeader = cloneHeader(b.response.TemplateHeader)
+		b.response.TemplateHeader.Add(key, value)
+
+		return b
+	}
+
+	b.response.Header = cloneHeader(b.response.Header)
+	b.response.Header.Add(key, value)
+
+	return b
+}
+
+// SetHeader replaces all values of the header.
+func (b ResponseBuilder) SetHeader(key, value string) ResponseBuilder {
+	b.response.Header = cloneHeader(b.response.Header)
+	b.response.Header.Del(key)
+
+	b.response.TemplateHeader = cloneHeader(b.response.TemplateHeader)
+	b.response.TemplateHeader.Del(key)
+
+	return b.Header(key, value)
+}
+
+// Cookie adds a Set-Cookie header, invalid cookies are dropped like
+// http.SetCookie does.
+func (b ResponseBuilder) Cookie(cookie *http.Cookie) ResponseBuilder {
+	value := cookie.String()
+	if value == "" {
+		return b
+	}
+
+	b.response.Header = cloneHeader(b.response.Header)
+	b.response.Header.Add("Set-Cookie", value)
+
+	return b
+}
+
+// Response returns the built response.
+func (b ResponseBuilder) Response() Response {
+	response := b.response
+
+	response.Header = cloneHeader(response.Header)
+
+	if len(response.TemplateHeader) == 0 {
+		response.TemplateHeader = nil
+	} else {
+		response.TemplateHeader = cloneHeader(response.TemplateHeader)
+	}
+
+	return response
+}
+
+func cloneHeader(header http.Header) http.Header {
+	if header == nil {
+		return make(http.Header)
+	}
+
+	return header.Clone()
+}
@@ -0,0 +1,77 @@
+package httpmock
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_ResponseBuilder(t *testing.T) {
+	base := Resp(http.StatusCreated).JSON(map[string]int{"id": 1})
+
+	withCookie := base.
+		Header("X-Tag", "a").
+		Header("X-Tag", "b").
+		Header("X-Request-Id", "{{request.headers.X-Request-Id}}").
+		Cookie(&http.Cookie{Name: "session", Value: "abc", Path: "/"}).
+		Cookie(&http.Cookie{Name: "invalid name"})
+
+	response := withCookie.Response()
+
+	expectedHeader := http.Header{
+		"Content-Type": {"application/json"},
+		"X-Tag":        {"a", "b"},
+		"Set-Cookie":   {"session=abc; Path=/"},
+	}
+
+	if !reflect.DeepEqual(response.Header, expectedHeader) {
+		t.Errorf("wrong header, expected %v, actual %v", expectedHeader, response.Header)
+	}
+
+	expectedTemplateHeader := http.Header{"X-Request-Id": {"{{request.headers.X-Request-Id}}"}}
+
+	if !reflect.DeepEqual(response.TemplateHeader, expectedTemplateHeader) {
+		t.Errorf("wrong template header, expected %v, actual %v", expectedTemplateHeader, response.TemplateHeader)
+	}
+
+	if baseHeader := base.Response().Header; len(baseHeader) != 1 {
+		t.Errorf("base builder modified by derived builder, header %v", baseHeader)
+	}
+
+	text := base.Text("Hello World!").SetHeader("Content-Type", "text/csv").Response()
+
+	if contentType := text.Header.Get("Content-Type"); contentType != "text/csv" {
+		t.Errorf("wrong Content-Type, expected text/csv, actual %s", contentType)
+	}
+
+	requestHeader := make(http.Header)
+	requestHeader.Set("X-Request-Id", "42")
+
+	responseHeader := expectedHeader.Clone()
+	responseHeader.Set("X-Request-Id", "42")
+
+	runTransportTests(t,
+		&transportTest{
+			Name:         "built response served",
+			TestReporter: ExpectSuccessTestReporter,
+			Calls: SequenceCalls(
+				Call{
+					Input:    Input{Method: http.MethodGet},
+					Response: response,
+				},
+			),
+			Execute: do(
+				request{
+					method: http.MethodGet,
+					target: "/users/1",
+					header: requestHeader,
+				},
+				Response{
+					StatusCode: http.StatusCreated,
+					Body:       RawBody(`{"id":1}`),
+					Header:     responseHeader,
+				},
+			),
+		},
+	)
+}
@@ -0,0 +1,88 @@
+package httpmock
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// ResponseInterceptor passes writes to the wrapped ResponseWriter and keeps
+// what was written, so authors of HandleCall functions can assert their
+// write logic. The header is snapshotted on the first WriteHeader or Write,
+// a Write without WriteHeader is recorded as 200.
+type ResponseInterceptor struct {
+	http.ResponseWriter
+
+	statusCode  int
+	header      http.Header
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func InterceptResponse(w http.ResponseWriter) *ResponseInterceptor {
+	return &ResponseInterceptor{ResponseWriter: w}
+}
+
+func (i *ResponseInterceptor) WriteHeader(statusCode int) {
+	if !i.wroteHeader {
+		i.wroteHeader = true
+		i.statusCode = statusCode
+		i.header = i.ResponseWriter.Header().Clone()
+	}
+
+	i.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (i *ResponseInterceptor) Write(p []byte) (int, error) {
+	if !i.wroteHeader {
+		i.WriteHeader(http.StatusOK)
+	}
+
+	n, err := i.ResponseWriter.Write(p)
+	i.body.Write(p[:n])
+
+	return n, err
+}
+
+func (i *ResponseInterceptor) Flush() {
+	if flusher, ok := i.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (i *ResponseInterceptor) Unwrap() http.ResponseWriter {
+	return i.ResponseWriter
+}
+
+// StatusCode returns the written status code, 0 when nothing was written.
+func (i *ResponseInterceptor) StatusCode() int {
+	return i.statusCode
+}
+
+// WrittenHeader returns the header as it was when the status code was
+// written, nil when nothing was written.
+func (i *ResponseInterceptor) WrittenHeader() http.Header {
+	return i.header
+}
+
+// Body returns the written body bytes.
+func (i *ResponseInterceptor) Body() []byte {
+	return i.body.Bytes()
+}
+
+// Compare reports differences between what was written and the response:
+// the status code, 200 when not set, headers of Response.Header and the
+// body compared like CompareBody.
+func (i *ResponseInterceptor) Compare(t TestReporter, response Response) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	CompareStatusCode(t, i.statusCode, statusCode)
+	CompareHeader(t, i.header, response.Header)
+	CompareBody(t, bytes.NewReader(i.body.Bytes()), response.Body)
+}
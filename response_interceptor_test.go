@@ -0,0 +1,69 @@
+package httpmock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_ResponseInterceptor(t *testing.T) {
+	handleCall := func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		written := InterceptResponse(w)
+
+		RespondCall(t, written, r, call)
+
+		written.Compare(t, call.Response)
+	}
+
+	server := NewServer(t,
+		SequenceCalls(Call{
+			Input: Input{Method: http.MethodGet},
+			Response: Response{
+				StatusCode: http.StatusCreated,
+				Header:     http.Header{"X-Id": {"1"}},
+				Body:       RawBody("created"),
+			},
+		}),
+		handleCall,
+	)
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_ResponseInterceptor_Compare(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	written := InterceptResponse(recorder)
+
+	written.Header().Set("X-Id", "2")
+	written.WriteHeader(http.StatusAccepted)
+	written.Header().Set("X-Late", "ignored")
+	_, _ = written.Write([]byte("accepted"))
+
+	if written.StatusCode() != http.StatusAccepted || string(written.Body()) != "accepted" {
+		t.Errorf("wrong intercepted response, %d %s", written.StatusCode(), written.Body())
+	}
+
+	if written.WrittenHeader().Get("X-Late") != "" {
+		t.Errorf("header not snapshotted on WriteHeader")
+	}
+
+	if recorder.Body.String() != "accepted" {
+		t.Errorf("write not passed through, actual %s", recorder.Body)
+	}
+
+	written.Compare(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "wrong response status code, expected %d, actual %d", args: []any{200, 202}},
+				{format: "body not equal, expected %s actual %s", args: []any{"ok", "accepted"}},
+			},
+			nil,
+		)(t),
+		Response{Header: http.Header{"X-Id": {"2"}}, Body: RawBody("ok")},
+	)
+}
@@ -0,0 +1,149 @@
+package httpmock
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// responseWriter buffers what HandleCall writes and builds the *http.Response
+// directly, it follows httptest.ResponseRecorder semantics: the header is
+// snapshotted on WriteHeader, Content-Type is sniffed on the first Write and
+// trailers are declared with the Trailer header or http.TrailerPrefix.
+type responseWriter struct {
+	header      http.Header
+	snapHeader  http.Header
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func newResponseWriter() *responseWriter {
+	return &responseWriter{header: make(http.Header)}
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if statusCode < 100 || statusCode > 999 {
+		panic(fmt.Sprintf("invalid WriteHeader code %v", statusCode))
+	}
+
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	w.statusCode = statusCode
+	w.snapHeader = w.header.Clone()
+}
+
+func (w *responseWriter) writeHeader(b []byte) {
+	if w.wroteHeader {
+		return
+	}
+
+	_, hasType := w.header["Content-Type"]
+	hasTE := w.header.Get("Transfer-Encoding") != ""
+
+	if !hasType && !hasTE {
+		w.header.Set("Content-Type", http.DetectContentType(b))
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.writeHeader(b)
+
+	return w.body.Write(b)
+}
+
+func (w *responseWriter) WriteString(s string) (int, error) {
+	w.writeHeader([]byte(s))
+
+	return w.body.WriteString(s)
+}
+
+func (w *responseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (w *responseWriter) result(r *http.Request) *http.Response {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%03d %s", w.statusCode, http.StatusText(w.statusCode)),
+		StatusCode:    w.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.snapHeader,
+		Body:          io.NopCloser(bytes.NewReader(w.body.Bytes())),
+		ContentLength: int64(w.body.Len()),
+		Request:       r,
+	}
+
+	if contentLength := resp.Header.Get("Content-Length"); contentLength != "" {
+		resp.ContentLength, _ = strconv.ParseInt(contentLength, 10, 64)
+	}
+
+	resp.Close = headerHasToken(resp.Header.Values("Connection"), "close")
+
+	if resp.Header.Get("Transfer-Encoding") == TransferChunked {
+		resp.Header = resp.Header.Clone()
+		resp.Header.Del("Transfer-Encoding")
+		resp.TransferEncoding = []string{TransferChunked}
+		resp.ContentLength = -1
+	}
+
+	resp.Trailer = w.trailer()
+
+	return resp
+}
+
+func (w *responseWriter) trailer() http.Header {
+	var trailer http.Header
+
+	for _, declared := range w.snapHeader["Trailer"] {
+		for _, key := range strings.Split(declared, ",") {
+			key = http.CanonicalHeaderKey(strings.TrimSpace(key))
+
+			values, ok := w.header[key]
+			if !ok {
+				continue
+			}
+
+			if trailer == nil {
+				trailer = make(http.Header)
+			}
+
+			trailer[key] = append([]string(nil), values...)
+		}
+	}
+
+	for key, values := range w.header {
+		if !strings.HasPrefix(key, http.TrailerPrefix) {
+			continue
+		}
+
+		if trailer == nil {
+			trailer = make(http.Header)
+		}
+
+		for _, value := range values {
+			trailer.Add(strings.TrimPrefix(key, http.TrailerPrefix), value)
+		}
+	}
+
+	return trailer
+}
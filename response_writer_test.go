@@ -0,0 +1,70 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func Test_ResponseWriter_Result(t *testing.T) {
+	handleCall := func(_ TestReporter, w http.ResponseWriter, _ *http.Request, _ Call) {
+		w.Header().Set("Trailer", "X-Checksum")
+		w.Header().Set("X-Before", "1")
+
+		_, _ = io.WriteString(w, "<html></html>")
+
+		w.Header().Set("X-After", "1")
+		w.Header().Set("X-Checksum", "abc")
+		w.Header().Set(http.TrailerPrefix+"X-Count", "2")
+	}
+
+	transport := NewTransport(t, SequenceCalls(Call{}), handleCall)
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/page", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK || resp.Status != "200 OK" || string(body) != "<html></html>" {
+		t.Errorf("wrong response, status %s, body %s", resp.Status, body)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/html; charset=utf-8" {
+		t.Errorf("wrong sniffed content type %s", contentType)
+	}
+
+	if resp.Header.Get("X-Before") != "1" || resp.Header.Get("X-After") != "" {
+		t.Errorf("header not snapshotted on write, actual %v", resp.Header)
+	}
+
+	if resp.ContentLength != int64(len(body)) {
+		t.Errorf("wrong content length, expected %d, actual %d", len(body), resp.ContentLength)
+	}
+
+	if resp.Trailer.Get("X-Checksum") != "abc" || resp.Trailer.Get("X-Count") != "2" {
+		t.Errorf("wrong trailer %v", resp.Trailer)
+	}
+
+	if resp.Request != req {
+		t.Errorf("response request not set")
+	}
+}
+
+func Test_ResponseWriter_EmptyResponse(t *testing.T) {
+	w := newResponseWriter()
+
+	resp := w.result(nil)
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK || len(body) != 0 || resp.ContentLength != 0 || resp.Trailer != nil {
+		t.Errorf("wrong empty response %+v", resp)
+	}
+}
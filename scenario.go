@@ -0,0 +1,133 @@
+package httpmock
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ScenarioStep is a row of a Scenario table.
+type ScenarioStep struct {
+	// Name labels failures of the step, e.g. "3 call [step=pay], ...".
+	Name     string
+	Input    Input
+	Response Response
+	Delay    time.Duration
+	// When is the state the scenario must be in when the step is called,
+	// any state is accepted when empty.
+	When string
+	// Then moves the scenario to the state when the step is called, the
+	// state is kept when empty.
+	Then string
+}
+
+// Scenario serves the steps in order as Calls for long client workflows,
+// failures of a step are labeled with its name, and steps may require and
+// move the scenario state.
+//
+//	scenario := httpmock.NewScenario(t,
+//		httpmock.ScenarioStep{Name: "login", Input: login, Response: token, Then: "authorized"},
+//		httpmock.ScenarioStep{Name: "pay", Input: pay, Response: paid, When: "authorized", Then: "paid"},
+//	)
+//
+//	transport := httpmock.NewTransport(t, scenario, nil)
+type Scenario struct {
+	t     TestReporter
+	steps []ScenarioStep
+	site  callSite
+
+	mu    sync.Mutex
+	state string
+}
+
+func NewScenario(t TestReporter, steps ...ScenarioStep) *Scenario {
+	return &Scenario{
+		t:     t,
+		steps: steps,
+		site:  definitionSite(),
+	}
+}
+
+// State returns the current scenario state.
+func (s *Scenario) State() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+// SetState moves the scenario to the state, for transitions made outside
+// of the mocked calls.
+func (s *Scenario) SetState(state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = state
+}
+
+func (s *Scenario) Call(calledTimes int) (Call, bool) {
+	if calledTimes < 1 || calledTimes > len(s.steps) {
+		return Call{}, false
+	}
+
+	step := s.steps[calledTimes-1]
+
+	s.transit(calledTimes, step)
+
+	return step.call(), true
+}
+
+func (step ScenarioStep) call() Call {
+	call := Call{
+		Input:    step.Input,
+		Response: step.Response,
+		Delay:    step.Delay,
+	}
+
+	if step.Name != "" {
+		call.Labels = map[string]string{"step": step.Name}
+	}
+
+	return call
+}
+
+func (s *Scenario) validate(marshalJSON bool) error {
+	calls := make([]Call, len(s.steps))
+
+	for i, step := range s.steps {
+		calls[i] = step.call()
+	}
+
+	return validateCalls(calls, s.site, marshalJSON)
+}
+
+func (s *Scenario) transit(calledTimes int, step ScenarioStep) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if step.When != "" && s.state != step.When {
+		s.t.Errorf(
+			"%s, wrong scenario state, expected %s, actual %s",
+			scenarioStepName(calledTimes, step),
+			step.When,
+			s.state,
+		)
+	}
+
+	if step.Then != "" {
+		s.state = step.Then
+	}
+}
+
+func scenarioStepName(calledTimes int, step ScenarioStep) string {
+	name := strconv.Itoa(calledTimes) + " call"
+	if step.Name != "" {
+		name += " [step=" + step.Name + "]"
+	}
+
+	return name
+}
+
+func (s *Scenario) Done(calledTimes int) bool {
+	return calledTimes == len(s.steps)
+}
@@ -0,0 +1,73 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_Scenario(t *testing.T) {
+	steps := []ScenarioStep{
+		{
+			Name:     "login",
+			Input:    Input{Method: http.MethodPost, URL: mustParseURL("http://localhost/login")},
+			Response: Response{StatusCode: http.StatusOK, Body: RawBody("token")},
+			Then:     "authorized",
+		},
+		{
+			Name:     "pay",
+			Input:    Input{Method: http.MethodPost, URL: mustParseURL("http://localhost/payments")},
+			Response: Response{StatusCode: http.StatusCreated},
+			When:     "authorized",
+			Then:     "paid",
+		},
+	}
+
+	t.Run("passed", func(t *testing.T) {
+		scenario := NewScenario(t, steps...)
+
+		client := &http.Client{Transport: NewTransport(t, scenario, nil)}
+
+		for _, target := range []string{"http://localhost/login", "http://localhost/payments"} {
+			resp, err := client.Post(target, "", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp.Body.Close()
+		}
+
+		if scenario.State() != "paid" {
+			t.Errorf("wrong scenario state, expected paid, actual %s", scenario.State())
+		}
+	})
+
+	t.Run("failed step", func(t *testing.T) {
+		reporter := ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "%s, wrong scenario state, expected %s, actual %s", args: []any{"2 call [step=pay]", "authorized", "expired"}},
+				{format: "2 call [step=pay], wrong url.Path, expected %s, actual %s", args: []any{"/payments", "/refunds"}},
+			},
+			nil,
+		)(t)
+
+		scenario := NewScenario(reporter, steps...)
+
+		client := &http.Client{Transport: NewTransport(reporter, scenario, nil)}
+
+		resp, err := client.Post("http://localhost/login", "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		scenario.SetState("expired")
+
+		resp, err = client.Post("http://localhost/refunds", "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+}
@@ -0,0 +1,242 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Scrubber redacts secrets of an exchange before it is recorded.
+type Scrubber func(exchange *Exchange)
+
+// ScrubbedValue is the placeholder scrubbers write instead of the secret
+// with the name, InjectScrubbed replaces it back at replay.
+func ScrubbedValue(name string) string {
+	return "__scrubbed_" + name + "__"
+}
+
+type scrubbingSink struct {
+	sink      RecordSink
+	scrubbers []Scrubber
+}
+
+// ScrubbingSink scrubs every exchange before it reaches the sink, so
+// recorded fixtures don't leak credentials into repositories.
+//
+//	sink := httpmock.ScrubbingSink(cassette,
+//		httpmock.ScrubHeaders("Authorization"),
+//		httpmock.ScrubQuery("api_key"),
+//		httpmock.ScrubJSONFields("/password"),
+//	)
+func ScrubbingSink(sink RecordSink, scrubbers ...Scrubber) RecordSink {
+	return scrubbingSink{
+		sink:      sink,
+		scrubbers: scrubbers,
+	}
+}
+
+func (s scrubbingSink) Record(exchange Exchange) error {
+	exchange.Request.Header = exchange.Request.Header.Clone()
+	exchange.Request.Body = bytes.Clone(exchange.Request.Body)
+	exchange.Response.Header = exchange.Response.Header.Clone()
+	exchange.Response.Body = bytes.Clone(exchange.Response.Body)
+
+	for _, scrub := range s.scrubbers {
+		scrub(&exchange)
+	}
+
+	fixContentLength(exchange.Request.Header, exchange.Request.Body)
+	fixContentLength(exchange.Response.Header, exchange.Response.Body)
+
+	return s.sink.Record(exchange)
+}
+
+// fixContentLength keeps a recorded Content-Length in line with the body
+// scrubbers changed.
+func fixContentLength(header http.Header, body []byte) {
+	if header.Get("Content-Length") != "" {
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+}
+
+// ScrubHeaders replaces values of request and response headers with
+// ScrubbedValue of the canonical header name.
+func ScrubHeaders(names ...string) Scrubber {
+	return func(exchange *Exchange) {
+		for _, name := range names {
+			name = http.CanonicalHeaderKey(name)
+
+			scrubHeader(exchange.Request.Header, name)
+			scrubHeader(exchange.Response.Header, name)
+		}
+	}
+}
+
+func scrubHeader(header http.Header, name string) {
+	values := header[name]
+
+	for i := range values {
+		values[i] = ScrubbedValue(name)
+	}
+}
+
+// ScrubQuery replaces values of request query keys with ScrubbedValue of
+// the key.
+func ScrubQuery(keys ...string) Scrubber {
+	return func(exchange *Exchange) {
+		u, err := url.Parse(exchange.Request.URL)
+		if err != nil {
+			return
+		}
+
+		query := u.Query()
+
+		for _, key := range keys {
+			values := query[key]
+
+			for i := range values {
+				values[i] = ScrubbedValue(key)
+			}
+		}
+
+		u.RawQuery = query.Encode()
+		exchange.Request.URL = u.String()
+	}
+}
+
+// ScrubJSONFields replaces fields addressed by JSON pointers in request and
+// response JSON bodies with ScrubbedValue of the last pointer token, "*"
+// addresses every array item and is skipped in the name.
+func ScrubJSONFields(pointers ...string) Scrubber {
+	return func(exchange *Exchange) {
+		exchange.Request.Body = scrubJSONFields(exchange.Request.Body, pointers)
+		exchange.Response.Body = scrubJSONFields(exchange.Response.Body, pointers)
+	}
+}
+
+func scrubJSONFields(body []byte, pointers []string) []byte {
+	var value any
+
+	err := json.Unmarshal(body, &value)
+	if err != nil {
+		return body
+	}
+
+	for _, pointer := range pointers {
+		tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+
+		value = replaceJSONPointer(value, tokens, ScrubbedValue(jsonPointerName(tokens)))
+	}
+
+	scrubbed, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+
+	return scrubbed
+}
+
+// jsonPointerName is the last token which is not "*".
+func jsonPointerName(tokens []string) string {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if tokens[i] != "*" {
+			return unescapeJSONPointer(tokens[i])
+		}
+	}
+
+	return "*"
+}
+
+func replaceJSONPointer(value any, tokens []string, replacement string) any {
+	token := unescapeJSONPointer(tokens[0])
+
+	switch value := value.(type) {
+	case map[string]any:
+		child, ok := value[token]
+		if !ok {
+			return value
+		}
+
+		if len(tokens) == 1 {
+			value[token] = replacement
+		} else {
+			value[token] = replaceJSONPointer(child, tokens[1:], replacement)
+		}
+
+		return value
+	case []any:
+		if token != "*" {
+			return value
+		}
+
+		for i := range value {
+			if len(tokens) == 1 {
+				value[i] = replacement
+			} else {
+				value[i] = replaceJSONPointer(value[i], tokens[1:], replacement)
+			}
+		}
+
+		return value
+	default:
+		return value
+	}
+}
+
+// InjectScrubbed replaces ScrubbedValue placeholders of the names with the
+// values in the expected url and body and in the served response, e.g. the
+// fake api key the test client sends.
+func InjectScrubbed(values map[string]string) ReplayRule {
+	replacer := scrubbedReplacer(values, func(value string) string { return value })
+	queryReplacer := scrubbedReplacer(values, url.QueryEscape)
+
+	return func(call *Call) {
+		if call.Input.URL != nil {
+			u := *call.Input.URL
+			u.RawQuery = queryReplacer.Replace(u.RawQuery)
+			call.Input.URL = &u
+		}
+
+		if body, ok := call.Input.Body.(RawBody); ok {
+			call.Input.Body = RawBody(replacer.Replace(string(body)))
+		}
+
+		header := call.Response.Header.Clone()
+
+		for _, headerValues := range header {
+			for i := range headerValues {
+				headerValues[i] = replacer.Replace(headerValues[i])
+			}
+		}
+
+		call.Response.Header = header
+
+		if body, ok := call.Response.Body.(RawBody); ok {
+			call.Response.Body = RawBody(replacer.Replace(string(body)))
+
+			return
+		}
+
+		for name, value := range values {
+			call.Response.Body = replacingBody{
+				body:     call.Response.Body,
+				pattern:  regexp.MustCompile(regexp.QuoteMeta(ScrubbedValue(name))),
+				template: value,
+			}
+		}
+	}
+}
+
+func scrubbedReplacer(values map[string]string, encode func(string) string) *strings.Replacer {
+	pairs := make([]string, 0, 2*len(values))
+
+	for name, value := range values {
+		pairs = append(pairs, ScrubbedValue(name), encode(value))
+	}
+
+	return strings.NewReplacer(pairs...)
+}
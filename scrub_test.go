@@ -0,0 +1,163 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_ScrubbingSink(t *testing.T) {
+	cassette := &Cassette{}
+
+	sink := ScrubbingSink(cassette,
+		ScrubHeaders("authorization", "Set-Cookie"),
+		ScrubQuery("api_key"),
+		ScrubJSONFields("/password", "/tokens/*"),
+	)
+
+	exchange := Exchange{
+		Request: RecordedRequest{
+			Method: http.MethodPost,
+			URL:    "http://localhost/login?api_key=secret&lang=en",
+			Header: http.Header{"Authorization": {"Bearer secret"}},
+			Body:   RecordedBody(`{"user":"dima","password":"secret"}`),
+		},
+		Response: RecordedResponse{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Set-Cookie": {"session=secret"}},
+			Body:       RecordedBody(`{"tokens":["a","b"]}`),
+		},
+	}
+
+	err := sink.Record(exchange)
+	if err != nil {
+		t.Fatalf("record exchange, unexpected error: %s", err)
+	}
+
+	expected := Exchange{
+		Request: RecordedRequest{
+			Method: http.MethodPost,
+			URL:    "http://localhost/login?api_key=__scrubbed_api_key__&lang=en",
+			Header: http.Header{"Authorization": {"__scrubbed_Authorization__"}},
+			Body:   RecordedBody(`{"password":"__scrubbed_password__","user":"dima"}`),
+		},
+		Response: RecordedResponse{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Set-Cookie": {"__scrubbed_Set-Cookie__"}},
+			Body:       RecordedBody(`{"tokens":["__scrubbed_tokens__","__scrubbed_tokens__"]}`),
+		},
+	}
+
+	if !reflect.DeepEqual(cassette.Exchanges(), []Exchange{expected}) {
+		t.Errorf("wrong scrubbed exchange, expected %+v, actual %+v", expected, cassette.Exchanges())
+	}
+
+	if exchange.Request.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("recorded exchange modified, authorization %s", exchange.Request.Header.Get("Authorization"))
+	}
+}
+
+func Test_InjectScrubbed(t *testing.T) {
+	cassette := &Cassette{}
+
+	err := cassette.Record(Exchange{
+		Request: RecordedRequest{
+			Method: http.MethodPost,
+			URL:    "http://localhost/login?api_key=__scrubbed_api_key__",
+			Body:   RecordedBody(`{"password":"__scrubbed_password__"}`),
+		},
+		Response: RecordedResponse{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"X-Token": {"__scrubbed_token__"}},
+			Body:       RecordedBody(`{"token":"__scrubbed_token__"}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls, err := cassette.Calls(InjectScrubbed(map[string]string{
+		"api_key":  "fake key",
+		"password": "fake",
+		"token":    "fake-token",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport := NewTransport(t, SequenceCalls(calls...), nil)
+
+	resp, err := (&http.Client{Transport: transport}).Post(
+		"http://localhost/login?api_key=fake+key",
+		"application/json",
+		strings.NewReader(`{"password":"fake"}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if string(body) != `{"token":"fake-token"}` || resp.Header.Get("X-Token") != "fake-token" {
+		t.Errorf("scrubbed values not injected, body %s, header %s", body, resp.Header.Get("X-Token"))
+	}
+}
+
+func Test_ScrubbingSink_ServerReplay(t *testing.T) {
+	cassette := &Cassette{}
+
+	err := ScrubbingSink(cassette, ScrubJSONFields("/token")).Record(Exchange{
+		Request: RecordedRequest{Method: http.MethodGet, URL: "http://localhost/token"},
+		Response: RecordedResponse{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Length": {"15"}},
+			Body:       RecordedBody(`{"token":"abc"}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded := cassette.Exchanges()[0].Response
+	if contentLength := recorded.Header.Get("Content-Length"); contentLength != strconv.Itoa(len(recorded.Body)) {
+		t.Errorf("wrong scrubbed Content-Length, expected %d, actual %s", len(recorded.Body), contentLength)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		rules    []ReplayRule
+		expected string
+	}{
+		{name: "scrubbed", expected: `{"token":"__scrubbed_token__"}`},
+		{name: "injected", rules: []ReplayRule{InjectScrubbed(map[string]string{"token": "t"})}, expected: `{"token":"t"}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, err := cassette.Calls(tt.rules...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			server := NewServer(t, SequenceCalls(calls...), nil)
+
+			resp, err := http.Get(server.URL + "/token")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read replayed body, unexpected error: %s", err)
+			}
+
+			if string(body) != tt.expected {
+				t.Errorf("wrong body, expected %s, actual %s", tt.expected, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package httpmock
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+)
+
+// Server serves calls over a real listener for code which takes a base url
+// instead of an http.Client, it is closed at Cleanup.
+type Server struct {
+	*httptest.Server
+
+	transport  *transport
+	socketPath string
+	conns      *connTracker
+	drain      *drainTracker
+	served     servedCounter
+	directives connDirectives
+}
+
+func NewServer(t TestReporter, calls Calls, handleCall HandleCall, opts ...Option) *Server {
+	ts := newTransport(t, calls, handleCall, opts)
+	ts.events = newEventBroker()
+
+	t.Cleanup(ts.assert)
+	t.Cleanup(ts.webhooks.wait)
+
+	listener := ts.options.listener
+
+	if ts.options.addr != "" {
+		var err error
+
+		listener, err = net.Listen("tcp", ts.options.addr)
+		if err != nil {
+			t.Fatalf("listen %s, %s", ts.options.addr, err)
+
+			return nil
+		}
+	}
+
+	server := &Server{
+		transport: ts,
+		conns:     newConnTracker(),
+		drain:     newDrainTracker(),
+	}
+
+	server.start(listener)
+
+	t.Cleanup(server.Close)
+	t.Cleanup(ts.awaitCalls)
+
+	return server
+}
+
+func (s *Server) start(listener net.Listener) {
+	var handler http.Handler = s.transport
+
+	if s.transport.options.http10 {
+		handler = http10Handler(s.transport.t, handler)
+	}
+
+	server := httptest.NewUnstartedServer(s.served.handler(s.directives.handler(s.drain.handler(handler))))
+
+	if listener != nil {
+		server.Listener.Close()
+		server.Listener = listener
+	}
+
+	if s.transport.options.listenerFaults != nil {
+		server.Listener = newFaultyListener(server.Listener, *s.transport.options.listenerFaults)
+	}
+
+	server.Config.ConnState = s.conns.track
+
+	if s.transport.options.tlsConfig != nil {
+		server.TLS = s.transport.options.tlsConfig.Clone()
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+
+	s.Server = server
+
+	if s.socketPath != "" {
+		s.dialUnixSocket()
+	}
+}
+
+// Served returns the count of requests the server served.
+func (s *Server) Served() int {
+	return int(s.served.count.Load())
+}
+
+// Restart drops in-flight and pooled connections, shuts down the server
+// and listens on the same address again, calls and their counters are kept.
+// Handlers of dropped requests are not waited for.
+func (s *Server) Restart() error {
+	addr := s.Listener.Addr()
+
+	s.Config.SetKeepAlivesEnabled(false)
+	s.CloseClientConnections()
+
+	// http.Server.Close stops the serve goroutine and closes the listener
+	// and the connections left, unlike httptest.Server.Close it does not
+	// wait for handlers.
+	s.Config.Close()
+
+	listener, err := net.Listen(addr.Network(), addr.String())
+	if err != nil {
+		return fmt.Errorf("listen %s again, %w", addr, err)
+	}
+
+	s.start(listener)
+
+	return nil
+}
+
+// NewUnixServer serves calls in sequence on the unix socket for clients of
+// daemons dialing over UDS. Server.URL is http://unix and Server.Client dials
+// the socket, other clients need a DialContext dialing it.
+func NewUnixServer(t TestReporter, socketPath string, calls ...Call) *Server {
+	if info, err := os.Stat(socketPath); err == nil && info.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(socketPath)
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("listen unix socket %s, %s", socketPath, err)
+
+		return nil
+	}
+
+	server := NewServer(t, SequenceCalls(calls...), nil, WithListener(listener))
+	server.socketPath = socketPath
+	server.dialUnixSocket()
+
+	return server
+}
+
+func (s *Server) dialUnixSocket() {
+	s.URL = "http://unix"
+
+	dialer := &net.Dialer{}
+
+	s.Client().Transport.(*http.Transport).DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", s.socketPath)
+	}
+}
+
+// Emit writes the event to open Watch and LongPoll responses, []byte and
+// string events are written as is, other values are encoded as JSON, every
+// event ends with a newline. Events emitted while no response is open are
+// delivered to the next one.
+func (s *Server) Emit(event any) error {
+	data, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	s.transport.events.emit(data)
+
+	return nil
+}
+
+// Watchers returns the count of open Watch and LongPoll responses.
+func (s *Server) Watchers() int {
+	return s.transport.events.count()
+}
+
+// Close ends open Watch responses and shuts down the server.
+func (s *Server) Close() {
+	s.transport.events.close()
+	s.Server.Close()
+}
+
+// NewStaticServer serves calls repeatedly, see StaticCalls.
+func NewStaticServer(t TestReporter, calls ...Call) *Server {
+	return NewServer(t, StaticCalls(calls...), HandleCallCompareInput)
+}
+
+func (h *transport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	calledTimes, t := h.next()
+
+	call, ok := callFor(h.calls, int(calledTimes), r)
+	if !ok {
+		h.noteTranscript(calledTimes, r, "no expected calls left")
+
+		t.Errorf("no expected calls left")
+
+		writeNoCallsLeft(w, http.StatusNotImplemented, calledTimes, r)
+
+		return
+	}
+
+	t = withLabels(t, call.Labels)
+
+	defer h.matched(calledTimes)()
+
+	compareFaultedInput(t, r, call)
+
+	if call.DoError != nil {
+		h.noteTranscript(calledTimes, r, "aborted")
+
+		panic(http.ErrAbortHandler)
+	}
+
+	if call.Timeout {
+		<-r.Context().Done()
+
+		return
+	}
+
+	if call.ReadWithin > 0 {
+		rw := &readWithinWriter{ResponseWriter: w}
+		defer rw.compare(t, call.ReadWithin)
+
+		w = rw
+	}
+
+	if call.FirstByte > 0 || call.BodyTransfer > 0 {
+		lw := newLatencyWriter(w, r, call)
+		defer lw.finish()
+
+		w = lw
+	}
+
+	h.serveCall(t, w, r, call, calledTimes)
+}
@@ -0,0 +1,385 @@
+package httpmock
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_NewServer(t *testing.T) {
+	server := NewServer(t,
+		SequenceCalls(
+			Call{
+				Input: Input{
+					Method: http.MethodPost,
+					URL:    mustParseURL("/users?role=admin"),
+					Body:   RawBody(`{"name":"dima"}`),
+				},
+				Response: Response{
+					StatusCode: http.StatusCreated,
+					Header:     http.Header{"Content-Type": {"application/json"}},
+					Body:       RawBody(`{"id":1}`),
+				},
+			},
+		),
+		nil,
+	)
+
+	resp, err := http.Post(server.URL+"/users?role=admin", "application/json", strings.NewReader(`{"name":"dima"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("wrong status code, expected %d, actual %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	if string(body) != `{"id":1}` {
+		t.Errorf("wrong body, expected %s, actual %s", `{"id":1}`, body)
+	}
+}
+
+func Test_NewServer_DoErrorAbortsConnection(t *testing.T) {
+	server := NewServer(t,
+		SequenceCalls(Call{DoError: io.ErrUnexpectedEOF}),
+		nil,
+	)
+
+	_, err := http.Get(server.URL)
+	if err == nil {
+		t.Fatal("expect error on aborted connection")
+	}
+}
+
+func Test_NewServer_NoExpectedCallsLeft(t *testing.T) {
+	tr := ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "2 call, no expected calls left"},
+			{format: "assert handler calls, not all calls were handled"},
+		},
+		nil,
+	)(t)
+
+	server := NewServer(tr, SequenceCalls(Call{Input: Input{Method: http.MethodGet}}), nil)
+
+	for _, expectedStatusCode := range []int{http.StatusOK, http.StatusNotImplemented} {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		if resp.StatusCode != expectedStatusCode {
+			t.Errorf("wrong status code, expected %d, actual %d", expectedStatusCode, resp.StatusCode)
+		}
+	}
+}
+
+func Test_NewStaticServer(t *testing.T) {
+	server := NewStaticServer(t,
+		Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{Body: RawBody("pong")},
+		},
+	)
+
+	for range 3 {
+		resp, err := http.Get(server.URL + "/ping")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(body) != "pong" {
+			t.Errorf("wrong body, expected pong, actual %s", body)
+		}
+	}
+}
+
+func Test_NewUnixServer(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "docker.sock")
+
+	server := NewUnixServer(t, socketPath,
+		Call{
+			Input:    Input{Method: http.MethodGet, URL: mustParseURL("/_ping")},
+			Response: Response{Body: RawBody("OK")},
+		},
+		Call{
+			Input:    Input{Method: http.MethodGet, URL: mustParseURL("/version")},
+			Response: Response{Body: RawBody(`{"Version":"27.0"}`)},
+		},
+	)
+
+	if server.URL != "http://unix" {
+		t.Errorf("wrong server url, expected http://unix, actual %s", server.URL)
+	}
+
+	resp, err := server.Client().Get(server.URL + "/_ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	resp, err = client.Get("http://docker/version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if string(body) != `{"Version":"27.0"}` {
+		t.Errorf("wrong body, expected %s, actual %s", `{"Version":"27.0"}`, body)
+	}
+}
+
+func Test_NewServer_Listener(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().String()
+
+	server := NewServer(t,
+		SequenceCalls(Call{Input: Input{Method: http.MethodGet}}),
+		nil,
+		WithListener(listener),
+	)
+
+	if server.URL != "http://"+addr {
+		t.Errorf("wrong server url, expected http://%s, actual %s", addr, server.URL)
+	}
+
+	resp, err := http.Get("http://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	server.Close()
+
+	fixed := NewServer(t,
+		SequenceCalls(Call{Input: Input{Method: http.MethodGet}}),
+		nil,
+		WithAddr(addr),
+	)
+
+	if fixed.URL != "http://"+addr {
+		t.Errorf("wrong fixed server url, expected http://%s, actual %s", addr, fixed.URL)
+	}
+
+	resp, err = http.Get("http://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_NewServer_AddrInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	tr := &testReporterMock{t: t}
+
+	server := NewServer(tr, SequenceCalls(), nil, WithAddr(listener.Addr().String()))
+	if server != nil {
+		t.Errorf("expect nil server on listen error")
+	}
+
+	if len(tr.fatalfCalls) != 1 || tr.fatalfCalls[0].format != "listen %s, %s" {
+		t.Errorf("wrong fatalf calls %v", tr.fatalfCalls)
+	}
+}
+
+func Test_Server_Restart(t *testing.T) {
+	release := make(chan struct{})
+
+	server := NewServer(t,
+		SequenceCalls(
+			Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("1")}},
+			Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("2")}},
+			Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("3")}},
+		),
+		func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+			if r.URL.Path == "/slow" {
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+
+				<-release
+			}
+
+			HandleCallCompareInput(t, w, r, call)
+		},
+	)
+
+	url := server.URL
+	client := &http.Client{}
+
+	get := func(path string) (string, error) {
+		resp, err := client.Get(url + path)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+
+		return string(body), err
+	}
+
+	body, err := get("/")
+	if err != nil || body != "1" {
+		t.Fatalf("first request, body %s, error %v", body, err)
+	}
+
+	slowErr := make(chan error, 1)
+
+	go func() {
+		_, err := get("/slow")
+		slowErr <- err
+	}()
+
+	for server.transport.calledTimes.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	err = server.Restart()
+	if err != nil {
+		t.Fatalf("restart, unexpected error: %s", err)
+	}
+
+	close(release)
+
+	if err := <-slowErr; err == nil {
+		t.Errorf("expect in-flight request dropped by restart")
+	}
+
+	if server.URL != url {
+		t.Errorf("wrong url after restart, expected %s, actual %s", url, server.URL)
+	}
+
+	body, err = get("/")
+	if err != nil || body != "3" {
+		t.Errorf("request after restart, body %s, error %v", body, err)
+	}
+}
+
+func Test_Server_RestartTwice(t *testing.T) {
+	server := NewStaticServer(t, Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("ok")}})
+
+	client := &http.Client{Transport: &http.Transport{}}
+
+	get := func() {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("get, unexpected error: %s", err)
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	get()
+
+	client.CloseIdleConnections()
+
+	goroutines := runtime.NumGoroutine()
+
+	for range 2 {
+		err := server.Restart()
+		if err != nil {
+			t.Fatalf("restart, unexpected error: %s", err)
+		}
+
+		get()
+	}
+
+	client.CloseIdleConnections()
+
+	deadline := time.Now().Add(time.Second)
+
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if leaked := runtime.NumGoroutine() - goroutines; leaked > 0 {
+		t.Errorf("restart leaked %d goroutines", leaked)
+	}
+}
+
+func Test_Server_WriteHeaderAndTrailer(t *testing.T) {
+	call := Call{
+		Input: Input{Method: http.MethodGet},
+		Response: Response{
+			Header: http.Header{
+				"X-Tag":        {"a", "b"},
+				"x-request-id": {"1", "2"},
+			},
+			Body:    RawBody("body"),
+			Trailer: http.Header{"X-Checksum": {"c1", "c2"}},
+		},
+	}
+
+	server := NewStaticServer(t, call)
+
+	clients := map[string]*http.Client{
+		"server":    server.Client(),
+		"transport": {Transport: NewTransport(t, StaticCalls(call), nil)},
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != "body" {
+				t.Errorf("wrong body %s", body)
+			}
+
+			if values := resp.Header.Values("X-Tag"); !slices.Equal(values, []string{"a", "b"}) {
+				t.Errorf("wrong X-Tag values %v", values)
+			}
+
+			if values := resp.Header.Values("X-Request-Id"); !slices.Equal(values, []string{"1", "2"}) {
+				t.Errorf("wrong X-Request-Id values %v", values)
+			}
+
+			if values := resp.Trailer.Values("X-Checksum"); !slices.Equal(values, []string{"c1", "c2"}) {
+				t.Errorf("wrong X-Checksum trailer values %v", values)
+			}
+		})
+	}
+}
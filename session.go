@@ -0,0 +1,213 @@
+package httpmock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Session keeps values between requests of one client, it turns repeating
+// static calls into a lightweight fake. Templates reach the session of the
+// current request with:
+//
+//	{{session.id}}                 session id
+//	{{session.get name}}           stored value, empty if not set
+//	{{session.set name value}}     stores the value and renders nothing
+//	{{session.incr name}}          increments the counter and renders it
+//	{{session.delete name}}        removes the value and renders nothing
+//
+// Arguments are literals, quoted strings or request.* and generated.*
+// expressions, e.g. {{session.set user request.body}}.
+type Session struct {
+	ID string
+
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newSession(id string) *Session {
+	return &Session{
+		ID:     id,
+		values: make(map[string]string),
+	}
+}
+
+// Get returns the value stored under the name.
+func (s *Session) Get(name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.values[name]
+
+	return value, ok
+}
+
+// Set stores the value under the name.
+func (s *Session) Set(name, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[name] = value
+}
+
+// Delete removes the value stored under the name.
+func (s *Session) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, name)
+}
+
+// Incr increments the counter stored under the name and returns the new
+// value, values which are not numbers start from zero.
+func (s *Session) Incr(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, _ := strconv.Atoi(s.values[name])
+	counter++
+
+	s.values[name] = strconv.Itoa(counter)
+
+	return counter
+}
+
+// SessionStore keeps sessions in memory, pass it with WithSessions to a
+// transport or server.
+type SessionStore struct {
+	cookie string
+	header string
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+// CookieSessions keys sessions by the cookie value, a request without the
+// cookie starts a new session and the cookie is set in the response.
+func CookieSessions(name string) *SessionStore {
+	return &SessionStore{
+		cookie:   name,
+		sessions: make(map[string]*Session),
+	}
+}
+
+// HeaderSessions keys sessions by the header value, requests without the
+// header share the session with the empty id.
+func HeaderSessions(name string) *SessionStore {
+	return &SessionStore{
+		header:   name,
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Session returns the session by id, creating it if missing.
+func (s *SessionStore) Session(id string) *Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		session = newSession(id)
+		s.sessions[id] = session
+	}
+
+	return session
+}
+
+func (s *SessionStore) attach(w http.ResponseWriter, r *http.Request) *http.Request {
+	var id string
+
+	switch {
+	case s.cookie != "":
+		cookie, err := r.Cookie(s.cookie)
+		if err == nil {
+			id = cookie.Value
+
+			break
+		}
+
+		id = newUUID()
+
+		http.SetCookie(w, &http.Cookie{Name: s.cookie, Value: id, Path: "/"})
+	default:
+		id = r.Header.Get(s.header)
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), sessionContextKey{}, s.Session(id)))
+}
+
+type sessionContextKey struct{}
+
+// SessionFromRequest returns the session attached to the request, nil if
+// the transport or server was created without WithSessions.
+func SessionFromRequest(r *http.Request) *Session {
+	session, _ := r.Context().Value(sessionContextKey{}).(*Session)
+
+	return session
+}
+
+func evalSessionExpression(fields []string, tc *templateContext) (string, error) {
+	if tc == nil || tc.request == nil {
+		return "", errTemplateNoRequest
+	}
+
+	session := SessionFromRequest(tc.request)
+	if session == nil {
+		return "", fmt.Errorf("no session attached to request, use WithSessions")
+	}
+
+	action := strings.TrimPrefix(fields[0], "session.")
+
+	args := make([]string, 0, len(fields)-1)
+
+	for _, field := range fields[1:] {
+		arg, err := evalSessionArg(field, tc)
+		if err != nil {
+			return "", err
+		}
+
+		args = append(args, arg)
+	}
+
+	expectedArgs := map[string]int{"id": 0, "get": 1, "set": 2, "incr": 1, "delete": 1}
+
+	expected, ok := expectedArgs[action]
+	if !ok {
+		return "", fmt.Errorf("unknown session action %s", action)
+	}
+
+	if len(args) != expected {
+		return "", fmt.Errorf("wrong session.%s arguments count, expected %d, actual %d", action, expected, len(args))
+	}
+
+	switch action {
+	case "id":
+		return session.ID, nil
+	case "get":
+		value, _ := session.Get(args[0])
+
+		return value, nil
+	case "set":
+		session.Set(args[0], args[1])
+	case "incr":
+		return strconv.Itoa(session.Incr(args[0])), nil
+	case "delete":
+		session.Delete(args[0])
+	}
+
+	return "", nil
+}
+
+func evalSessionArg(arg string, tc *templateContext) (string, error) {
+	switch {
+	case strings.HasPrefix(arg, "request."):
+		return evalRequestExpression(arg, tc)
+	case strings.HasPrefix(arg, "generated."):
+		return tc.gen().evalGenerated(arg)
+	}
+
+	return unquoteHelperArg(arg), nil
+}
@@ -0,0 +1,136 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"testing"
+)
+
+func Test_CookieSessions_Counters(t *testing.T) {
+	server := NewServer(t,
+		StaticCalls(
+			Call{
+				Input:    Input{Method: http.MethodGet},
+				Response: Response{Body: TemplateBody("visit {{session.incr visits}}")},
+			},
+		),
+		nil,
+		WithSessions(CookieSessions("sid")),
+	)
+
+	visit := func(client *http.Client) string {
+		resp, err := client.Get(server.URL + "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+
+		return string(body)
+	}
+
+	newClient := func() *http.Client {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return &http.Client{Jar: jar}
+	}
+
+	first, second := newClient(), newClient()
+
+	actual := []string{visit(first), visit(first), visit(second), visit(first)}
+	expected := []string{"visit 1", "visit 2", "visit 1", "visit 3"}
+
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("wrong session counters, expected %v, actual %v", expected, actual)
+	}
+}
+
+func Test_HeaderSessions_CreatedResources(t *testing.T) {
+	store := HeaderSessions("X-Tenant")
+
+	calls := SequenceCalls(
+		Call{
+			Input: Input{Method: http.MethodPut, Body: RawBody(`{"name":"dima"}`)},
+			Response: Response{
+				StatusCode: http.StatusNoContent,
+				Body:       TemplateBody("{{session.set request.path request.body}}"),
+			},
+		},
+		Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{Body: TemplateBody("{{session.get request.path}}")},
+		},
+		Call{
+			Input:    Input{Method: http.MethodGet},
+			Response: Response{Body: TemplateBody("{{session.get request.path}}")},
+		},
+	)
+
+	client := &http.Client{Transport: NewTransport(t, calls, nil, WithSessions(store))}
+
+	do := func(method, tenant, body string) string {
+		req, err := http.NewRequest(method, "http://localhost/users/1", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("X-Tenant", tenant)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		respBody, _ := io.ReadAll(resp.Body)
+
+		return string(respBody)
+	}
+
+	do(http.MethodPut, "a", `{"name":"dima"}`)
+
+	if body := do(http.MethodGet, "a", ""); body != `{"name":"dima"}` {
+		t.Errorf("wrong stored resource, expected %s, actual %s", `{"name":"dima"}`, body)
+	}
+
+	if body := do(http.MethodGet, "b", ""); body != "" {
+		t.Errorf("resource leaked to another session, actual %s", body)
+	}
+
+	if value, ok := store.Session("a").Get("/users/1"); !ok || value != `{"name":"dima"}` {
+		t.Errorf("wrong session value, actual %s", value)
+	}
+}
+
+func Test_Session_TemplateErrors(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = TemplateBody("{{session.id}}").(RequestBody).RequestBytes(r)
+	if err == nil || err.Error() != "render template expression {{session.id}}, no session attached to request, use WithSessions" {
+		t.Errorf("wrong error without session, actual %v", err)
+	}
+
+	r = CookieSessions("sid").attach(&nopResponseWriter{header: make(http.Header)}, r)
+
+	_, err = TemplateBody("{{session.set name}}").(RequestBody).RequestBytes(r)
+	if err == nil || err.Error() != "render template expression {{session.set name}}, wrong session.set arguments count, expected 2, actual 1" {
+		t.Errorf("wrong error for missing argument, actual %v", err)
+	}
+}
+
+type nopResponseWriter struct {
+	header http.Header
+}
+
+func (w *nopResponseWriter) Header() http.Header         { return w.header }
+func (w *nopResponseWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w *nopResponseWriter) WriteHeader(int)             {}
@@ -0,0 +1,82 @@
+package httpmock
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+// counterShard is padded to a cache line, so parallel shards don't share one.
+type counterShard struct {
+	count atomic.Int64
+	_     [56]byte
+}
+
+type shardedCounter struct {
+	shards []counterShard
+	mask   uint32
+}
+
+func newShardedCounter() *shardedCounter {
+	size := 1
+	for size < runtime.GOMAXPROCS(0) {
+		size <<= 1
+	}
+
+	return &shardedCounter{
+		shards: make([]counterShard, size),
+		mask:   uint32(size - 1),
+	}
+}
+
+// add increments a random shard and returns its count.
+func (c *shardedCounter) add() int64 {
+	return c.shards[rand.Uint32()&c.mask].count.Add(1)
+}
+
+func (c *shardedCounter) load() int64 {
+	var total int64
+
+	for i := range c.shards {
+		total += c.shards[i].count.Load()
+	}
+
+	return total
+}
+
+// WithShardedCalls counts calls in sharded counters instead of a single one
+// and reports errors without the call number, for StaticCalls backing
+// load-style tests with many parallel clients. Calls get the count of
+// their shard, so only calls serving the same call for every call number
+// are accepted: StaticCalls with one call and NewVerifiedHandlerTransport
+// without MaxCalls. Other calls, including wrapped ones, e.g. FailOnCalls,
+// are rejected with Fatalf. WithTimeline and WithTimings see repeated call
+// numbers.
+func WithShardedCalls() Option {
+	return func(o *options) {
+		o.shardedCalls = true
+	}
+}
+
+// unorderedCalls is implemented by Calls which may be counted in shards.
+type unorderedCalls interface {
+	// unordered reports whether the calls serve the same call for every
+	// call number.
+	unordered() bool
+}
+
+func (s staticCalls) unordered() bool {
+	return len(s.calls) <= 1
+}
+
+func (c handlerCalls) unordered() bool {
+	return c.expect.MaxCalls == 0
+}
+
+// shardable reports whether calls may be counted in shards, Done is still
+// exact as the shards are summed.
+func shardable(calls Calls) bool {
+	unordered, ok := calls.(unorderedCalls)
+
+	return ok && unordered.unordered()
+}
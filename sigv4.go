@@ -0,0 +1,329 @@
+package httpmock
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	sigV4Algorithm       = "AWS4-HMAC-SHA256"
+	sigV4UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// SigV4Credentials are the test credentials requests are signed with,
+// empty Region and Service are taken from the credential scope of the
+// request without comparison.
+type SigV4Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+	Service         string
+}
+
+// SigV4 verifies AWS Signature Version 4 of the Authorization header.
+// Components of the signature are checked one by one, access key, scope,
+// date, signed headers and payload hash, so the failure names the one which
+// differs, a mismatch of the signature itself reports the canonical request
+// the mock signed. Presigned urls and SigV4a are not supported.
+func SigV4(credentials SigV4Credentials) RequestVerifier {
+	return func(t TestReporter, r *http.Request) {
+		verifySigV4(t, r, credentials)
+	}
+}
+
+type sigV4Authorization struct {
+	accessKeyID   string
+	date          string
+	region        string
+	service       string
+	signedHeaders []string
+	signature     string
+}
+
+func (a sigV4Authorization) scope() string {
+	return strings.Join([]string{a.date, a.region, a.service, "aws4_request"}, "/")
+}
+
+func verifySigV4(t TestReporter, r *http.Request, credentials SigV4Credentials) {
+	authorization := r.Header.Get("Authorization")
+	if authorization == "" {
+		t.Errorf("sigv4, Authorization header not presented")
+
+		return
+	}
+
+	auth, err := parseSigV4Authorization(authorization)
+	if err != nil {
+		t.Errorf("sigv4, %s", err)
+
+		return
+	}
+
+	if !compareSigV4Scope(t, r, auth, credentials) {
+		return
+	}
+
+	var body []byte
+
+	if r.Body != nil {
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			withFailureKind(t, ErrBodyRead).Errorf("sigv4, read body from request, %s", err)
+
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	payloadHash := hashHex(body)
+
+	if contentHash := r.Header.Get("X-Amz-Content-Sha256"); contentHash != "" {
+		if contentHash != sigV4UnsignedPayload && !strings.HasPrefix(contentHash, "STREAMING-") && contentHash != payloadHash {
+			t.Errorf("sigv4, X-Amz-Content-Sha256 %s does not match body hash %s", contentHash, payloadHash)
+
+			return
+		}
+
+		payloadHash = contentHash
+	}
+
+	canonicalRequest, ok := sigV4CanonicalRequest(t, r, auth, payloadHash)
+	if !ok {
+		return
+	}
+
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		sigV4Date(r),
+		auth.scope(),
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), auth.date)
+	key = hmacSHA256(key, auth.region)
+	key = hmacSHA256(key, auth.service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	if !hmac.Equal([]byte(signature), []byte(auth.signature)) {
+		t.Errorf("sigv4, signature mismatch, expected %s, actual %s, canonical request:\n%s", signature, auth.signature, canonicalRequest)
+	}
+}
+
+func parseSigV4Authorization(authorization string) (sigV4Authorization, error) {
+	algorithm, params, _ := strings.Cut(authorization, " ")
+	if algorithm != sigV4Algorithm {
+		return sigV4Authorization{}, fmt.Errorf("unsupported algorithm %s, expected %s", algorithm, sigV4Algorithm)
+	}
+
+	var (
+		auth        sigV4Authorization
+		credential  string
+		signedNames string
+	)
+
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+
+		switch key {
+		case "Credential":
+			credential = value
+		case "SignedHeaders":
+			signedNames = value
+		case "Signature":
+			auth.signature = value
+		}
+	}
+
+	scope := strings.Split(credential, "/")
+	if len(scope) != 5 || scope[4] != "aws4_request" {
+		return sigV4Authorization{}, fmt.Errorf("malformed Credential %s, expected access-key/date/region/service/aws4_request", credential)
+	}
+
+	if signedNames == "" || auth.signature == "" {
+		return sigV4Authorization{}, fmt.Errorf("malformed Authorization header, SignedHeaders and Signature expected")
+	}
+
+	auth.accessKeyID, auth.date, auth.region, auth.service = scope[0], scope[1], scope[2], scope[3]
+	auth.signedHeaders = strings.Split(signedNames, ";")
+
+	return auth, nil
+}
+
+func compareSigV4Scope(t TestReporter, r *http.Request, auth sigV4Authorization, credentials SigV4Credentials) bool {
+	ok := true
+
+	mismatch := func(component, expected, actual string) {
+		if expected != "" && expected != actual {
+			t.Errorf("sigv4, wrong %s, expected %s, actual %s", component, expected, actual)
+
+			ok = false
+		}
+	}
+
+	mismatch("access key", credentials.AccessKeyID, auth.accessKeyID)
+	mismatch("credential scope region", credentials.Region, auth.region)
+	mismatch("credential scope service", credentials.Service, auth.service)
+
+	date := sigV4Date(r)
+	if date == "" {
+		t.Errorf("sigv4, X-Amz-Date header not presented")
+
+		return false
+	}
+
+	if !strings.HasPrefix(date, auth.date) {
+		t.Errorf("sigv4, credential scope date %s does not match X-Amz-Date %s", auth.date, date)
+
+		ok = false
+	}
+
+	if !slices.Contains(auth.signedHeaders, "host") {
+		t.Errorf("sigv4, host header is not signed")
+
+		ok = false
+	}
+
+	if !slices.IsSorted(auth.signedHeaders) {
+		t.Errorf("sigv4, SignedHeaders %s are not sorted", strings.Join(auth.signedHeaders, ";"))
+
+		ok = false
+	}
+
+	return ok
+}
+
+func sigV4Date(r *http.Request) string {
+	if date := r.Header.Get("X-Amz-Date"); date != "" {
+		return date
+	}
+
+	return r.Header.Get("Date")
+}
+
+func sigV4CanonicalRequest(t TestReporter, r *http.Request, auth sigV4Authorization, payloadHash string) (string, bool) {
+	var headers strings.Builder
+
+	for _, name := range auth.signedHeaders {
+		values, ok := sigV4HeaderValues(r, name)
+		if !ok {
+			t.Errorf("sigv4, signed header %s not presented", name)
+
+			return "", false
+		}
+
+		for i, value := range values {
+			values[i] = strings.Join(strings.Fields(value), " ")
+		}
+
+		headers.WriteString(name + ":" + strings.Join(values, ",") + "\n")
+	}
+
+	return strings.Join([]string{
+		r.Method,
+		sigV4CanonicalURI(r, auth.service),
+		sigV4CanonicalQuery(r),
+		headers.String(),
+		strings.Join(auth.signedHeaders, ";"),
+		payloadHash,
+	}, "\n"), true
+}
+
+func sigV4HeaderValues(r *http.Request, name string) ([]string, bool) {
+	switch name {
+	case "host":
+		host := r.Host
+		if host == "" {
+			host = r.URL.Host
+		}
+
+		return []string{host}, host != ""
+	case "content-length":
+		if r.ContentLength >= 0 && r.Header.Get("Content-Length") == "" {
+			return []string{strconv.FormatInt(r.ContentLength, 10)}, true
+		}
+	}
+
+	values := slices.Clone(r.Header.Values(name))
+
+	return values, len(values) > 0
+}
+
+// sigV4CanonicalURI encodes path segments once more, except for s3 which
+// signs the path as sent.
+func sigV4CanonicalURI(r *http.Request, service string) string {
+	path := r.URL.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+
+	if service == "s3" {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		segments[i] = sigV4Escape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func sigV4CanonicalQuery(r *http.Request) string {
+	query := r.URL.Query()
+
+	pairs := make([]string, 0, len(query))
+
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, sigV4Escape(key)+"="+sigV4Escape(value))
+		}
+	}
+
+	slices.Sort(pairs)
+
+	return strings.Join(pairs, "&")
+}
+
+// sigV4Escape percent-encodes all bytes except unreserved characters, with
+// uppercase hex digits.
+func sigV4Escape(value string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+)
+
+// sigV4TestCredentials and the signatures below are from the AWS SigV4
+// test suite, get-vanilla and get-vanilla-query-order-key-case.
+var sigV4TestCredentials = SigV4Credentials{
+	AccessKeyID:     "AKIDEXAMPLE",
+	SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	Region:          "us-east-1",
+	Service:         "service",
+}
+
+func sigV4TestRequest(target, signature string) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, target, http.NoBody)
+	r.Header.Set("X-Amz-Date", "20150830T123600Z")
+	r.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, Signature="+signature)
+
+	return r
+}
+
+func Test_SigV4(t *testing.T) {
+	verify := SigV4(sigV4TestCredentials)
+
+	verify(t, sigV4TestRequest(
+		"http://example.amazonaws.com/",
+		"5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+	))
+
+	verify(t, sigV4TestRequest(
+		"http://example.amazonaws.com/?Param2=value2&Param1=value1",
+		"b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+	))
+}
+
+func Test_SigV4_Mismatch(t *testing.T) {
+	credentials := sigV4TestCredentials
+	credentials.AccessKeyID = "AKIDOTHER"
+	credentials.Region = "eu-west-1"
+
+	SigV4(credentials)(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "sigv4, wrong %s, expected %s, actual %s", args: []any{"access key", "AKIDOTHER", "AKIDEXAMPLE"}},
+			{format: "sigv4, wrong %s, expected %s, actual %s", args: []any{"credential scope region", "eu-west-1", "us-east-1"}},
+		},
+		nil,
+	)(t), sigV4TestRequest("http://example.amazonaws.com/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"))
+
+	reporter := &testReporterMock{t: t}
+
+	SigV4(sigV4TestCredentials)(reporter, sigV4TestRequest(
+		"http://example.amazonaws.com/?Param1=value2",
+		"b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+	))
+
+	if len(reporter.errorfCalls) != 1 || reporter.errorfCalls[0].format != "sigv4, signature mismatch, expected %s, actual %s, canonical request:\n%s" {
+		t.Fatalf("expect signature mismatch, actual %+v", reporter.errorfCalls)
+	}
+}
+
+func Test_WithRequestVerifier(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{{format: "1 call, sigv4, Authorization header not presented"}},
+			nil,
+		)(t),
+		SequenceCalls(Call{Input: Input{Method: http.MethodGet}}),
+		nil,
+		WithRequestVerifier(SigV4(sigV4TestCredentials)),
+	)
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://example.amazonaws.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
@@ -0,0 +1,117 @@
+package httpmock
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SlowHeaders delays parts of the response head, so client header timeouts,
+// e.g. http.Transport.ResponseHeaderTimeout, can be tested apart from body
+// timeouts.
+type SlowHeaders struct {
+	// BeforeStatus delays the status line.
+	BeforeStatus time.Duration
+	// PerHeader delays every header line.
+	PerHeader time.Duration
+	// BeforeBody delays the body after the head is complete.
+	BeforeBody time.Duration
+}
+
+// ServeSlowHeaders compares the input and writes the rendered response
+// straight to the hijacked connection with the delays, then closes the
+// connection. Every part is flushed as soon as it is written. It needs
+// NewServer, other response writers cannot be hijacked.
+func ServeSlowHeaders(slow SlowHeaders) HandleCall {
+	return func(t TestReporter, w http.ResponseWriter, r *http.Request, call Call) {
+		rewind := rewindableRequestBody(r, call)
+
+		CompareInput(t, r, call.Input)
+
+		rewind()
+
+		response, err := RenderResponse(withPathVars(r, call.Input.URL), call.Response)
+		if err != nil {
+			t.Errorf(err.Error())
+
+			return
+		}
+
+		var body []byte
+
+		if response.Body != nil {
+			body, err = response.Body.Bytes()
+			if err != nil {
+				t.Errorf("slow headers, read response body, %s", err)
+
+				return
+			}
+		}
+
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Errorf("slow headers, response writer does not support hijacking, NewServer expected")
+
+			return
+		}
+
+		conn, buf, err := hijacker.Hijack()
+		if err != nil {
+			t.Errorf("slow headers, hijack connection, %s", err)
+
+			return
+		}
+
+		defer conn.Close()
+
+		writeSlowResponse(buf.Writer, response, body, slow)
+	}
+}
+
+// writeSlowResponse stops at the first write error, the client gave up.
+func writeSlowResponse(w *bufio.Writer, response Response, body []byte, slow SlowHeaders) {
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	header := response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	header.Set("Connection", "close")
+
+	write := func(delay time.Duration, data []byte) bool {
+		time.Sleep(delay)
+
+		_, err := w.Write(data)
+		if err != nil {
+			return false
+		}
+
+		return w.Flush() == nil
+	}
+
+	statusLine := fmt.Sprintf("HTTP/1.1 %03d %s\r\n", statusCode, http.StatusText(statusCode))
+	if !write(slow.BeforeStatus, []byte(statusLine)) {
+		return
+	}
+
+	for _, key := range sortedKeys(header) {
+		for _, value := range header[key] {
+			if !write(slow.PerHeader, []byte(key+": "+value+"\r\n")) {
+				return
+			}
+		}
+	}
+
+	if !write(0, []byte("\r\n")) {
+		return
+	}
+
+	write(slow.BeforeBody, body)
+}
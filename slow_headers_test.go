@@ -0,0 +1,73 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func slowHeadersServer(t *testing.T, slow SlowHeaders) *Server {
+	return NewServer(t,
+		StaticCalls(Call{
+			Input: Input{Method: http.MethodGet},
+			Response: Response{
+				Header: http.Header{"X-First": {"1"}, "X-Second": {"2"}},
+				Body:   RawBody("ok"),
+			},
+		}),
+		ServeSlowHeaders(slow),
+	)
+}
+
+func Test_ServeSlowHeaders_HeaderTimeout(t *testing.T) {
+	server := slowHeadersServer(t, SlowHeaders{PerHeader: 50 * time.Millisecond})
+
+	client := &http.Client{Transport: &http.Transport{ResponseHeaderTimeout: 60 * time.Millisecond}}
+
+	_, err := client.Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "timeout awaiting response headers") {
+		t.Fatalf("expect response header timeout, actual %v", err)
+	}
+}
+
+func Test_ServeSlowHeaders_SlowBody(t *testing.T) {
+	server := slowHeadersServer(t, SlowHeaders{BeforeBody: 100 * time.Millisecond})
+
+	client := &http.Client{Transport: &http.Transport{ResponseHeaderTimeout: 50 * time.Millisecond}}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.Header.Get("X-Second") != "2" {
+		t.Errorf("wrong X-Second header, expected 2, actual %s", resp.Header.Get("X-Second"))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "ok" {
+		t.Errorf("wrong body, expected ok, actual %s, %v", body, err)
+	}
+}
+
+func Test_ServeSlowHeaders_NotHijackable(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{{format: "1 call, slow headers, response writer does not support hijacking, NewServer expected"}},
+			nil,
+		)(t),
+		SequenceCalls(Call{Input: Input{Method: http.MethodGet}}),
+		ServeSlowHeaders(SlowHeaders{}),
+	)
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://localhost/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
@@ -0,0 +1,138 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// UpdateSnapshotsEnv rewrites snapshot files instead of comparing them when
+// set to a non empty value.
+const UpdateSnapshotsEnv = "HTTPMOCK_UPDATE_SNAPSHOTS"
+
+// NewSnapshotTransport records every exchange passing through base and at
+// Cleanup compares their canonical text form with the snapshot file,
+// failing with a unified diff. Exchanges are written in completion order,
+// ignoreHeaders are excluded from the snapshot.
+func NewSnapshotTransport(t TestReporter, path string, base http.RoundTripper, ignoreHeaders ...string) http.RoundTripper {
+	cassette := &Cassette{}
+
+	t.Cleanup(func() {
+		assertSnapshot(t, path, FormatExchanges(cassette.Exchanges(), ignoreHeaders...))
+	})
+
+	return NewRecordingTransport(base, cassette)
+}
+
+func assertSnapshot(t TestReporter, path, actual string) {
+	if os.Getenv(UpdateSnapshotsEnv) != "" {
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err == nil {
+			err = os.WriteFile(path, []byte(actual), 0o644)
+		}
+
+		if err != nil {
+			t.Errorf("update snapshot %s, %s", path, err)
+		}
+
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("read snapshot, %s, run with %s=1 to create it", err, UpdateSnapshotsEnv)
+
+		return
+	}
+
+	diff := unifiedDiff(path, "actual", string(expected), actual)
+	if diff != "" {
+		t.Errorf("snapshot %s mismatch, run with %s=1 to update it\n%s", path, UpdateSnapshotsEnv, diff)
+	}
+}
+
+// FormatExchanges renders exchanges in a canonical text form: sorted query
+// and headers, indented JSON bodies.
+func FormatExchanges(exchanges []Exchange, ignoreHeaders ...string) string {
+	ignored := make(map[string]struct{}, len(ignoreHeaders))
+	for _, key := range ignoreHeaders {
+		ignored[http.CanonicalHeaderKey(key)] = struct{}{}
+	}
+
+	var builder strings.Builder
+
+	for i, exchange := range exchanges {
+		if i > 0 {
+			builder.WriteByte('\n')
+		}
+
+		fmt.Fprintf(&builder, "### %d\n", i+1)
+		fmt.Fprintf(&builder, "%s %s\n", exchange.Request.Method, canonicalURL(exchange.Request.URL))
+		writeCanonicalHeader(&builder, exchange.Request.Header, ignored)
+		writeCanonicalBody(&builder, exchange.Request.Body)
+
+		builder.WriteString("\n--- response\n")
+		fmt.Fprintf(&builder, "%d %s\n", exchange.Response.StatusCode, http.StatusText(exchange.Response.StatusCode))
+		writeCanonicalHeader(&builder, exchange.Response.Header, ignored)
+		writeCanonicalBody(&builder, exchange.Response.Body)
+	}
+
+	return builder.String()
+}
+
+func canonicalURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	u.RawQuery = u.Query().Encode()
+
+	return u.RequestURI()
+}
+
+func writeCanonicalHeader(builder *strings.Builder, header http.Header, ignored map[string]struct{}) {
+	keys := make([]string, 0, len(header))
+
+	for key := range header {
+		if _, ok := ignored[http.CanonicalHeaderKey(key)]; ok {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(builder, "%s: %s\n", key, value)
+		}
+	}
+}
+
+func writeCanonicalBody(builder *strings.Builder, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	builder.WriteByte('\n')
+
+	var indented bytes.Buffer
+
+	if json.Valid(body) && json.Indent(&indented, body, "", "  ") == nil {
+		body = indented.Bytes()
+	}
+
+	builder.Write(body)
+
+	if !bytes.HasSuffix(body, []byte("\n")) {
+		builder.WriteByte('\n')
+	}
+}
@@ -0,0 +1,132 @@
+package httpmock
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func snapshotCalls() Calls {
+	return SequenceCalls(
+		Call{
+			Input: Input{
+				Method: http.MethodPost,
+				Body:   RawBody(`{"name":"dima","age":10}`),
+			},
+			Response: Response{
+				StatusCode: http.StatusCreated,
+				Header:     http.Header{"Content-Type": {"application/json"}, "Date": {"Mon, 01 Jan 2024 00:00:00 GMT"}},
+				Body:       RawBody(`{"id":1}`),
+			},
+		},
+	)
+}
+
+func doSnapshotRequest(name string) func(client *http.Client) error {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+
+	return doUncheckedResponse(
+		request{
+			method: http.MethodPost,
+			target: "http://api.example.com/users?b=2&a=1",
+			body:   strings.NewReader(`{"name":"` + name + `","age":10}`),
+			header: header,
+		},
+	)
+}
+
+const expectedSnapshot = `### 1
+POST /users?a=1&b=2
+Content-Type: application/json
+
+{
+  "name": "dima",
+  "age": 10
+}
+
+--- response
+201 Created
+Content-Type: application/json
+
+{
+  "id": 1
+}
+`
+
+func Test_NewSnapshotTransport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots", "create_user.txt")
+
+	t.Run("update", func(t *testing.T) {
+		t.Setenv(UpdateSnapshotsEnv, "1")
+
+		client := &http.Client{
+			Transport: NewSnapshotTransport(t, path, NewTransport(t, snapshotCalls(), nil), "Date"),
+		}
+
+		err := doSnapshotRequest("dima")(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read snapshot, unexpected error: %s", err)
+	}
+
+	if string(data) != expectedSnapshot {
+		t.Fatalf("wrong snapshot,\nexpected:\n%s\nactual:\n%s", expectedSnapshot, data)
+	}
+
+	t.Run("match", func(t *testing.T) {
+		client := &http.Client{
+			Transport: NewSnapshotTransport(t, path, NewTransport(t, snapshotCalls(), nil), "Date"),
+		}
+
+		err := doSnapshotRequest("dima")(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		tr := ExpectFailureTestReporter(
+			[]testReporterCall{
+				{
+					format: "1 call, body not equal, expected %s actual %s",
+					args:   []any{`{"name":"dima","age":10}`, `{"name":"amidman","age":10}`},
+				},
+				{
+					format: "snapshot %s mismatch, run with %s=1 to update it\n%s",
+					args: []any{
+						path,
+						UpdateSnapshotsEnv,
+						"--- " + path + "\n+++ actual\n" +
+							"@@ -3,7 +3,7 @@\n" +
+							" Content-Type: application/json\n" +
+							" \n" +
+							" {\n" +
+							`-  "name": "dima",` + "\n" +
+							`+  "name": "amidman",` + "\n" +
+							`   "age": 10` + "\n" +
+							" }\n" +
+							" \n",
+					},
+				},
+			},
+			nil,
+		)(t)
+
+		client := &http.Client{
+			Transport: NewSnapshotTransport(tr, path, NewTransport(tr, snapshotCalls(), nil), "Date"),
+		}
+
+		err := doSnapshotRequest("amidman")(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package httpmock
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Soak drives a client against the mock, usually served with StaticCalls,
+// for Duration or Iterations, whichever ends first, with Concurrency
+// workers, see RunSoak.
+type Soak struct {
+	Duration    time.Duration
+	Iterations  int
+	Concurrency int
+	// MaxErrorRate and MaxP99 fail the soak when exceeded, zero values are
+	// not checked.
+	MaxErrorRate float64
+	MaxP99       time.Duration
+}
+
+// SoakResult is the error rate and latency percentiles of a soak.
+type SoakResult struct {
+	Iterations int
+	Errors     int
+	ErrorRate  float64
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+}
+
+func (r SoakResult) String() string {
+	return fmt.Sprintf("soak, %d iterations, %d errors, error rate %.2f%%, p50 %s, p90 %s, p99 %s, max %s",
+		r.Iterations, r.Errors, r.ErrorRate*100, r.P50, r.P90, r.P99, r.Max,
+	)
+}
+
+// RunSoak calls do with the iteration number starting from 1 until the soak
+// ends, the context is canceled when the soak duration is over. The result
+// is logged with Logf when the reporter has it, exceeded limits are
+// reported with Errorf.
+func RunSoak(t TestReporter, soak Soak, do func(ctx context.Context, iteration int) error) SoakResult {
+	if soak.Duration <= 0 && soak.Iterations <= 0 {
+		t.Fatalf("soak, Duration or Iterations expected")
+
+		return SoakResult{}
+	}
+
+	ctx := context.Background()
+
+	if soak.Duration > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, soak.Duration)
+		defer cancel()
+	}
+
+	var (
+		next      atomic.Int64
+		errs      atomic.Int64
+		mu        sync.Mutex
+		latencies []time.Duration
+		wg        sync.WaitGroup
+	)
+
+	for range max(soak.Concurrency, 1) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for ctx.Err() == nil {
+				iteration := int(next.Add(1))
+				if soak.Iterations > 0 && iteration > soak.Iterations {
+					return
+				}
+
+				start := time.Now()
+				err := do(ctx, iteration)
+				latency := time.Since(start)
+
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+
+					errs.Add(1)
+				}
+
+				mu.Lock()
+				latencies = append(latencies, latency)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	result := soakResult(latencies, int(errs.Load()))
+
+	if logger, ok := t.(interface {
+		Logf(format string, args ...any)
+	}); ok {
+		logger.Logf("%s", result)
+	}
+
+	if soak.MaxErrorRate > 0 && result.ErrorRate > soak.MaxErrorRate {
+		t.Errorf("soak, error rate %.2f%% exceeds %.2f%%", result.ErrorRate*100, soak.MaxErrorRate*100)
+	}
+
+	if soak.MaxP99 > 0 && result.P99 > soak.MaxP99 {
+		t.Errorf("soak, p99 latency %s exceeds %s", result.P99, soak.MaxP99)
+	}
+
+	return result
+}
+
+func soakResult(latencies []time.Duration, errs int) SoakResult {
+	result := SoakResult{
+		Iterations: len(latencies),
+		Errors:     errs,
+	}
+
+	if len(latencies) == 0 {
+		return result
+	}
+
+	slices.Sort(latencies)
+
+	percentile := func(p float64) time.Duration {
+		return latencies[int(p*float64(len(latencies)-1))]
+	}
+
+	result.ErrorRate = float64(errs) / float64(len(latencies))
+	result.P50 = percentile(0.5)
+	result.P90 = percentile(0.9)
+	result.P99 = percentile(0.99)
+	result.Max = latencies[len(latencies)-1]
+
+	return result
+}
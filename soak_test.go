@@ -0,0 +1,73 @@
+package httpmock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_RunSoak(t *testing.T) {
+	transport := NewTransport(t,
+		Degrade(
+			StaticCalls(Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: RawBody("ok")}}),
+			3,
+			FlakyErrors(0.1, http.StatusServiceUnavailable),
+		),
+		nil,
+	)
+
+	client := &http.Client{Transport: transport}
+
+	errUnavailable := errors.New("unavailable")
+
+	reporter := &testReporterMock{t: t}
+
+	result := RunSoak(
+		reporter,
+		Soak{Iterations: 500, Concurrency: 8, MaxErrorRate: 0.01, MaxP99: time.Second},
+		func(ctx context.Context, _ int) error {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/items", http.NoBody)
+
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return errUnavailable
+			}
+
+			return nil
+		},
+	)
+
+	if len(reporter.errorfCalls) != 1 || reporter.errorfCalls[0].format != "soak, error rate %.2f%% exceeds %.2f%%" {
+		t.Errorf("expect error rate failure, actual %+v", reporter.errorfCalls)
+	}
+
+	if result.Iterations != 500 {
+		t.Errorf("wrong iterations, expected 500, actual %d", result.Iterations)
+	}
+
+	if result.ErrorRate < 0.05 || result.ErrorRate > 0.15 {
+		t.Errorf("wrong error rate, expected about 0.1, actual %f", result.ErrorRate)
+	}
+}
+
+func Test_RunSoak_Duration(t *testing.T) {
+	result := RunSoak(t, Soak{Duration: 50 * time.Millisecond, Concurrency: 2}, func(ctx context.Context, _ int) error {
+		time.Sleep(time.Millisecond)
+
+		return nil
+	})
+
+	if result.Iterations == 0 || result.Errors != 0 || result.Max < time.Millisecond {
+		t.Errorf("wrong soak result, %s", result)
+	}
+}
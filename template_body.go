@@ -0,0 +1,304 @@
+package httpmock
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errTemplateNoRequest = errors.New("template references request, but no request given")
+
+type templateBody struct {
+	template  string
+	generator *Generator
+}
+
+// TemplateBody renders the template for every request, placeholders look like
+// {{request.path}} and are resolved against the incoming request:
+//
+//	request.method, request.url, request.path, request.host, request.scheme,
+//	request.baseURL, request.pathSegments.[i], request.pathVars.<name>,
+//	request.query.<name>[.[i]], request.headers.<name>[.[i]], request.body
+//
+// request.baseURL is scheme://host the request was sent to.
+// request.pathVars are {name} segments of the Input.URL path, e.g.
+// /users/{id}, filled by HandleCallCompareInput.
+//
+// Helpers generating values like {{uuid}}, {{now}} and {{seq}} are described
+// in Generator, TemplateBody uses the generator of the transport, see
+// WithGenerator, or DefaultGenerator when rendered without one. Session
+// values are described in Session.
+//
+// Triple braces {{{...}}} are accepted as well, values are never escaped.
+func TemplateBody(template string) Body {
+	return templateBody{template: template}
+}
+
+func (b templateBody) Bytes() ([]byte, error) {
+	return b.RequestBytes(nil)
+}
+
+func (b templateBody) RequestBytes(r *http.Request) ([]byte, error) {
+	rendered, err := renderTemplate(b.template, &templateContext{request: r, generator: b.generator})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(rendered), nil
+}
+
+// bodyGenerator returns the generator of a Generator.Body body, nil for
+// other bodies.
+func bodyGenerator(body Body) *Generator {
+	if body, ok := body.(templateBody); ok {
+		return body.generator
+	}
+
+	return nil
+}
+
+func renderHeader(r *http.Request, generator *Generator, header, templateHeader http.Header) (http.Header, error) {
+	rendered := header.Clone()
+	if rendered == nil {
+		rendered = make(http.Header, len(templateHeader))
+	}
+
+	tc := &templateContext{request: r, generator: generator}
+
+	for _, key := range sortedKeys(templateHeader) {
+		rendered.Del(key)
+
+		for _, template := range templateHeader[key] {
+			value, err := renderTemplate(template, tc)
+			if err != nil {
+				return nil, fmt.Errorf("%s, %w", key, err)
+			}
+
+			rendered.Add(key, value)
+		}
+	}
+
+	return rendered, nil
+}
+
+type templateContext struct {
+	request     *http.Request
+	generator   *Generator
+	body        []byte
+	bodyLoaded  bool
+	bodyLoadErr error
+}
+
+func (c *templateContext) requestBody() ([]byte, error) {
+	if c.bodyLoaded {
+		return c.body, c.bodyLoadErr
+	}
+
+	c.bodyLoaded = true
+
+	if c.request.Body == nil {
+		return nil, nil
+	}
+
+	c.body, c.bodyLoadErr = io.ReadAll(c.request.Body)
+	c.request.Body = io.NopCloser(bytes.NewReader(c.body))
+
+	return c.body, c.bodyLoadErr
+}
+
+func renderTemplate(template string, tc *templateContext) (string, error) {
+	var builder strings.Builder
+
+	for {
+		start := strings.Index(template, "{{")
+		if start == -1 {
+			builder.WriteString(template)
+
+			return builder.String(), nil
+		}
+
+		builder.WriteString(template[:start])
+
+		open, closing := "{{", "}}"
+		if strings.HasPrefix(template[start:], "{{{") {
+			open, closing = "{{{", "}}}"
+		}
+
+		rest := template[start+len(open):]
+
+		end := strings.Index(rest, closing)
+		if end == -1 {
+			return "", fmt.Errorf("unclosed template expression at offset %d", start)
+		}
+
+		value, err := evalTemplateExpression(strings.TrimSpace(rest[:end]), tc)
+		if err != nil {
+			return "", fmt.Errorf("render template expression {{%s}}, %w", strings.TrimSpace(rest[:end]), err)
+		}
+
+		builder.WriteString(value)
+
+		template = rest[end+len(closing):]
+	}
+}
+
+// gen prefers the generator of the body, then the one of the transport
+// serving the request.
+func (c *templateContext) gen() *Generator {
+	if c == nil {
+		return DefaultGenerator
+	}
+
+	if c.generator != nil {
+		return c.generator
+	}
+
+	if c.request != nil {
+		if generator := generatorFrom(c.request.Context()); generator != nil {
+			return generator
+		}
+	}
+
+	return DefaultGenerator
+}
+
+// now prefers the clock of the request, see WithClock.
+func (c *templateContext) now() time.Time {
+	if c != nil && c.request != nil {
+		if clock := clockFrom(c.request.Context()); clock != nil {
+			return clock()
+		}
+	}
+
+	return c.gen().now()
+}
+
+func evalTemplateExpression(expression string, tc *templateContext) (string, error) {
+	fields := splitTemplateFields(expression)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty template expression")
+	}
+
+	head := fields[0]
+
+	switch {
+	case head == "request" || strings.HasPrefix(head, "request."):
+		return evalRequestExpression(head, tc)
+	case head == "generated" || strings.HasPrefix(head, "generated."):
+		return tc.gen().evalGenerated(head)
+	case head == "session" || strings.HasPrefix(head, "session."):
+		return evalSessionExpression(fields, tc)
+	}
+
+	value, ok := tc.gen().eval(head, fields[1:], tc.now)
+	if !ok {
+		return "", fmt.Errorf("unknown template expression")
+	}
+
+	return value, nil
+}
+
+func evalRequestExpression(expression string, tc *templateContext) (string, error) {
+	if tc == nil || tc.request == nil {
+		return "", errTemplateNoRequest
+	}
+
+	r := tc.request
+
+	parts := strings.Split(expression, ".")[1:]
+	if len(parts) == 0 {
+		return "", fmt.Errorf("request field not specified")
+	}
+
+	switch parts[0] {
+	case "method":
+		return r.Method, nil
+	case "url":
+		return r.URL.RequestURI(), nil
+	case "path":
+		return r.URL.Path, nil
+	case "host":
+		return r.Host, nil
+	case "scheme":
+		return r.URL.Scheme, nil
+	case "baseURL":
+		return requestBaseURL(r), nil
+	case "body":
+		body, err := tc.requestBody()
+		if err != nil {
+			return "", fmt.Errorf("read request body, %w", err)
+		}
+
+		return string(body), nil
+	case "pathVars":
+		if len(parts) < 2 {
+			return "", fmt.Errorf("path variable name not specified")
+		}
+
+		return pathVars(r)[parts[1]], nil
+	case "pathSegments":
+		segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+		return templateIndex(segments, parts[1:])
+	case "query":
+		if len(parts) < 2 {
+			return "", fmt.Errorf("query parameter name not specified")
+		}
+
+		return templateIndex(r.URL.Query()[parts[1]], parts[2:])
+	case "headers":
+		if len(parts) < 2 {
+			return "", fmt.Errorf("header name not specified")
+		}
+
+		return templateIndex(r.Header.Values(parts[1]), parts[2:])
+	default:
+		return "", fmt.Errorf("unknown request field %s", parts[0])
+	}
+}
+
+// requestBaseURL is the scheme and host the request was sent to, server
+// requests have no scheme in the url, it is taken from the connection.
+func requestBaseURL(r *http.Request) string {
+	scheme := r.URL.Scheme
+	if scheme == "" {
+		scheme = "http"
+
+		if r.TLS != nil {
+			scheme = "https"
+		}
+	}
+
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+
+	return scheme + "://" + host
+}
+
+func templateIndex(values []string, parts []string) (string, error) {
+	if len(parts) == 0 {
+		if len(values) == 0 {
+			return "", nil
+		}
+
+		return values[0], nil
+	}
+
+	index, err := strconv.Atoi(strings.Trim(parts[0], "[]"))
+	if err != nil {
+		return "", fmt.Errorf("parse index %s, %w", parts[0], err)
+	}
+
+	if index < 0 || index >= len(values) {
+		return "", nil
+	}
+
+	return values[index], nil
+}
@@ -0,0 +1,197 @@
+package httpmock
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type templateBodyTest struct {
+	Name          string
+	Template      string
+	Request       *http.Request
+	ExpectedBytes []byte
+	ExpectedError error
+}
+
+func (tb *templateBodyTest) Test(t *testing.T) {
+	body := TemplateBody(tb.Template).(RequestBody)
+
+	bytes, err := body.RequestBytes(tb.Request)
+	if !errors.Is(err, tb.ExpectedError) {
+		t.Fatalf("wrong error, expected %v, actual %v", tb.ExpectedError, err)
+	}
+
+	if !slices.Equal(bytes, tb.ExpectedBytes) {
+		t.Errorf("wrong rendered body, expected %s, actual %s", tb.ExpectedBytes, bytes)
+	}
+}
+
+func Test_TemplateBody(t *testing.T) {
+	newRequest := func() *http.Request {
+		r, err := http.NewRequest(http.MethodPut, "http://localhost:1000/users/10?tag=a&tag=b", strings.NewReader(`{"name":"dima"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r.Header.Add("X-Request-Id", "abc")
+
+		return r
+	}
+
+	tests := []*templateBodyTest{
+		{
+			Name:          "plain text",
+			Template:      "Hello World!",
+			Request:       newRequest(),
+			ExpectedBytes: []byte("Hello World!"),
+		},
+		{
+			Name:          "request fields",
+			Template:      `{{request.method}} {{ request.url }} {{request.host}} {{request.pathSegments.[0]}}/{{request.pathSegments.1}}`,
+			Request:       newRequest(),
+			ExpectedBytes: []byte("PUT /users/10?tag=a&tag=b localhost:1000 users/10"),
+		},
+		{
+			Name:          "query, headers and body",
+			Template:      `{{request.query.tag}},{{request.query.tag.[1]}},{{request.query.missing}};{{request.headers.X-Request-Id}};{{{request.body}}}`,
+			Request:       newRequest(),
+			ExpectedBytes: []byte(`a,b,;abc;{"name":"dima"}`),
+		},
+		{
+			Name:          "no request",
+			Template:      "{{request.path}}",
+			ExpectedError: errTemplateNoRequest,
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.Name, tst.Test)
+	}
+}
+
+func Test_TemplateBody_Errors(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	templates := map[string]string{
+		"{{request.path":      "unclosed template expression at offset 0",
+		"{{unknown}}":         "render template expression {{unknown}}, unknown template expression",
+		"{{request.cookies}}": "render template expression {{request.cookies}}, unknown request field cookies",
+	}
+
+	for template, expectedErr := range templates {
+		_, err := TemplateBody(template).(RequestBody).RequestBytes(r)
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("wrong error for %s, expected %s, actual %v", template, expectedErr, err)
+		}
+	}
+}
+
+func Test_TemplateBody_Transport(t *testing.T) {
+	runTransportTests(t,
+		&transportTest{
+			Name:         "echo request body after comparison",
+			TestReporter: ExpectSuccessTestReporter,
+			Calls: SequenceCalls(
+				Call{
+					Input: Input{
+						Method: http.MethodPost,
+						Body:   RawBody("Hello World!"),
+					},
+					Response: Response{
+						StatusCode: http.StatusOK,
+						Body:       TemplateBody("echo: {{request.body}}"),
+					},
+				},
+			),
+			Execute: do(
+				request{
+					method: http.MethodPost,
+					target: "/echo",
+					body:   strings.NewReader("Hello World!"),
+				},
+				Response{
+					StatusCode: http.StatusOK,
+					Body:       RawBody("echo: Hello World!"),
+				},
+			),
+		},
+		&transportTest{
+			Name: "render error reported",
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "1 call, render response body, render template expression {{request.cookies}}, unknown request field cookies",
+					},
+				},
+				nil,
+			),
+			Calls: SequenceCalls(
+				Call{
+					Input: Input{
+						Method: http.MethodGet,
+					},
+					Response: Response{
+						Body: TemplateBody("{{request.cookies}}"),
+					},
+				},
+			),
+			Execute: doUncheckedResponse(
+				request{
+					method: http.MethodGet,
+					target: "/",
+				},
+			),
+		},
+	)
+}
+
+func Test_TemplateHeader_PathVarsAndClock(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	transport := NewTransport(t,
+		SequenceCalls(Call{
+			Input: Input{
+				Method: http.MethodPut,
+				URL:    mustParseURL("http://localhost/users/{id}"),
+			},
+			Response: Response{
+				StatusCode: http.StatusOK,
+				TemplateHeader: http.Header{
+					"Location":     {"/users/{{request.pathVars.id}}/profile"},
+					"X-Request-Id": {"{{request.headers.X-Request-Id}}"},
+					"Expires":      {"{{now HTTP offset=1h}}"},
+				},
+			},
+		}),
+		nil,
+		WithClock(func() time.Time { return now }),
+	)
+
+	req, err := http.NewRequest(http.MethodPut, "http://localhost/users/42", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("X-Request-Id", "abc")
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	CompareHeader(t, resp.Header, http.Header{
+		"Date":         {"Fri, 01 Mar 2024 12:00:00 GMT"},
+		"Location":     {"/users/42/profile"},
+		"X-Request-Id": {"abc"},
+		"Expires":      {"Fri, 01 Mar 2024 13:00:00 GMT"},
+	})
+}
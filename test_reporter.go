@@ -1,6 +1,10 @@
 package httpmock
 
-import "fmt"
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
 
 type TestReporter interface {
 	Errorf(format string, args ...any)
@@ -9,11 +13,78 @@ type TestReporter interface {
 	Cleanup(func())
 }
 
+// helper is implemented by testing.TB, Compare functions call Helper when
+// the reporter has it, so failures point to the caller.
+type helper interface {
+	Helper()
+}
+
 func errorfTestReporterWithCallNumber(t TestReporter, number int64) TestReporter {
+	return callNumberTestReporter{
+		TestReporter: t,
+		number:       number,
+	}
+}
+
+// callNumberTestReporter formats the prefix on Errorf only, so passing calls
+// don't pay for it.
+type callNumberTestReporter struct {
+	TestReporter
+	number int64
+	labels map[string]string
+}
+
+func (c callNumberTestReporter) Errorf(format string, args ...any) {
+	if h, ok := c.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
+	prefix := strconv.FormatInt(c.number, 10) + " call"
+	if len(c.labels) > 0 {
+		prefix += " [" + formatLabels(c.labels) + "]"
+	}
+
+	c.TestReporter.Errorf(prefix+", "+format, args...)
+}
+
+// withLabels adds the call labels to the prefix of reported errors.
+func withLabels(t TestReporter, labels map[string]string) TestReporter {
+	if len(labels) == 0 {
+		return t
+	}
+
+	if c, ok := t.(callNumberTestReporter); ok {
+		c.labels = labels
+
+		return c
+	}
+
 	return errorfPrefixTestReporter{
 		TestReporter: t,
-		prefix:       fmt.Sprintf("%d call, ", number),
+		prefix:       "[" + formatLabels(labels) + "], ",
+	}
+}
+
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+
+	for key := range labels {
+		keys = append(keys, key)
 	}
+
+	slices.Sort(keys)
+
+	var builder strings.Builder
+
+	for i, key := range keys {
+		if i > 0 {
+			builder.WriteByte(' ')
+		}
+
+		builder.WriteString(key + "=" + labels[key])
+	}
+
+	return builder.String()
 }
 
 type errorfPrefixTestReporter struct {
@@ -22,6 +93,10 @@ type errorfPrefixTestReporter struct {
 }
 
 func (p errorfPrefixTestReporter) Errorf(format string, args ...any) {
+	if h, ok := p.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
 	p.TestReporter.Errorf(p.prefix+format, args...)
 }
 
@@ -0,0 +1,91 @@
+package httpmock
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Timeline records when every call was matched, the zero value is ready to
+// use, see WithTimeline. Assertions validate client pacing, debouncing and
+// polling intervals.
+type Timeline struct {
+	mu    sync.Mutex
+	times map[int]time.Time
+}
+
+// WithTimeline records the time of every matched call to tl.
+func WithTimeline(tl *Timeline) Option {
+	return func(o *options) {
+		o.timeline = tl
+	}
+}
+
+func (tl *Timeline) record(calledTimes int) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if tl.times == nil {
+		tl.times = make(map[int]time.Time)
+	}
+
+	tl.times[calledTimes] = time.Now()
+}
+
+// At returns the time the call was matched, calls are numbered from 1.
+func (tl *Timeline) At(call int) (time.Time, bool) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	at, ok := tl.times[call]
+
+	return at, ok
+}
+
+// Len returns the count of recorded calls.
+func (tl *Timeline) Len() int {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	return len(tl.times)
+}
+
+// AssertAfter reports when call happened less than minGap after previous.
+func (tl *Timeline) AssertAfter(t TestReporter, call, previous int, minGap time.Duration) {
+	previousAt, ok := tl.At(previous)
+	if !ok {
+		t.Errorf("call %d not recorded", previous)
+
+		return
+	}
+
+	callAt, ok := tl.At(call)
+	if !ok {
+		t.Errorf("call %d not recorded", call)
+
+		return
+	}
+
+	if gap := callAt.Sub(previousAt); gap < minGap {
+		t.Errorf("call %d happened %s after call %d, expected at least %s", call, gap.Round(time.Millisecond), previous, minGap)
+	}
+}
+
+// AssertSpacing reports every pair of consecutive calls spaced less than
+// minGap apart.
+func (tl *Timeline) AssertSpacing(t TestReporter, minGap time.Duration) {
+	tl.mu.Lock()
+
+	calls := make([]int, 0, len(tl.times))
+	for call := range tl.times {
+		calls = append(calls, call)
+	}
+
+	tl.mu.Unlock()
+
+	slices.Sort(calls)
+
+	for i := 1; i < len(calls); i++ {
+		tl.AssertAfter(t, calls[i], calls[i-1], minGap)
+	}
+}
@@ -0,0 +1,53 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_Timeline(t *testing.T) {
+	timeline := &Timeline{}
+
+	transport := NewTransport(t,
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}}),
+		nil,
+		WithTimeline(timeline),
+	)
+
+	client := &http.Client{Transport: transport}
+
+	for i := range 3 {
+		if i == 2 {
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		resp, err := client.Get("http://localhost/status")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	if timeline.Len() != 3 {
+		t.Fatalf("wrong recorded calls count, expected 3, actual %d", timeline.Len())
+	}
+
+	timeline.AssertAfter(t, 3, 2, 50*time.Millisecond)
+
+	reporter := &testReporterMock{t: t}
+
+	timeline.AssertSpacing(reporter, 50*time.Millisecond)
+
+	if len(reporter.errorfCalls) != 1 || reporter.errorfCalls[0].args[0] != 2 {
+		t.Errorf("expect spacing error for call 2, actual %+v", reporter.errorfCalls)
+	}
+
+	timeline.AssertAfter(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{format: "call %d not recorded", args: []any{4}},
+		},
+		nil,
+	)(t), 4, 3, time.Second)
+}
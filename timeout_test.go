@@ -0,0 +1,71 @@
+package httpmock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func Test_Call_Timeout(t *testing.T) {
+	call := Call{Input: Input{Method: http.MethodGet}, Timeout: true}
+
+	server := NewStaticServer(t, call)
+
+	transports := map[string]http.RoundTripper{
+		"transport": NewTransport(t, StaticCalls(call), nil),
+		"server":    server.Client().Transport,
+	}
+
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			client := &http.Client{Transport: transport}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, http.NoBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = client.Do(req)
+
+			var urlErr *url.Error
+			if !errors.As(err, &urlErr) || !errors.Is(err, context.DeadlineExceeded) || !urlErr.Timeout() {
+				t.Fatalf("expect *url.Error wrapping context.DeadlineExceeded, actual %v", err)
+			}
+
+			if urlErr.Op != "Get" || urlErr.URL != server.URL {
+				t.Errorf("wrong url error, op %s, url %s", urlErr.Op, urlErr.URL)
+			}
+
+			client.Timeout = 20 * time.Millisecond
+
+			_, err = client.Get(server.URL)
+			if !errors.As(err, &urlErr) || !urlErr.Timeout() {
+				t.Errorf("expect client timeout error, actual %v", err)
+			}
+		})
+	}
+}
+
+func Test_Call_Timeout_NoDeadline(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "1 call, call timeout, request context has no deadline"},
+			},
+			nil,
+		)(t),
+		SequenceCalls(Call{Input: Input{Method: http.MethodGet}, Timeout: true}),
+		nil,
+	)
+
+	_, err := (&http.Client{Transport: transport}).Get("http://localhost")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wrong error, expected %s, actual %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,96 @@
+package httpmock
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Timings collects how long every call took to serve, Call.Delay included,
+// the zero value is ready to use, see WithTimings. It is useful when the same
+// mock is reused in benchmark-style tests.
+type Timings struct {
+	mu        sync.Mutex
+	durations map[int]time.Duration
+}
+
+// TimingSummary describes collected durations.
+type TimingSummary struct {
+	Count int
+	Min   time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+}
+
+// WithTimings collects call durations to timings.
+func WithTimings(timings *Timings) Option {
+	return func(o *options) {
+		o.timings = timings
+	}
+}
+
+func (tm *Timings) record(calledTimes int, duration time.Duration) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if tm.durations == nil {
+		tm.durations = make(map[int]time.Duration)
+	}
+
+	tm.durations[calledTimes] = duration
+}
+
+// Durations returns durations of served calls in call order.
+func (tm *Timings) Durations() []time.Duration {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	calls := make([]int, 0, len(tm.durations))
+	for call := range tm.durations {
+		calls = append(calls, call)
+	}
+
+	slices.Sort(calls)
+
+	durations := make([]time.Duration, 0, len(calls))
+	for _, call := range calls {
+		durations = append(durations, tm.durations[call])
+	}
+
+	return durations
+}
+
+// Summary returns the count, bounds, mean and percentiles of durations.
+func (tm *Timings) Summary() TimingSummary {
+	durations := tm.Durations()
+	if len(durations) == 0 {
+		return TimingSummary{}
+	}
+
+	slices.Sort(durations)
+
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+
+	return TimingSummary{
+		Count: len(durations),
+		Min:   durations[0],
+		Max:   durations[len(durations)-1],
+		Mean:  total / time.Duration(len(durations)),
+		P50:   percentile(durations, 50),
+		P90:   percentile(durations, 90),
+		P99:   percentile(durations, 99),
+	}
+}
+
+// percentile uses the nearest-rank method on sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,57 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_Timings(t *testing.T) {
+	timings := &Timings{}
+
+	transport := NewTransport(t,
+		SequenceCalls(
+			Call{Input: Input{Method: http.MethodGet}, Delay: 40 * time.Millisecond},
+			Call{Input: Input{Method: http.MethodGet}},
+			Call{Input: Input{Method: http.MethodGet}, Delay: 20 * time.Millisecond},
+		),
+		nil,
+		WithTimings(timings),
+	)
+
+	client := &http.Client{Transport: transport}
+
+	for range 3 {
+		resp, err := client.Get("http://localhost/status")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	durations := timings.Durations()
+	if len(durations) != 3 {
+		t.Fatalf("wrong durations count, expected 3, actual %d", len(durations))
+	}
+
+	if durations[0] < 40*time.Millisecond || durations[2] < 20*time.Millisecond {
+		t.Errorf("durations do not include delay, actual %v", durations)
+	}
+
+	summary := timings.Summary()
+
+	if summary.Count != 3 || summary.Max != durations[0] || summary.Min != durations[1] || summary.P50 != durations[2] {
+		t.Errorf("wrong summary %+v for durations %v", summary, durations)
+	}
+
+	if summary.P99 != summary.Max {
+		t.Errorf("wrong p99, expected %s, actual %s", summary.Max, summary.P99)
+	}
+}
+
+func Test_Timings_Empty(t *testing.T) {
+	if summary := (&Timings{}).Summary(); summary != (TimingSummary{}) {
+		t.Errorf("expect empty summary, actual %+v", summary)
+	}
+}
@@ -0,0 +1,113 @@
+package httpmock
+
+import (
+	"crypto/tls"
+	"net"
+	"slices"
+)
+
+// WithTLS makes NewServer serve https with the config, a zero config is
+// used when nil. Server.Client trusts the server certificate, other clients
+// need Server.Certificate in their roots.
+func WithTLS(config *tls.Config) Option {
+	return func(o *options) {
+		if config == nil {
+			config = &tls.Config{}
+		}
+
+		o.tlsConfig = config
+	}
+}
+
+// TLSConnState describes the handshake of a connection the server accepted.
+type TLSConnState struct {
+	RemoteAddr         string
+	Version            uint16
+	CipherSuite        uint16
+	NegotiatedProtocol string
+	ServerName         string
+}
+
+// TLSExpect is the handshake expected from every connection, zero fields
+// are not compared.
+type TLSExpect struct {
+	// MinVersion is the lowest accepted version, e.g. tls.VersionTLS13.
+	MinVersion uint16
+	// CipherSuites are the accepted cipher suites.
+	CipherSuites []uint16
+	// NegotiatedProtocol is the ALPN protocol, e.g. "h2".
+	NegotiatedProtocol string
+	// ServerName is the SNI sent by the client.
+	ServerName string
+}
+
+func (c *connTracker) trackTLS(conn net.Conn) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return
+	}
+
+	state := tlsConn.ConnectionState()
+
+	c.tls = append(c.tls, TLSConnState{
+		RemoteAddr:         conn.RemoteAddr().String(),
+		Version:            state.Version,
+		CipherSuite:        state.CipherSuite,
+		NegotiatedProtocol: state.NegotiatedProtocol,
+		ServerName:         state.ServerName,
+	})
+}
+
+// TLSConns returns handshakes of connections which sent a request, in
+// accept order.
+func (s *Server) TLSConns() []TLSConnState {
+	s.conns.mu.Lock()
+	defer s.conns.mu.Unlock()
+
+	return slices.Clone(s.conns.tls)
+}
+
+// AssertTLS reports connections which handshake differs from expected, e.g.
+// a client ignoring its min version or sending no SNI.
+func (s *Server) AssertTLS(t TestReporter, expect TLSExpect) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	conns := s.TLSConns()
+	if len(conns) == 0 {
+		t.Errorf("no tls connections")
+
+		return
+	}
+
+	for i, conn := range conns {
+		compareTLSConnState(t, i+1, conn, expect)
+	}
+}
+
+func compareTLSConnState(t TestReporter, number int, conn TLSConnState, expect TLSExpect) {
+	if expect.MinVersion != 0 && conn.Version < expect.MinVersion {
+		t.Errorf("connection %d from %s, wrong tls version, expected at least %s, actual %s",
+			number, conn.RemoteAddr, tls.VersionName(expect.MinVersion), tls.VersionName(conn.Version),
+		)
+	}
+
+	if len(expect.CipherSuites) > 0 && !slices.Contains(expect.CipherSuites, conn.CipherSuite) {
+		t.Errorf("connection %d from %s, unexpected cipher suite %s",
+			number, conn.RemoteAddr, tls.CipherSuiteName(conn.CipherSuite),
+		)
+	}
+
+	if expect.NegotiatedProtocol != "" && conn.NegotiatedProtocol != expect.NegotiatedProtocol {
+		t.Errorf("connection %d from %s, wrong alpn protocol, expected %s, actual %s",
+			number, conn.RemoteAddr, expect.NegotiatedProtocol, conn.NegotiatedProtocol,
+		)
+	}
+
+	if expect.ServerName != "" && conn.ServerName != expect.ServerName {
+		t.Errorf("connection %d from %s, wrong sni, expected %s, actual %s",
+			number, conn.RemoteAddr, expect.ServerName, conn.ServerName,
+		)
+	}
+}
@@ -0,0 +1,71 @@
+package httpmock
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+)
+
+func tlsClient(server *Server, config func(*tls.Config)) *http.Client {
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	config(transport.TLSClientConfig)
+
+	return &http.Client{Transport: transport}
+}
+
+func Test_Server_TLS(t *testing.T) {
+	server := NewServer(t,
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}}),
+		HandleCallCompareInput,
+		WithTLS(nil),
+	)
+
+	client := tlsClient(server, func(config *tls.Config) {
+		config.ServerName = "example.com"
+		config.MaxVersion = tls.VersionTLS12
+		config.NextProtos = []string{"http/1.1"}
+	})
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	conns := server.TLSConns()
+	if len(conns) != 1 {
+		t.Fatalf("wrong tls connections count, expected 1, actual %d", len(conns))
+	}
+
+	server.AssertTLS(t, TLSExpect{
+		MinVersion:         tls.VersionTLS12,
+		NegotiatedProtocol: "http/1.1",
+		ServerName:         "example.com",
+	})
+
+	conn := conns[0]
+
+	server.AssertTLS(ExpectFailureTestReporter(
+		[]testReporterCall{
+			{
+				format: "connection %d from %s, wrong tls version, expected at least %s, actual %s",
+				args:   []any{1, conn.RemoteAddr, "TLS 1.3", "TLS 1.2"},
+			},
+			{
+				format: "connection %d from %s, wrong sni, expected %s, actual %s",
+				args:   []any{1, conn.RemoteAddr, "api.example.com", "example.com"},
+			},
+		},
+		nil,
+	)(t), TLSExpect{MinVersion: tls.VersionTLS13, ServerName: "api.example.com"})
+}
+
+func Test_Server_TLS_NoConns(t *testing.T) {
+	server := NewServer(t, StaticCalls(), nil)
+
+	server.AssertTLS(ExpectFailureTestReporter(
+		[]testReporterCall{{format: "no tls connections"}},
+		nil,
+	)(t), TLSExpect{})
+}
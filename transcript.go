@@ -0,0 +1,246 @@
+package httpmock
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// transcriptBodyLimit truncates bodies in the transcript.
+const transcriptBodyLimit = 256
+
+// WithTranscript logs every exchange in request order at Cleanup when the
+// test failed: number, method, url, status and truncated bodies. It helps
+// with interleaving-sensitive failures in parallel tests. The transcript is
+// logged with Logf when the reporter has it, with Errorf otherwise.
+func WithTranscript() Option {
+	return func(o *options) {
+		o.transcript = true
+	}
+}
+
+type exchange struct {
+	number       int64
+	method       string
+	url          string
+	status       int
+	requestBody  limitedBuffer
+	responseBody limitedBuffer
+	done         bool
+	note         string
+}
+
+type transcript struct {
+	failed atomic.Bool
+
+	mu        sync.Mutex
+	exchanges []*exchange
+}
+
+// failureReporter marks the transcript failed on every reported error.
+type failureReporter struct {
+	TestReporter
+	transcript *transcript
+}
+
+func (f failureReporter) Errorf(format string, args ...any) {
+	if h, ok := f.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
+	f.transcript.failed.Store(true)
+	f.TestReporter.Errorf(format, args...)
+}
+
+func (f failureReporter) Fatalf(format string, args ...any) {
+	if h, ok := f.TestReporter.(helper); ok {
+		h.Helper()
+	}
+
+	f.transcript.failed.Store(true)
+	f.TestReporter.Fatalf(format, args...)
+}
+
+func (tr *transcript) start(number int64, r *http.Request) *exchange {
+	e := &exchange{
+		number: number,
+		method: r.Method,
+		url:    r.URL.String(),
+	}
+
+	tr.mu.Lock()
+	tr.exchanges = append(tr.exchanges, e)
+	tr.mu.Unlock()
+
+	return e
+}
+
+// record wraps w and r.Body to capture the exchange, finish is called when
+// the call is served.
+func (tr *transcript) record(number int64, w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request, func()) {
+	e := tr.start(number, r)
+
+	if r.Body != nil {
+		r.Body = readCloser{
+			Reader: io.TeeReader(r.Body, &e.requestBody),
+			Closer: r.Body,
+		}
+	}
+
+	rw := &transcriptWriter{ResponseWriter: w, exchange: e}
+
+	return rw, r, func() {
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+
+		e.done = true
+
+		if e.status == 0 {
+			e.status = http.StatusOK
+		}
+	}
+}
+
+func (tr *transcript) note(number int64, r *http.Request, note string) {
+	e := tr.start(number, r)
+
+	tr.mu.Lock()
+	e.done = true
+	e.note = note
+	tr.mu.Unlock()
+}
+
+func (tr *transcript) print(t TestReporter) {
+	failed := tr.failed.Load()
+	if reporter, ok := t.(interface{ Failed() bool }); ok && reporter.Failed() {
+		failed = true
+	}
+
+	if !failed {
+		return
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	var builder strings.Builder
+
+	builder.WriteString("httpmock transcript:")
+
+	for _, e := range tr.exchanges {
+		fmt.Fprintf(&builder, "\n  %d %s %s", e.number, e.method, e.url)
+
+		switch {
+		case e.note != "":
+			fmt.Fprintf(&builder, " -> %s", e.note)
+		case !e.done:
+			builder.WriteString(" -> pending")
+		default:
+			fmt.Fprintf(&builder, " -> %d", e.status)
+		}
+
+		if e.requestBody.Len() > 0 {
+			fmt.Fprintf(&builder, "\n    request: %s", e.requestBody.String())
+		}
+
+		if e.responseBody.Len() > 0 {
+			fmt.Fprintf(&builder, "\n    response: %s", e.responseBody.String())
+		}
+	}
+
+	if logger, ok := t.(interface {
+		Logf(format string, args ...any)
+	}); ok {
+		logger.Logf("%s", builder.String())
+
+		return
+	}
+
+	t.Errorf("%s", builder.String())
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// limitedBuffer keeps the first transcriptBodyLimit bytes and counts the
+// rest.
+type limitedBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	truncated int
+}
+
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	free := transcriptBodyLimit - l.buf.Len()
+
+	if len(p) > free {
+		l.buf.Write(p[:free])
+		l.truncated += len(p) - free
+
+		return len(p), nil
+	}
+
+	l.buf.Write(p)
+
+	return len(p), nil
+}
+
+func (l *limitedBuffer) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.buf.Len()
+}
+
+func (l *limitedBuffer) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.truncated > 0 {
+		return fmt.Sprintf("%s... (%d more bytes)", l.buf.String(), l.truncated)
+	}
+
+	return l.buf.String()
+}
+
+type transcriptWriter struct {
+	http.ResponseWriter
+	exchange *exchange
+}
+
+func (w *transcriptWriter) WriteHeader(statusCode int) {
+	if w.exchange.status == 0 {
+		w.exchange.status = statusCode
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *transcriptWriter) Write(p []byte) (int, error) {
+	if w.exchange.status == 0 {
+		w.exchange.status = http.StatusOK
+	}
+
+	_, _ = w.exchange.responseBody.Write(p)
+
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *transcriptWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *transcriptWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,88 @@
+package httpmock
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type logTestReporter struct {
+	*testReporterMock
+	logs []string
+}
+
+func (l *logTestReporter) Logf(format string, args ...any) {
+	l.logs = append(l.logs, fmt.Sprintf(format, args...))
+}
+
+func Test_WithTranscript(t *testing.T) {
+	reporter := &logTestReporter{}
+
+	t.Run("failed", func(t *testing.T) {
+		reporter.testReporterMock = &testReporterMock{t: t}
+
+		transport := NewTransport(reporter,
+			SequenceCalls(
+				Call{
+					Input:    Input{Method: http.MethodPost, Body: RawBody(`{"name":"bob"}`)},
+					Response: Response{StatusCode: http.StatusCreated, Body: RawBody(strings.Repeat("x", 300))},
+				},
+				Call{Input: Input{Method: http.MethodGet}},
+			),
+			nil,
+			WithTranscript(),
+		)
+
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Post("http://localhost/users", "", strings.NewReader(`{"name":"bob"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		resp, err = client.Post("http://localhost/users?retry=1", "", http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+
+	expected := "httpmock transcript:" +
+		"\n  1 POST http://localhost/users -> 201" +
+		"\n    request: {\"name\":\"bob\"}" +
+		"\n    response: " + strings.Repeat("x", 256) + "... (44 more bytes)" +
+		"\n  2 POST http://localhost/users?retry=1 -> 200"
+
+	if len(reporter.logs) != 1 || reporter.logs[0] != expected {
+		t.Errorf("wrong transcript,\nexpected %q,\nactual %q", expected, reporter.logs)
+	}
+
+	if len(reporter.errorfCalls) != 1 {
+		t.Errorf("wrong errorf calls %v", reporter.errorfCalls)
+	}
+}
+
+func Test_WithTranscript_Passed(t *testing.T) {
+	reporter := &logTestReporter{}
+
+	t.Run("passed", func(t *testing.T) {
+		reporter.testReporterMock = &testReporterMock{t: t}
+
+		transport := NewTransport(reporter, SequenceCalls(Call{Input: Input{Method: http.MethodGet}}), nil, WithTranscript())
+
+		resp, err := (&http.Client{Transport: transport}).Get("http://localhost/users")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	})
+
+	if len(reporter.logs) != 0 {
+		t.Errorf("transcript logged for passed test, actual %v", reporter.logs)
+	}
+}
@@ -0,0 +1,85 @@
+package httpmock
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+const (
+	TransferChunked  = "chunked"
+	TransferIdentity = "identity"
+)
+
+// renderTransferEncoding sets the headers which make the server write the
+// response with Response.TransferEncoding.
+func renderTransferEncoding(response Response) (Response, error) {
+	switch response.TransferEncoding {
+	case "":
+		return response, nil
+	case TransferChunked:
+		response.Header = response.Header.Clone()
+		if response.Header == nil {
+			response.Header = make(http.Header)
+		}
+
+		response.Header.Del("Content-Length")
+		response.Header.Set("Transfer-Encoding", TransferChunked)
+	case TransferIdentity:
+		var length int
+
+		if response.Body != nil {
+			body, err := response.Body.Bytes()
+			if err != nil {
+				return Response{}, fmt.Errorf("render identity transfer encoding, %w", err)
+			}
+
+			length = len(body)
+		}
+
+		response.Header = response.Header.Clone()
+		if response.Header == nil {
+			response.Header = make(http.Header)
+		}
+
+		response.Header.Set("Content-Length", strconv.Itoa(length))
+	default:
+		return Response{}, fmt.Errorf("unsupported transfer encoding %s, expected %s or %s", response.TransferEncoding, TransferChunked, TransferIdentity)
+	}
+
+	return response, nil
+}
+
+// CompareTransferEncoding compares the encoding the client sent the request
+// body with, chunked is used for bodies of unknown length, which some
+// backends reject.
+func CompareTransferEncoding(t TestReporter, r *http.Request, inputTransferEncoding string) {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	if inputTransferEncoding == "" {
+		return
+	}
+
+	if encoding := requestTransferEncoding(r); encoding != inputTransferEncoding {
+		t.Errorf("wrong transfer encoding, expected %s, actual %s", inputTransferEncoding, encoding)
+	}
+}
+
+// requestTransferEncoding handles server requests, which have
+// TransferEncoding set, and client requests, which body of unknown length
+// is sent chunked by http.Transport.
+func requestTransferEncoding(r *http.Request) string {
+	switch {
+	case slices.Contains(r.TransferEncoding, TransferChunked):
+		return TransferChunked
+	case r.ContentLength < 0:
+		return TransferChunked
+	case r.ContentLength == 0 && r.Body != nil && r.Body != http.NoBody:
+		return TransferChunked
+	default:
+		return TransferIdentity
+	}
+}
@@ -0,0 +1,72 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_Response_TransferEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		encoding              string
+		expectedTE            []string
+		expectedContentLength int64
+	}{
+		{encoding: TransferChunked, expectedTE: []string{"chunked"}, expectedContentLength: -1},
+		{encoding: TransferIdentity, expectedContentLength: 5},
+	} {
+		t.Run(tc.encoding, func(t *testing.T) {
+			call := Call{
+				Input:    Input{Method: http.MethodGet},
+				Response: Response{Body: RawBody("hello"), TransferEncoding: tc.encoding},
+			}
+
+			server := NewStaticServer(t, call)
+			transport := NewTransport(t, StaticCalls(call), nil)
+
+			for _, client := range []*http.Client{http.DefaultClient, {Transport: transport}} {
+				resp, err := client.Get(server.URL)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+
+				if string(body) != "hello" {
+					t.Errorf("wrong body, expected hello, actual %s", body)
+				}
+
+				if strings.Join(resp.TransferEncoding, ",") != strings.Join(tc.expectedTE, ",") || resp.ContentLength != tc.expectedContentLength {
+					t.Errorf("wrong encoding, expected %v %d, actual %v %d", tc.expectedTE, tc.expectedContentLength, resp.TransferEncoding, resp.ContentLength)
+				}
+			}
+		})
+	}
+}
+
+func Test_Input_TransferEncoding(t *testing.T) {
+	server := NewServer(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "2 call, wrong transfer encoding, expected %s, actual %s", args: []any{"identity", "chunked"}},
+			},
+			nil,
+		)(t),
+		SequenceCalls(
+			Call{Input: Input{Method: http.MethodPost, Body: RawBody("sized"), TransferEncoding: TransferIdentity}},
+			Call{Input: Input{Method: http.MethodPost, Body: RawBody("unsized"), TransferEncoding: TransferIdentity}},
+		),
+		nil,
+	)
+
+	for _, body := range []io.Reader{strings.NewReader("sized"), io.MultiReader(strings.NewReader("unsized"))} {
+		resp, err := http.Post(server.URL, "text/plain", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,34 @@
+package httpmock
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// WithUserAgent makes the transport report every call which User-Agent is
+// missing or does not match the pattern, e.g. `^myapp/\d+\.\d+`. The pattern
+// is not anchored, it panics when the pattern does not compile.
+func WithUserAgent(pattern string) Option {
+	userAgent := regexp.MustCompile(pattern)
+
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+func checkUserAgent(t TestReporter, r *http.Request, userAgent *regexp.Regexp) {
+	if userAgent == nil {
+		return
+	}
+
+	value, ok := r.Header["User-Agent"]
+	if !ok || len(value) == 0 {
+		t.Errorf("User-Agent not presented, expected to match %s", userAgent.String())
+
+		return
+	}
+
+	if !userAgent.MatchString(value[0]) {
+		t.Errorf("User-Agent %s does not match %s", value[0], userAgent.String())
+	}
+}
@@ -0,0 +1,38 @@
+package httpmock
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_WithUserAgent(t *testing.T) {
+	transport := NewTransport(
+		ExpectFailureTestReporter(
+			[]testReporterCall{
+				{format: "2 call, User-Agent %s does not match %s", args: []any{"curl/8.0", `^myapp/\d+\.\d+`}},
+				{format: "3 call, User-Agent not presented, expected to match %s", args: []any{`^myapp/\d+\.\d+`}},
+			},
+			nil,
+		)(t),
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}}),
+		nil,
+		WithUserAgent(`^myapp/\d+\.\d+`),
+	)
+
+	client := &http.Client{Transport: transport}
+
+	for _, userAgent := range []string{"myapp/1.12 (linux)", "curl/8.0", ""} {
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost/items", http.NoBody)
+
+		if userAgent != "" {
+			req.Header.Set("User-Agent", userAgent)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,197 @@
+package httpmock
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ValidateCall reports definition mistakes which would otherwise fail
+// obscurely mid-request: invalid methods, negative durations, status codes
+// out of range, JSON bodies which cannot be marshaled, response templates
+// reading request.pathVars without Input.URL path variables and webhooks
+// without url. SequenceCalls, StaticCalls, SelectCalls and NewScenario
+// steps are validated by transports and servers at construction, also when
+// wrapped by FailOnCalls or Degrade. Mock expectations may be added after
+// the transport is built and CallsFunc computes calls per request, they are
+// not validated, call ValidateCall on them.
+//
+// Marshaling JSONBody values costs as much as serving them, so
+// construction skips it unless WithJSONBodyCheck is set, ValidateCall
+// always marshals them.
+func ValidateCall(call Call) error {
+	return validateCall(call, true)
+}
+
+// WithJSONBodyCheck makes transports and servers marshal JSONBody values of
+// the calls validated at construction, so values which cannot be marshaled
+// fail the test before the first request instead of the call using them.
+func WithJSONBodyCheck() Option {
+	return func(o *options) {
+		o.jsonBodyCheck = true
+	}
+}
+
+func validateCall(call Call, marshalJSON bool) error {
+	var errs []error
+
+	if call.Input.Method != "" {
+		for _, method := range strings.Split(call.Input.Method, "|") {
+			if method != AnyMethod && !validMethod(method) {
+				errs = append(errs, fmt.Errorf("invalid method %q", method))
+			}
+		}
+	}
+
+	if call.Delay < 0 {
+		errs = append(errs, fmt.Errorf("negative Delay %s", call.Delay))
+	}
+
+	if call.FirstByte < 0 {
+		errs = append(errs, fmt.Errorf("negative FirstByte %s", call.FirstByte))
+	}
+
+	if call.BodyTransfer < 0 {
+		errs = append(errs, fmt.Errorf("negative BodyTransfer %s", call.BodyTransfer))
+	}
+
+	if call.ReadWithin < 0 {
+		errs = append(errs, fmt.Errorf("negative ReadWithin %s", call.ReadWithin))
+	}
+
+	if code := call.Response.StatusCode; code != 0 && (code < 100 || code > 999) {
+		errs = append(errs, fmt.Errorf("response status code %d out of range", code))
+	}
+
+	if call.Response.Body != nil && call.Response.BodyReader != nil {
+		errs = append(errs, errors.New("both response Body and BodyReader set, Body is ignored"))
+	}
+
+	if marshalJSON {
+		errs = append(errs, validateJSONBodies(call)...)
+	}
+
+	for i, hook := range call.Webhooks {
+		if hook.URL == "" {
+			errs = append(errs, fmt.Errorf("webhook %d without URL", i+1))
+		}
+	}
+
+	if usesPathVars(call) && (call.Input.URL == nil || !strings.Contains(call.Input.URL.Path, "{")) {
+		errs = append(errs, errors.New("response template reads request.pathVars, Input.URL has no {name} path segments"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateJSONBodies(call Call) []error {
+	var errs []error
+
+	for _, body := range []struct {
+		name string
+		body Body
+	}{
+		{name: "input", body: call.Input.Body},
+		{name: "response", body: call.Response.Body},
+	} {
+		if jsonBody, ok := body.body.(*jsonBody); ok {
+			if _, err := jsonBody.Bytes(); err != nil {
+				errs = append(errs, fmt.Errorf("%s JSON body, %w", body.name, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// usesPathVars reports whether templates rendered with the call request
+// read path variables.
+func usesPathVars(call Call) bool {
+	const pathVars = "request.pathVars"
+
+	templates := []Body{call.Response.Body}
+
+	for _, hook := range call.Webhooks {
+		if strings.Contains(hook.URL, pathVars) {
+			return true
+		}
+
+		templates = append(templates, hook.Body)
+	}
+
+	for _, body := range templates {
+		if body, ok := body.(templateBody); ok && strings.Contains(body.template, pathVars) {
+			return true
+		}
+	}
+
+	for _, values := range call.Response.TemplateHeader {
+		for _, value := range values {
+			if strings.Contains(value, pathVars) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// validator is implemented by Calls validated at construction, JSONBody
+// values are marshaled with marshalJSON set.
+type validator interface {
+	validate(marshalJSON bool) error
+}
+
+// validateWrapped validates calls wrapped by other Calls.
+func validateWrapped(calls Calls, marshalJSON bool) error {
+	if calls, ok := calls.(validator); ok {
+		return calls.validate(marshalJSON)
+	}
+
+	return nil
+}
+
+// validMethod reports whether method is an RFC 9110 token.
+func validMethod(method string) bool {
+	if method == "" {
+		return false
+	}
+
+	for _, c := range method {
+		if c > 0x7e || c <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// callSite is the file and line calls were defined at.
+type callSite string
+
+func definitionSite() callSite {
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+
+	return callSite(fmt.Sprintf("%s:%d", filepath.Base(file), line))
+}
+
+func validateCalls(calls []Call, site callSite, marshalJSON bool) error {
+	var errs []error
+
+	for i, call := range calls {
+		if err := validateCall(call, marshalJSON); err != nil {
+			errs = append(errs, fmt.Errorf("call %d, %w", i+1, err))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("invalid calls defined at %s, %w", site, errors.Join(errs...))
+}
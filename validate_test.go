@@ -0,0 +1,149 @@
+package httpmock
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ValidateCall(t *testing.T) {
+	err := ValidateCall(Call{
+		Input:    Input{Method: "GET|get |"},
+		Response: Response{StatusCode: 2000, Body: JSONBody(make(chan int))},
+		Delay:    -time.Second,
+		Webhooks: []Webhook{{}},
+	})
+
+	expected := strings.Join([]string{
+		`invalid method "get "`,
+		`invalid method ""`,
+		"negative Delay -1s",
+		"response status code 2000 out of range",
+		"response JSON body, json: unsupported type: chan int",
+		"webhook 1 without URL",
+	}, "\n")
+
+	if err == nil || err.Error() != expected {
+		t.Errorf("wrong validation error,\nexpected %s,\nactual %v", expected, err)
+	}
+
+	err = ValidateCall(Call{Input: Input{Method: "GET|HEAD"}, Response: Response{StatusCode: http.StatusOK}})
+	if err != nil {
+		t.Errorf("unexpected validation error, %s", err)
+	}
+}
+
+func Test_NewTransport_InvalidCalls(t *testing.T) {
+	reporter := &testReporterMock{t: t}
+
+	NewTransport(reporter, SequenceCalls(
+		Call{Input: Input{Method: http.MethodGet}},
+		Call{Response: Response{StatusCode: 42}},
+	), nil)
+
+	if len(reporter.fatalfCalls) != 1 {
+		t.Fatalf("expect one fatalf call, actual %+v", reporter.fatalfCalls)
+	}
+
+	message := reporter.fatalfCalls[0].args[0].(error).Error()
+
+	if !strings.HasPrefix(message, "invalid calls defined at validate_test.go:") ||
+		!strings.HasSuffix(message, ", call 2, response status code 42 out of range") {
+		t.Errorf("wrong validation failure, %s", message)
+	}
+}
+
+func Test_ValidateCall_PathVars(t *testing.T) {
+	call := Call{Response: Response{Body: TemplateBody("{{request.pathVars.id}}")}}
+
+	const expected = "response template reads request.pathVars, Input.URL has no {name} path segments"
+
+	if err := ValidateCall(call); err == nil || err.Error() != expected {
+		t.Errorf("wrong validation error, expected %s, actual %v", expected, err)
+	}
+
+	call.Input.URL = mustParseURL("/users/{id}")
+
+	if err := ValidateCall(call); err != nil {
+		t.Errorf("unexpected validation error, %s", err)
+	}
+}
+
+func Test_NewTransport_InvalidWrappedCalls(t *testing.T) {
+	for name, calls := range map[string]Calls{
+		"fail on calls": FailOnCalls(SequenceCalls(Call{Delay: -time.Second}), []int{1}, FaultTimeout()),
+		"degrade":       Degrade(StaticCalls(Call{Delay: -time.Second}), 1, SlowTail(0.5, time.Second)),
+		"scenario":      NewScenario(t, ScenarioStep{Delay: -time.Second}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			reporter := &testReporterMock{t: t}
+
+			NewTransport(reporter, calls, nil)
+
+			if len(reporter.fatalfCalls) != 1 {
+				t.Fatalf("expect one fatalf call, actual %+v", reporter.fatalfCalls)
+			}
+
+			message := reporter.fatalfCalls[0].args[0].(error).Error()
+
+			if !strings.HasPrefix(message, "invalid calls defined at validate_test.go:") ||
+				!strings.HasSuffix(message, ", call 1, negative Delay -1s") {
+				t.Errorf("wrong validation failure, %s", message)
+			}
+		})
+	}
+}
+
+func Test_NewTransport_UnmarshalableJSONBody(t *testing.T) {
+	for name, call := range map[string]Call{
+		"input":    {Input: Input{Body: JSONBody(make(chan int))}},
+		"response": {Response: Response{Body: JSONBody(make(chan int))}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			reporter := &testReporterMock{t: t}
+
+			NewTransport(reporter, SequenceCalls(call), nil, WithJSONBodyCheck())
+
+			if len(reporter.fatalfCalls) != 1 {
+				t.Fatalf("expect one fatalf call, actual %+v", reporter.fatalfCalls)
+			}
+
+			message := reporter.fatalfCalls[0].args[0].(error).Error()
+
+			if !strings.HasSuffix(message, ", call 1, "+name+" JSON body, json: unsupported type: chan int") {
+				t.Errorf("wrong validation failure, %s", message)
+			}
+		})
+	}
+}
+
+func Test_NewTransport_JSONBodyCheck(t *testing.T) {
+	calls := FailOnCalls(
+		StaticCalls(Call{Response: Response{Body: JSONBody(make(chan int))}}),
+		[]int{2},
+		FaultTimeout(),
+	)
+
+	reporter := &testReporterMock{t: t}
+
+	NewTransport(reporter, calls, nil)
+
+	if len(reporter.fatalfCalls) != 0 {
+		t.Fatalf("expect JSON bodies marshaled on demand, actual %+v", reporter.fatalfCalls)
+	}
+
+	reporter = &testReporterMock{t: t}
+
+	NewTransport(reporter, calls, nil, WithJSONBodyCheck())
+
+	if len(reporter.fatalfCalls) != 1 {
+		t.Fatalf("expect one fatalf call, actual %+v", reporter.fatalfCalls)
+	}
+
+	message := reporter.fatalfCalls[0].args[0].(error).Error()
+
+	if !strings.HasSuffix(message, ", call 1, response JSON body, json: unsupported type: chan int") {
+		t.Errorf("wrong validation failure, %s", message)
+	}
+}
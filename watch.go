@@ -0,0 +1,250 @@
+package httpmock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+var errWatchNoServer = errors.New("watch responses are served only by NewServer")
+
+// StreamBody is implemented by response bodies written over time, the
+// stream is written after the response header and ends when WriteStream
+// returns.
+type StreamBody interface {
+	Body
+	WriteStream(w http.ResponseWriter, r *http.Request) error
+}
+
+type watchBody struct {
+	longPoll bool
+}
+
+// Watch keeps the response open and writes every event passed to
+// Server.Emit as a line until the client disconnects or the server is
+// closed, like Kubernetes watch requests.
+func Watch() Body {
+	return watchBody{}
+}
+
+// LongPoll holds the response until the next Server.Emit and writes the
+// event as the whole body.
+func LongPoll() Body {
+	return watchBody{longPoll: true}
+}
+
+func (watchBody) Bytes() ([]byte, error) {
+	return nil, errWatchNoServer
+}
+
+func (b watchBody) WriteStream(w http.ResponseWriter, r *http.Request) error {
+	broker, ok := r.Context().Value(eventBrokerContextKey{}).(*eventBroker)
+	if !ok {
+		return errWatchNoServer
+	}
+
+	watcher := broker.subscribe()
+	defer broker.unsubscribe(watcher)
+
+	flusher, _ := w.(http.Flusher)
+
+	if flusher != nil && !b.longPoll {
+		flusher.Flush()
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-watcher.notify:
+		}
+
+		events, closed := watcher.take()
+
+		if b.longPoll && len(events) > 0 {
+			watcher.requeue(events[1:])
+
+			_, _ = w.Write(events[0])
+
+			return nil
+		}
+
+		for _, event := range events {
+			_, err := w.Write(event)
+			if err != nil {
+				return nil
+			}
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		if closed {
+			return nil
+		}
+	}
+}
+
+type eventBrokerContextKey struct{}
+
+// eventBroker fans out emitted events to open watch responses, events
+// emitted while nobody watches are kept for the next watcher.
+type eventBroker struct {
+	mu       sync.Mutex
+	watchers map[*watcher]struct{}
+	backlog  [][]byte
+	closed   bool
+}
+
+type watcher struct {
+	mu     sync.Mutex
+	events [][]byte
+	closed bool
+	notify chan struct{}
+}
+
+func (w *watcher) push(events ...[]byte) {
+	w.mu.Lock()
+	w.events = append(w.events, events...)
+	w.mu.Unlock()
+
+	w.signal()
+}
+
+func (w *watcher) close() {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+
+	w.signal()
+}
+
+func (w *watcher) signal() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (w *watcher) requeue(events [][]byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.events = append(events, w.events...)
+}
+
+func (w *watcher) take() (events [][]byte, closed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	events, w.events = w.events, nil
+
+	return events, w.closed
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{
+		watchers: make(map[*watcher]struct{}),
+	}
+}
+
+func (b *eventBroker) attach(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), eventBrokerContextKey{}, b))
+}
+
+func (b *eventBroker) subscribe() *watcher {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w := &watcher{notify: make(chan struct{}, 1)}
+
+	if b.closed {
+		w.close()
+
+		return w
+	}
+
+	if len(b.backlog) > 0 {
+		w.push(b.backlog...)
+
+		b.backlog = nil
+	}
+
+	b.watchers[w] = struct{}{}
+
+	return w
+}
+
+// unsubscribe returns events the watcher has not written to the backlog.
+func (b *eventBroker) unsubscribe(w *watcher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.watchers, w)
+
+	events, _ := w.take()
+	if len(b.watchers) == 0 {
+		b.backlog = append(events, b.backlog...)
+	}
+}
+
+func (b *eventBroker) emit(event []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.watchers) == 0 {
+		b.backlog = append(b.backlog, event)
+
+		return
+	}
+
+	for w := range b.watchers {
+		w.push(event)
+	}
+}
+
+func (b *eventBroker) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.watchers)
+}
+
+func (b *eventBroker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	for w := range b.watchers {
+		w.close()
+	}
+}
+
+func encodeEvent(event any) ([]byte, error) {
+	var data []byte
+
+	switch event := event.(type) {
+	case []byte:
+		data = append(data, event...)
+	case string:
+		data = []byte(event)
+	default:
+		var err error
+
+		data, err = json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("marshal event, %w", err)
+		}
+	}
+
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+
+	return data, nil
+}
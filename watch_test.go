@@ -0,0 +1,154 @@
+package httpmock
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func waitWatchers(t *testing.T, server *Server, expected int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for server.Watchers() != expected {
+		if time.Now().After(deadline) {
+			t.Fatalf("wrong watchers count, expected %d, actual %d", expected, server.Watchers())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_Server_Watch(t *testing.T) {
+	server := NewServer(t,
+		StaticCalls(
+			Call{
+				Input: Input{Method: http.MethodGet},
+				Response: Response{
+					Header: http.Header{"Content-Type": {"application/json"}},
+					Body:   Watch(),
+				},
+			},
+		),
+		nil,
+	)
+
+	err := server.Emit(map[string]string{"type": "ADDED"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/pods?watch=true", http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("wrong Content-Type, expected application/json, actual %s", contentType)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+
+	expectLine := func(expected string) {
+		t.Helper()
+
+		if !lines.Scan() {
+			t.Fatalf("watch ended before event %s, %v", expected, lines.Err())
+		}
+
+		if lines.Text() != expected {
+			t.Errorf("wrong event, expected %s, actual %s", expected, lines.Text())
+		}
+	}
+
+	expectLine(`{"type":"ADDED"}`)
+
+	_ = server.Emit(`{"type":"MODIFIED"}`)
+	_ = server.Emit([]byte("{\"type\":\"DELETED\"}\n"))
+
+	expectLine(`{"type":"MODIFIED"}`)
+	expectLine(`{"type":"DELETED"}`)
+
+	cancel()
+
+	waitWatchers(t, server, 0)
+}
+
+func Test_Server_WatchEndsOnClose(t *testing.T) {
+	server := NewServer(t,
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: Watch()}}),
+		nil,
+	)
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	waitWatchers(t, server, 1)
+
+	go server.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Errorf("read closed watch, unexpected error: %s", err)
+	}
+}
+
+func Test_Server_LongPoll(t *testing.T) {
+	server := NewServer(t,
+		StaticCalls(Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: LongPoll()}}),
+		nil,
+	)
+
+	_ = server.Emit("first")
+	_ = server.Emit("second")
+
+	for _, expected := range []string{"first\n", "second\n"} {
+		resp, err := http.Get(server.URL + "/poll")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(body) != expected {
+			t.Errorf("wrong long poll body, expected %q, actual %q", expected, body)
+		}
+	}
+}
+
+func Test_Watch_Transport(t *testing.T) {
+	tr := ExpectFailureTestReporter(
+		[]testReporterCall{{format: "1 call, " + errWatchNoServer.Error()}},
+		nil,
+	)(t)
+
+	client := &http.Client{
+		Transport: NewTransport(tr,
+			SequenceCalls(Call{Input: Input{Method: http.MethodGet}, Response: Response{Body: Watch()}}),
+			nil,
+		),
+	}
+
+	resp, err := client.Get("http://localhost/watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
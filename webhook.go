@@ -0,0 +1,206 @@
+package httpmock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Webhook is a request the mock sends after the call is handled, for
+// protocols which answer asynchronously with a callback.
+type Webhook struct {
+	// URL of the receiver, {{...}} placeholders are rendered with the call
+	// request like TemplateBody, e.g. {{request.headers.X-Callback-Url}}.
+	URL string
+	// Method defaults to POST.
+	Method string
+	Header http.Header
+	// Body may be a TemplateBody rendered with the call request.
+	Body Body
+	// Delay is waited for in background, transports and servers wait for
+	// pending webhooks at Cleanup up to WebhookCleanupTimeout, then delays
+	// and requests still in progress are cancelled and reported.
+	Delay time.Duration
+	// ExpectStatusCode is the status code the receiver must answer with,
+	// any 2xx status code is accepted by default.
+	ExpectStatusCode int
+	// Client sends the webhook, a client with DefaultClientTimeout by default.
+	Client *http.Client
+}
+
+// WebhookCleanupTimeout is the longest time Cleanup waits for pending
+// webhooks before cancelling them.
+const WebhookCleanupTimeout = 5 * time.Second
+
+var webhookClient = &http.Client{Timeout: DefaultClientTimeout}
+
+type webhooksContextKey struct{}
+
+// webhooks tracks scheduled webhooks, so the transport waits for them at
+// Cleanup and cancels the ones left after the timeout.
+type webhooks struct {
+	wg      sync.WaitGroup
+	timeout time.Duration
+
+	once   sync.Once
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (w *webhooks) attach(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), webhooksContextKey{}, w))
+}
+
+// context is cancelled when the webhooks are not sent within the cleanup
+// timeout.
+func (w *webhooks) context() context.Context {
+	w.once.Do(func() {
+		w.ctx, w.cancel = context.WithCancel(context.Background())
+	})
+
+	return w.ctx
+}
+
+func (w *webhooks) wait() {
+	done := make(chan struct{})
+
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	timeout := w.timeout
+	if timeout == 0 {
+		timeout = WebhookCleanupTimeout
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return
+	case <-timer.C:
+	}
+
+	w.context()
+	w.cancel()
+
+	<-done
+}
+
+// ScheduleWebhooks renders the webhooks with the call request and sends them
+// in background after their delays, failures are reported to t.
+func ScheduleWebhooks(t TestReporter, r *http.Request, hooks []Webhook) {
+	tracker, _ := r.Context().Value(webhooksContextKey{}).(*webhooks)
+
+	ctx := context.Background()
+	if tracker != nil {
+		ctx = tracker.context()
+	}
+
+	for i, hook := range hooks {
+		req, err := newWebhookRequest(ctx, r, hook)
+		if err != nil {
+			t.Errorf("webhook %d, %s", i+1, err.Error())
+
+			continue
+		}
+
+		if tracker != nil {
+			tracker.wg.Add(1)
+		}
+
+		go func() {
+			if tracker != nil {
+				defer tracker.wg.Done()
+			}
+
+			sendWebhook(t, i+1, req, hook)
+		}()
+	}
+}
+
+func newWebhookRequest(ctx context.Context, r *http.Request, hook Webhook) (*http.Request, error) {
+	tc := &templateContext{request: r}
+
+	target, err := renderTemplate(hook.URL, tc)
+	if err != nil {
+		return nil, fmt.Errorf("render url, %w", err)
+	}
+
+	var body []byte
+
+	switch hookBody := hook.Body.(type) {
+	case nil:
+	case RequestBody:
+		body, err = hookBody.RequestBytes(r)
+	default:
+		body, err = hookBody.Bytes()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("render body, %w", err)
+	}
+
+	method := hook.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request, %w", err)
+	}
+
+	for key, values := range hook.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	return req, nil
+}
+
+func sendWebhook(t TestReporter, number int, req *http.Request, hook Webhook) {
+	if hook.Delay > 0 && !sleepContext(req.Context(), hook.Delay) {
+		t.Errorf("webhook %d, cancelled at Cleanup before its delay passed", number)
+
+		return
+	}
+
+	client := hook.Client
+	if client == nil {
+		client = webhookClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Errorf("webhook %d, send %s %s, %s", number, req.Method, req.URL, err)
+
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch {
+	case hook.ExpectStatusCode != 0 && resp.StatusCode != hook.ExpectStatusCode:
+		t.Errorf("webhook %d, wrong status code, expected %d, actual %d", number, hook.ExpectStatusCode, resp.StatusCode)
+	case hook.ExpectStatusCode == 0 && resp.StatusCode/100 != 2:
+		t.Errorf("webhook %d, wrong status code, expected 2xx, actual %d", number, resp.StatusCode)
+	}
+}
+
+func webhooksReadRequestBody(hooks []Webhook) bool {
+	for _, hook := range hooks {
+		if _, ok := hook.Body.(RequestBody); ok || strings.Contains(hook.URL, "request.body") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,155 @@
+package httpmock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Call_Webhooks(t *testing.T) {
+	receiver := NewServer(t,
+		SequenceCalls(
+			Call{
+				Input: Input{
+					Method: http.MethodPost,
+					URL:    mustParseURL("/callbacks/orders"),
+					Header: http.Header{"X-Event": {"order.paid"}},
+					Body:   RawBody(`{"order":"42","status":"paid"}`),
+				},
+			},
+		),
+		nil,
+	)
+
+	client := &http.Client{
+		Transport: NewTransport(t,
+			SequenceCalls(
+				Call{
+					Input: Input{
+						Method: http.MethodPost,
+						Body:   RawBody(`42`),
+					},
+					Response: Response{StatusCode: http.StatusAccepted},
+					Webhooks: []Webhook{
+						{
+							URL:    "{{request.headers.X-Callback-Url}}",
+							Header: http.Header{"X-Event": {"order.paid"}},
+							Body:   TemplateBody(`{"order":"{{request.body}}","status":"paid"}`),
+							Delay:  10 * time.Millisecond,
+						},
+					},
+				},
+			),
+			nil,
+		),
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "http://api.example.com/orders/pay", strings.NewReader("42"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("X-Callback-Url", receiver.URL+"/callbacks/orders")
+
+	start := time.Now()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed >= 10*time.Millisecond {
+		t.Errorf("response waited for webhook delay, elapsed %s", elapsed)
+	}
+}
+
+func Test_Call_Webhooks_Failures(t *testing.T) {
+	receiver := NewStaticServer(t,
+		Call{
+			Input:    Input{Method: http.MethodPut},
+			Response: Response{StatusCode: http.StatusInternalServerError},
+		},
+	)
+
+	tr := ExpectFailureTestReporter(
+		[]testReporterCall{
+			{
+				format: "1 call, webhook %d, %s",
+				args:   []any{2, "render url, render template expression {{unknown}}, unknown template expression"},
+			},
+			{
+				format: "1 call, webhook %d, wrong status code, expected %d, actual %d",
+				args:   []any{1, http.StatusNoContent, http.StatusInternalServerError},
+			},
+		},
+		nil,
+	)(t)
+
+	client := &http.Client{
+		Transport: NewTransport(tr,
+			SequenceCalls(
+				Call{
+					Input: Input{Method: http.MethodGet},
+					Webhooks: []Webhook{
+						{URL: receiver.URL, Method: http.MethodPut, ExpectStatusCode: http.StatusNoContent},
+						{URL: "{{unknown}}"},
+					},
+				},
+			),
+			nil,
+		),
+	}
+
+	resp, err := client.Get("http://localhost/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+}
+
+func Test_Webhooks_CancelledAtCleanup(t *testing.T) {
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(hanging.Close)
+
+	tr := &testReporterMock{t: t}
+	tracker := &webhooks{timeout: 20 * time.Millisecond}
+
+	r := tracker.attach(httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+	ScheduleWebhooks(tr, r, []Webhook{
+		{URL: hanging.URL, Delay: time.Hour},
+		{URL: hanging.URL},
+	})
+
+	start := time.Now()
+
+	tracker.wait()
+
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("wait blocked on pending webhooks, elapsed %s", elapsed)
+	}
+
+	formats := make([]string, 0, len(tr.errorfCalls))
+	for _, call := range tr.errorfCalls {
+		formats = append(formats, call.format)
+	}
+
+	slices.Sort(formats)
+
+	expected := []string{
+		"webhook %d, cancelled at Cleanup before its delay passed",
+		"webhook %d, send %s %s, %s",
+	}
+
+	if !slices.Equal(formats, expected) {
+		t.Fatalf("wrong reported failures, expected %v, actual %v", expected, formats)
+	}
+}
@@ -0,0 +1,460 @@
+package httpmock
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type wireMockMappings struct {
+	Mappings []wireMockMapping `json:"mappings"`
+}
+
+type wireMockMapping struct {
+	Name     string           `json:"name"`
+	Request  *wireMockRequest `json:"request"`
+	Response wireMockResponse `json:"response"`
+}
+
+type wireMockRequest struct {
+	// unsupported are matchers the importer does not convert, dropping
+	// them would match more requests than the stub does.
+	unsupported []string
+
+	Method          string                     `json:"method"`
+	URL             string                     `json:"url"`
+	URLPath         string                     `json:"urlPath"`
+	URLPattern      string                     `json:"urlPattern"`
+	URLPathPattern  string                     `json:"urlPathPattern"`
+	QueryParameters map[string]wireMockMatcher `json:"queryParameters"`
+	Headers         map[string]wireMockMatcher `json:"headers"`
+	BodyPatterns    []wireMockMatcher          `json:"bodyPatterns"`
+}
+
+var wireMockRequestFields = map[string]bool{
+	"method":          true,
+	"url":             true,
+	"urlPath":         true,
+	"urlPattern":      true,
+	"urlPathPattern":  true,
+	"queryParameters": true,
+	"headers":         true,
+	"bodyPatterns":    true,
+}
+
+func (r *wireMockRequest) UnmarshalJSON(data []byte) error {
+	type request wireMockRequest
+
+	var fields map[string]json.RawMessage
+
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(fields) {
+		if !wireMockRequestFields[name] {
+			r.unsupported = append(r.unsupported, name)
+		}
+	}
+
+	return json.Unmarshal(data, (*request)(r))
+}
+
+type wireMockMatcher map[string]json.RawMessage
+
+type wireMockResponse struct {
+	Status                 int             `json:"status"`
+	Body                   *string         `json:"body"`
+	JSONBody               json.RawMessage `json:"jsonBody"`
+	Base64Body             string          `json:"base64Body"`
+	BodyFileName           string          `json:"bodyFileName"`
+	Headers                map[string]any  `json:"headers"`
+	FixedDelayMilliseconds int64           `json:"fixedDelayMilliseconds"`
+	Fault                  string          `json:"fault"`
+	Transformers           []string        `json:"transformers"`
+}
+
+// ParseWireMockMappings converts WireMock stub mappings into calls, data is
+// either a single mapping or an object with a "mappings" array.
+//
+// Supported request matchers are method, with ANY as AnyMethod, url as
+// the exact path and query, urlPath, equalTo for query parameters and
+// headers, and equalTo, equalToJson, matchesJsonSchema body patterns, any
+// other matcher or matcher option is an error. Responses with the
+// "response-template" transformer are rendered with TemplateBody.
+func ParseWireMockMappings(data []byte) ([]Call, error) {
+	var mappings wireMockMappings
+
+	err := json.Unmarshal(data, &mappings)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal wiremock mappings, %w", err)
+	}
+
+	if mappings.Mappings == nil {
+		var mapping wireMockMapping
+
+		err = json.Unmarshal(data, &mapping)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal wiremock mapping, %w", err)
+		}
+
+		mappings.Mappings = []wireMockMapping{mapping}
+	}
+
+	calls := make([]Call, 0, len(mappings.Mappings))
+
+	for i, mapping := range mappings.Mappings {
+		call, err := mapping.call()
+		if err != nil {
+			return nil, fmt.Errorf("convert wiremock mapping %d %s, %w", i, mapping.Name, err)
+		}
+
+		calls = append(calls, call)
+	}
+
+	return calls, nil
+}
+
+// LoadWireMockMappings reads mappings from a file or from every .json file of
+// a directory, files are read in lexical order.
+func LoadWireMockMappings(path string) ([]Call, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat wiremock mappings, %w", err)
+	}
+
+	files := []string{path}
+
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("list wiremock mappings, %w", err)
+		}
+
+		slices.Sort(files)
+	}
+
+	var calls []Call
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read wiremock mappings, %w", err)
+		}
+
+		fileCalls, err := ParseWireMockMappings(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s, %w", file, err)
+		}
+
+		calls = append(calls, fileCalls...)
+	}
+
+	return calls, nil
+}
+
+func (m wireMockMapping) call() (Call, error) {
+	if m.Request == nil {
+		return Call{}, errors.New("request is not specified")
+	}
+
+	input, err := m.Request.input()
+	if err != nil {
+		return Call{}, fmt.Errorf("request, %w", err)
+	}
+
+	call := Call{
+		Input: input,
+		Delay: time.Duration(m.Response.FixedDelayMilliseconds) * time.Millisecond,
+	}
+
+	if m.Response.Fault != "" {
+		call.DoError, err = wireMockFault(m.Response.Fault)
+
+		return call, err
+	}
+
+	call.Response, err = m.Response.response()
+	if err != nil {
+		return Call{}, fmt.Errorf("response, %w", err)
+	}
+
+	return call, nil
+}
+
+func (r *wireMockRequest) input() (Input, error) {
+	input := Input{
+		Method: r.Method,
+		Body:   anyBody{},
+	}
+
+	if r.Method == "ANY" {
+		input.Method = AnyMethod
+	}
+
+	if len(r.unsupported) > 0 {
+		return Input{}, fmt.Errorf("unsupported matchers %s", strings.Join(r.unsupported, ","))
+	}
+
+	if r.URLPattern != "" || r.URLPathPattern != "" {
+		return Input{}, errors.New("urlPattern and urlPathPattern are not supported")
+	}
+
+	if r.URL != "" && r.URLPath != "" {
+		return Input{}, errors.New("both url and urlPath set")
+	}
+
+	if r.URL != "" {
+		u, err := url.Parse(r.URL)
+		if err != nil {
+			return Input{}, fmt.Errorf("parse url, %w", err)
+		}
+
+		input.URL = u
+		input.exactQuery = true
+	}
+
+	if r.URLPath != "" {
+		u, err := url.Parse(r.URLPath)
+		if err != nil {
+			return Input{}, fmt.Errorf("parse url, %w", err)
+		}
+
+		input.URL = u
+	}
+
+	if len(r.QueryParameters) > 0 {
+		input.QueryMatch = make(QueryMatch, len(r.QueryParameters))
+
+		for _, name := range sortedKeys(r.QueryParameters) {
+			value, err := r.QueryParameters[name].equalTo()
+			if err != nil {
+				return Input{}, fmt.Errorf("query parameter %s, %w", name, err)
+			}
+
+			input.QueryMatch[name] = Equal(value)
+		}
+	}
+
+	if len(r.Headers) > 0 {
+		input.Header = make(http.Header, len(r.Headers))
+
+		for _, name := range sortedKeys(r.Headers) {
+			value, err := r.Headers[name].equalTo()
+			if err != nil {
+				return Input{}, fmt.Errorf("header %s, %w", name, err)
+			}
+
+			input.Header.Add(name, value)
+		}
+	}
+
+	switch len(r.BodyPatterns) {
+	case 0:
+	case 1:
+		body, err := r.BodyPatterns[0].body()
+		if err != nil {
+			return Input{}, fmt.Errorf("body pattern, %w", err)
+		}
+
+		input.Body = body
+	default:
+		return Input{}, errors.New("multiple body patterns are not supported")
+	}
+
+	return input, nil
+}
+
+func (m wireMockMatcher) equalTo() (string, error) {
+	raw, ok := m["equalTo"]
+	if !ok || len(m) != 1 {
+		return "", fmt.Errorf("unsupported matcher %s, only equalTo is supported", m.names())
+	}
+
+	var value string
+
+	err := json.Unmarshal(raw, &value)
+	if err != nil {
+		return "", fmt.Errorf("unmarshal equalTo, %w", err)
+	}
+
+	return value, nil
+}
+
+func (m wireMockMatcher) body() (Body, error) {
+	if len(m) != 1 {
+		return nil, m.unsupportedBody()
+	}
+
+	if raw, ok := m["equalToJson"]; ok {
+		var value string
+
+		if json.Unmarshal(raw, &value) == nil {
+			raw = json.RawMessage(value)
+		}
+
+		return jsonEqualBody(raw), nil
+	}
+
+	if raw, ok := m["matchesJsonSchema"]; ok {
+		var value string
+
+		if json.Unmarshal(raw, &value) == nil {
+			raw = json.RawMessage(value)
+		}
+
+		return JSONSchemaBody(raw), nil
+	}
+
+	if _, ok := m["equalTo"]; !ok {
+		return nil, m.unsupportedBody()
+	}
+
+	value, err := m.equalTo()
+	if err != nil {
+		return nil, err
+	}
+
+	return RawBody(value), nil
+}
+
+func (m wireMockMatcher) unsupportedBody() error {
+	return fmt.Errorf("unsupported matcher %s, only one of equalTo, equalToJson and matchesJsonSchema is supported", m.names())
+}
+
+func (m wireMockMatcher) names() string {
+	return strings.Join(sortedKeys(m), ",")
+}
+
+func (r wireMockResponse) response() (Response, error) {
+	response := Response{
+		StatusCode: r.Status,
+	}
+
+	body, err := r.body()
+	if err != nil {
+		return Response{}, err
+	}
+
+	response.Body = body
+
+	if len(r.Headers) > 0 {
+		response.Header = make(http.Header, len(r.Headers))
+
+		for _, name := range sortedKeys(r.Headers) {
+			switch value := r.Headers[name].(type) {
+			case string:
+				response.Header.Add(name, value)
+			case []any:
+				for _, v := range value {
+					response.Header.Add(name, fmt.Sprint(v))
+				}
+			default:
+				return Response{}, fmt.Errorf("header %s, unsupported value %v", name, value)
+			}
+		}
+	}
+
+	return response, nil
+}
+
+func (r wireMockResponse) body() (Body, error) {
+	var body []byte
+
+	switch {
+	case r.BodyFileName != "":
+		return nil, errors.New("bodyFileName is not supported")
+	case r.Body != nil:
+		body = []byte(*r.Body)
+	case len(r.JSONBody) > 0:
+		body = r.JSONBody
+	case r.Base64Body != "":
+		decoded, err := base64.StdEncoding.DecodeString(r.Base64Body)
+		if err != nil {
+			return nil, fmt.Errorf("decode base64Body, %w", err)
+		}
+
+		body = decoded
+	default:
+		return nil, nil
+	}
+
+	if slices.Contains(r.Transformers, "response-template") {
+		return TemplateBody(string(body)), nil
+	}
+
+	return RawBody(body), nil
+}
+
+func wireMockFault(fault string) (error, error) {
+	switch fault {
+	case "CONNECTION_RESET_BY_PEER":
+		return syscall.ECONNRESET, nil
+	case "EMPTY_RESPONSE", "MALFORMED_RESPONSE_CHUNK", "RANDOM_DATA_THEN_CLOSE":
+		return io.ErrUnexpectedEOF, nil
+	default:
+		return nil, fmt.Errorf("unsupported fault %s", fault)
+	}
+}
+
+// anyBody accepts every request body.
+type anyBody struct{}
+
+func (anyBody) Bytes() ([]byte, error) {
+	return nil, nil
+}
+
+func (anyBody) CompareBody(TestReporter, []byte) {}
+
+// jsonEqualBody compares bodies as decoded JSON values.
+type jsonEqualBody []byte
+
+func (j jsonEqualBody) Bytes() ([]byte, error) {
+	return j, nil
+}
+
+func (j jsonEqualBody) CompareBody(t TestReporter, body []byte) {
+	var expected, actual any
+
+	err := json.Unmarshal(j, &expected)
+	if err != nil {
+		t.Errorf("unmarshal input json body, %s", err)
+
+		return
+	}
+
+	err = json.Unmarshal(body, &actual)
+	if err != nil {
+		t.Errorf("unmarshal body as json, %s, body %s", err, string(body))
+
+		return
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("json body not equal, expected %s actual %s", compactJSON(j), compactJSON(body))
+	}
+}
+
+func compactJSON(data []byte) string {
+	var buf bytes.Buffer
+
+	err := json.Compact(&buf, data)
+	if err != nil {
+		return string(data)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,286 @@
+package httpmock
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const wireMockMappingsJSON = `{
+	"mappings": [
+		{
+			"name": "create user",
+			"request": {
+				"method": "POST",
+				"urlPath": "/users",
+				"queryParameters": {"dry": {"equalTo": "false"}},
+				"headers": {"Content-Type": {"equalTo": "application/json"}},
+				"bodyPatterns": [{"equalToJson": {"name": "dima", "age": 10}}]
+			},
+			"response": {
+				"status": 201,
+				"jsonBody": {"id": 1},
+				"headers": {"Content-Type": "application/json", "X-Tags": ["a", "b"]}
+			}
+		},
+		{
+			"name": "get user",
+			"request": {
+				"method": "GET",
+				"url": "/users/1?fields=name"
+			},
+			"response": {
+				"status": 200,
+				"body": "{{request.method}} {{request.path}} {{request.pathSegments.[1]}} {{request.query.fields}}",
+				"transformers": ["response-template"],
+				"fixedDelayMilliseconds": 1
+			}
+		},
+		{
+			"request": {"method": "DELETE", "url": "/users/1"},
+			"response": {"fault": "CONNECTION_RESET_BY_PEER"}
+		}
+	]
+}`
+
+func Test_ParseWireMockMappings(t *testing.T) {
+	calls, err := ParseWireMockMappings([]byte(wireMockMappingsJSON))
+	if err != nil {
+		t.Fatalf("parse wiremock mappings, unexpected error: %s", err)
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("wrong calls count, expected 3, actual %d", len(calls))
+	}
+
+	if calls[1].Delay != time.Millisecond {
+		t.Errorf("wrong delay, expected %s, actual %s", time.Millisecond, calls[1].Delay)
+	}
+
+	responseHeader := make(http.Header)
+	responseHeader.Set("Content-Type", "application/json")
+	responseHeader.Add("X-Tags", "a")
+	responseHeader.Add("X-Tags", "b")
+
+	requestHeader := make(http.Header)
+	requestHeader.Set("Content-Type", "application/json")
+
+	runTransportTests(t,
+		&transportTest{
+			Name:         "wiremock mappings",
+			TestReporter: ExpectSuccessTestReporter,
+			Calls:        SequenceCalls(calls...),
+			Execute: doMany(
+				do(
+					request{
+						method: http.MethodPost,
+						target: "/users?dry=false",
+						body:   strings.NewReader(`{"age":10,  "name":"dima"}`),
+						header: requestHeader,
+					},
+					Response{
+						StatusCode: http.StatusCreated,
+						Body:       RawBody(`{"id": 1}`),
+						Header:     responseHeader,
+					},
+				),
+				do(
+					request{
+						method: http.MethodGet,
+						target: "/users/1?fields=name",
+						body:   strings.NewReader("ignored body"),
+					},
+					Response{
+						StatusCode: http.StatusOK,
+						Body:       RawBody("GET /users/1 1 name"),
+					},
+				),
+				doExpectError(
+					request{
+						method: http.MethodDelete,
+						target: "/users/1",
+					},
+					syscall.ECONNRESET,
+				),
+			),
+		},
+	)
+}
+
+func Test_ParseWireMockMappings_SingleMapping(t *testing.T) {
+	calls, err := ParseWireMockMappings([]byte(`{"request":{"method":"GET","urlPath":"/ping"},"response":{"body":"pong"}}`))
+	if err != nil {
+		t.Fatalf("parse wiremock mapping, unexpected error: %s", err)
+	}
+
+	runTransportTests(t,
+		&transportTest{
+			Name:         "single mapping",
+			TestReporter: ExpectSuccessTestReporter,
+			Calls:        SequenceCalls(calls...),
+			Execute: do(
+				request{method: http.MethodGet, target: "/ping"},
+				Response{StatusCode: http.StatusOK, Body: RawBody("pong")},
+			),
+		},
+	)
+}
+
+func Test_ParseWireMockMappings_Unsupported(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Mappings string
+		Error    string
+	}{
+		{
+			Name:     "no request",
+			Mappings: `{"mappings":[{"response":{"status":200}}]}`,
+			Error:    "convert wiremock mapping 0 , request is not specified",
+		},
+		{
+			Name:     "url pattern",
+			Mappings: `{"mappings":[{"name":"users","request":{"urlPattern":"/users/.*"}}]}`,
+			Error:    "convert wiremock mapping 0 users, request, urlPattern and urlPathPattern are not supported",
+		},
+		{
+			Name:     "contains header matcher",
+			Mappings: `{"request":{"method":"GET","headers":{"Accept":{"contains":"json"}}}}`,
+			Error:    "convert wiremock mapping 0 , request, header Accept, unsupported matcher contains, only equalTo is supported",
+		},
+		{
+			Name:     "absent query matcher",
+			Mappings: `{"request":{"method":"GET","queryParameters":{"debug":{"absent":true}}}}`,
+			Error:    "convert wiremock mapping 0 , request, query parameter debug, unsupported matcher absent, only equalTo is supported",
+		},
+		{
+			Name:     "matches body pattern",
+			Mappings: `{"request":{"method":"POST","bodyPatterns":[{"matches":".*"}]}}`,
+			Error:    "convert wiremock mapping 0 , request, body pattern, unsupported matcher matches, only one of equalTo, equalToJson and matchesJsonSchema is supported",
+		},
+		{
+			Name:     "equalToJson options",
+			Mappings: `{"request":{"method":"POST","bodyPatterns":[{"equalToJson":{"id":1},"ignoreExtraElements":true}]}}`,
+			Error:    "convert wiremock mapping 0 , request, body pattern, unsupported matcher equalToJson,ignoreExtraElements, only one of equalTo, equalToJson and matchesJsonSchema is supported",
+		},
+		{
+			Name:     "cookies and basic auth",
+			Mappings: `{"request":{"method":"GET","cookies":{"session":{"equalTo":"1"}},"basicAuthCredentials":{"username":"u"}}}`,
+			Error:    "convert wiremock mapping 0 , request, unsupported matchers basicAuthCredentials,cookies",
+		},
+		{
+			Name:     "unknown fault",
+			Mappings: `{"request":{"method":"GET"},"response":{"fault":"BOOM"}}`,
+			Error:    "convert wiremock mapping 0 , unsupported fault BOOM",
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.Name, func(t *testing.T) {
+			_, err := ParseWireMockMappings([]byte(tst.Mappings))
+			if err == nil || err.Error() != tst.Error {
+				t.Errorf("wrong error, expected %s, actual %v", tst.Error, err)
+			}
+		})
+	}
+}
+
+func Test_LoadWireMockMappings(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile := func(name, content string) {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("write %s, %s", name, err)
+		}
+	}
+
+	writeFile("b.json", `{"request":{"method":"GET","urlPath":"/second"},"response":{"body":"2"}}`)
+	writeFile("a.json", `{"request":{"method":"GET","urlPath":"/first"},"response":{"body":"1"}}`)
+	writeFile("readme.txt", `not a mapping`)
+
+	calls, err := LoadWireMockMappings(dir)
+	if err != nil {
+		t.Fatalf("load wiremock mappings, unexpected error: %s", err)
+	}
+
+	runTransportTests(t,
+		&transportTest{
+			Name:         "directory mappings in lexical order",
+			TestReporter: ExpectSuccessTestReporter,
+			Calls:        SequenceCalls(calls...),
+			Execute: doMany(
+				do(request{method: http.MethodGet, target: "/first"}, Response{StatusCode: http.StatusOK, Body: RawBody("1")}),
+				do(request{method: http.MethodGet, target: "/second"}, Response{StatusCode: http.StatusOK, Body: RawBody("2")}),
+			),
+		},
+	)
+
+	_, err = LoadWireMockMappings(filepath.Join(dir, "missing"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("wrong error, expected %s, actual %v", os.ErrNotExist, err)
+	}
+}
+
+func Test_jsonEqualBody(t *testing.T) {
+	tr := ExpectFailureTestReporter(
+		[]testReporterCall{
+			{
+				format: "json body not equal, expected %s actual %s",
+				args:   []any{`{"name":"dima"}`, `{"name":"amidman"}`},
+			},
+		},
+		nil,
+	)(t)
+
+	CompareBody(tr, strings.NewReader(`{"name": "amidman"}`), jsonEqualBody(`{"name": "dima"}`))
+}
+
+func Test_ParseWireMockMappings_ExactURL(t *testing.T) {
+	calls, err := ParseWireMockMappings([]byte(`{"mappings":[
+		{"request":{"method":"GET","url":"/users?page=1"},"response":{"status":200}},
+		{"request":{"method":"GET","url":"/users"},"response":{"status":200}},
+		{"request":{"method":"GET","urlPath":"/users","queryParameters":{"page":{"equalTo":"2"}}},"response":{"status":200}}
+	]}`))
+	if err != nil {
+		t.Fatalf("parse wiremock mappings, unexpected error: %s", err)
+	}
+
+	runTransportTests(t,
+		&transportTest{
+			Name: "url matches path and query exactly",
+			TestReporter: ExpectFailureTestReporter(
+				[]testReporterCall{
+					{
+						format: "1 call, wrong url.RawQuery, expected %s, actual %s",
+						args:   []any{"page=1", "page=1&debug=true"},
+					},
+					{
+						format: "2 call, wrong url.RawQuery, expected no query, actual %s",
+						args:   []any{"page=1"},
+					},
+				},
+				nil,
+			),
+			Calls: SequenceCalls(calls...),
+			Execute: doMany(
+				do(
+					request{method: http.MethodGet, target: "/users?page=1&debug=true"},
+					Response{StatusCode: http.StatusOK},
+				),
+				do(
+					request{method: http.MethodGet, target: "/users?page=1"},
+					Response{StatusCode: http.StatusOK},
+				),
+				do(
+					request{method: http.MethodGet, target: "/users?page=2&debug=true"},
+					Response{StatusCode: http.StatusOK},
+				),
+			),
+		},
+	)
+}